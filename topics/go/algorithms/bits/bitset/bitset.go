@@ -0,0 +1,188 @@
+// Package bitset implements a set of non-negative integers stored as bits.
+// Integer i is in the set when bit i%64 of word i/64 is 1, so membership
+// checks are a shift and a mask, and the set operations work on 64 values
+// at a time using the bitwise operators.
+package bitset
+
+import (
+	"math/bits"
+)
+
+// wordSize is the number of bits in every word of the set.
+const wordSize = 64
+
+// BitSet is a set of non-negative integers. The zero value is an empty set
+// ready to use. The set grows as larger integers are added.
+type BitSet struct {
+	words []uint64
+}
+
+// New returns an empty set with room for the integers below size without
+// having to grow.
+func New(size uint) *BitSet {
+	return &BitSet{
+		words: make([]uint64, (size+wordSize-1)/wordSize),
+	}
+}
+
+// Set adds the integer to the set.
+func (b *BitSet) Set(i uint) {
+	w := i / wordSize
+	if w >= uint(len(b.words)) {
+		b.grow(w + 1)
+	}
+
+	// 1 << (i % 64) is a word with only the bit for i set.
+	b.words[w] |= 1 << (i % wordSize)
+}
+
+// Clear removes the integer from the set.
+func (b *BitSet) Clear(i uint) {
+	w := i / wordSize
+	if w >= uint(len(b.words)) {
+		return
+	}
+
+	// &^ is AND NOT, it turns off the bit for i and keeps the rest.
+	b.words[w] &^= 1 << (i % wordSize)
+}
+
+// Test reports whether the integer is in the set.
+func (b *BitSet) Test(i uint) bool {
+	w := i / wordSize
+	if w >= uint(len(b.words)) {
+		return false
+	}
+
+	return b.words[w]&(1<<(i%wordSize)) != 0
+}
+
+// Count returns the number of integers in the set.
+func (b *BitSet) Count() int {
+	var n int
+	for _, w := range b.words {
+		n += bits.OnesCount64(w)
+	}
+
+	return n
+}
+
+// Clone returns a new set with the same integers.
+func (b *BitSet) Clone() *BitSet {
+	c := BitSet{words: make([]uint64, len(b.words))}
+	copy(c.words, b.words)
+
+	return &c
+}
+
+// Union returns a new set with the integers found in either set.
+func (b *BitSet) Union(o *BitSet) *BitSet {
+	long, short := b.words, o.words
+	if len(long) < len(short) {
+		long, short = short, long
+	}
+
+	u := BitSet{words: make([]uint64, len(long))}
+	copy(u.words, long)
+	for i, w := range short {
+		u.words[i] |= w
+	}
+
+	return &u
+}
+
+// Intersect returns a new set with the integers found in both sets.
+func (b *BitSet) Intersect(o *BitSet) *BitSet {
+	n := len(b.words)
+	if len(o.words) < n {
+		n = len(o.words)
+	}
+
+	in := BitSet{words: make([]uint64, n)}
+	for i := range in.words {
+		in.words[i] = b.words[i] & o.words[i]
+	}
+
+	return &in
+}
+
+// Equal reports whether both sets hold the same integers.
+func (b *BitSet) Equal(o *BitSet) bool {
+	long, short := b.words, o.words
+	if len(long) < len(short) {
+		long, short = short, long
+	}
+
+	for i, w := range long {
+		var s uint64
+		if i < len(short) {
+			s = short[i]
+		}
+		if w != s {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Next returns the smallest integer in the set that is not less than i.
+// It returns false when there is none. Next can be used to iterate over the
+// set:
+//
+//	for i, ok := b.Next(0); ok; i, ok = b.Next(i + 1) {
+//	}
+func (b *BitSet) Next(i uint) (uint, bool) {
+	w := i / wordSize
+	if w >= uint(len(b.words)) {
+		return 0, false
+	}
+
+	// Drop the bits below i in the first word. The number of trailing
+	// zeros is then the distance to the next integer in the set.
+	word := b.words[w] >> (i % wordSize)
+	if word != 0 {
+		return i + uint(bits.TrailingZeros64(word)), true
+	}
+
+	for w++; w < uint(len(b.words)); w++ {
+		if b.words[w] != 0 {
+			return w*wordSize + uint(bits.TrailingZeros64(b.words[w])), true
+		}
+	}
+
+	return 0, false
+}
+
+// Each calls fn for every integer in the set in increasing order until fn
+// returns false.
+func (b *BitSet) Each(fn func(i uint) bool) {
+	for w, word := range b.words {
+
+		// Remove the lowest set bit after every call until the word is
+		// empty, which skips over the bits that are not set.
+		for word != 0 {
+			if !fn(uint(w)*wordSize + uint(bits.TrailingZeros64(word))) {
+				return
+			}
+			word &= word - 1
+		}
+	}
+}
+
+// grow extends the set to hold the specified number of words.
+func (b *BitSet) grow(words uint) {
+	if words <= uint(cap(b.words)) {
+		b.words = b.words[:words]
+		return
+	}
+
+	// Double the capacity to keep adding increasing integers cheap.
+	c := 2 * uint(cap(b.words))
+	if c < words {
+		c = words
+	}
+	w := make([]uint64, words, c)
+	copy(w, b.words)
+	b.words = w
+}
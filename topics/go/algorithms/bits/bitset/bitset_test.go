@@ -0,0 +1,141 @@
+package bitset_test
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/ardanlabs/gotraining/topics/go/algorithms/bits/bitset"
+)
+
+const succeed = "\u2713"
+const failed = "\u2717"
+
+// fromMap builds a set and the map holding the same integers.
+func fromMap(n int, max uint) (*bitset.BitSet, map[uint]bool) {
+	var b bitset.BitSet
+	m := make(map[uint]bool)
+	for i := 0; i < n; i++ {
+		v := uint(rand.Intn(int(max)))
+		b.Set(v)
+		m[v] = true
+	}
+	return &b, m
+}
+
+// members returns the integers in the set using Each.
+func members(b *bitset.BitSet) []uint {
+	var list []uint
+	b.Each(func(i uint) bool {
+		list = append(list, i)
+		return true
+	})
+	return list
+}
+
+func TestBitSet(t *testing.T) {
+	t.Log("Given the need to test the bitset functionality.")
+	{
+		t.Logf("\tTest 0:\tWhen setting, clearing and testing integers.")
+		{
+			b := bitset.New(10)
+			for _, i := range []uint{0, 1, 63, 64, 65, 1000} {
+				b.Set(i)
+			}
+			b.Clear(1)
+			b.Clear(5000)
+
+			for _, i := range []uint{0, 63, 64, 65, 1000} {
+				if !b.Test(i) {
+					t.Fatalf("\t%s\tTest 0:\tShould find %d in the set.", failed, i)
+				}
+			}
+			for _, i := range []uint{1, 2, 62, 66, 999, 1001, 5000} {
+				if b.Test(i) {
+					t.Fatalf("\t%s\tTest 0:\tShould not find %d in the set.", failed, i)
+				}
+			}
+			t.Logf("\t%s\tTest 0:\tShould find only the integers that were set.", succeed)
+
+			if n := b.Count(); n != 5 {
+				t.Fatalf("\t%s\tTest 0:\tShould count 5 integers : %d", failed, n)
+			}
+			t.Logf("\t%s\tTest 0:\tShould count 5 integers.", succeed)
+		}
+
+		t.Logf("\tTest 1:\tWhen iterating over the set.")
+		{
+			b, m := fromMap(500, 10000)
+
+			var exp []uint
+			for v := range m {
+				exp = append(exp, v)
+			}
+			sort.Slice(exp, func(i, j int) bool { return exp[i] < exp[j] })
+
+			got := members(b)
+			if len(got) != len(exp) {
+				t.Fatalf("\t%s\tTest 1:\tShould get %d integers : %d", failed, len(exp), len(got))
+			}
+			for i := range exp {
+				if got[i] != exp[i] {
+					t.Fatalf("\t%s\tTest 1:\tShould get the integers in order : %d, Expected %d", failed, got[i], exp[i])
+				}
+			}
+			t.Logf("\t%s\tTest 1:\tShould get every integer in order with Each.", succeed)
+
+			var i int
+			for v, ok := b.Next(0); ok; v, ok = b.Next(v + 1) {
+				if v != exp[i] {
+					t.Fatalf("\t%s\tTest 1:\tShould get the integers in order : %d, Expected %d", failed, v, exp[i])
+				}
+				i++
+			}
+			if i != len(exp) {
+				t.Fatalf("\t%s\tTest 1:\tShould get %d integers : %d", failed, len(exp), i)
+			}
+			t.Logf("\t%s\tTest 1:\tShould get every integer in order with Next.", succeed)
+		}
+
+		t.Logf("\tTest 2:\tWhen combining sets.")
+		{
+			a, ma := fromMap(300, 2000)
+			b, mb := fromMap(300, 5000)
+
+			union, inter := a.Union(b), a.Intersect(b)
+			for v := uint(0); v < 6000; v++ {
+				if union.Test(v) != (ma[v] || mb[v]) {
+					t.Fatalf("\t%s\tTest 2:\tShould find %d in the union only if it is in either set.", failed, v)
+				}
+				if inter.Test(v) != (ma[v] && mb[v]) {
+					t.Fatalf("\t%s\tTest 2:\tShould find %d in the intersection only if it is in both sets.", failed, v)
+				}
+			}
+			t.Logf("\t%s\tTest 2:\tShould get the union and intersection.", succeed)
+
+			if !union.Equal(b.Union(a)) || !inter.Equal(b.Intersect(a)) || union.Equal(inter) {
+				t.Fatalf("\t%s\tTest 2:\tShould compare sets by their integers.", failed)
+			}
+			t.Logf("\t%s\tTest 2:\tShould compare sets by their integers.", succeed)
+		}
+	}
+}
+
+var count int
+
+func BenchmarkCount(b *testing.B) {
+	s, _ := fromMap(10000, 1<<20)
+	for i := 0; i < b.N; i++ {
+		count = s.Count()
+	}
+}
+
+func BenchmarkEach(b *testing.B) {
+	s, _ := fromMap(10000, 1<<20)
+	for i := 0; i < b.N; i++ {
+		s.Each(func(v uint) bool {
+			count++
+			return true
+		})
+	}
+}
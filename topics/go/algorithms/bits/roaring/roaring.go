@@ -0,0 +1,413 @@
+// Package roaring implements a compressed set of 32 bit integers based on
+// Roaring bitmaps. A plain bitset needs 512MB to hold any 32 bit integer,
+// even when only a few integers are in the set.
+//
+// The integers are grouped into chunks by their high 16 bits, and only the
+// chunks that hold integers are stored. Each chunk holds its low 16 bits in
+// one of two containers, whichever is smaller:
+//
+//	array:  A sorted []uint16, used for up to 4096 integers (8KB max).
+//	bitmap: A bitset of 65536 bits, which always takes 8KB.
+//
+// https://roaringbitmap.org/about/
+package roaring
+
+import (
+	"sort"
+
+	"github.com/ardanlabs/gotraining/topics/go/algorithms/bits/bitset"
+)
+
+// arrayMax is the most integers an array container holds. Above it, a
+// bitmap container takes less memory than 2 bytes for every integer.
+const arrayMax = 4096
+
+// containerBits is the number of integers every container covers.
+const containerBits = 1 << 16
+
+// Bitmap is a set of 32 bit integers. The zero value is an empty set ready
+// to use.
+type Bitmap struct {
+	keys       []uint16
+	containers []container
+}
+
+// New returns an empty set.
+func New() *Bitmap {
+	return &Bitmap{}
+}
+
+// Add adds the integer to the set.
+func (b *Bitmap) Add(x uint32) {
+	key, low := split(x)
+
+	idx, found := b.find(key)
+	if !found {
+		b.keys = append(b.keys, 0)
+		copy(b.keys[idx+1:], b.keys[idx:])
+		b.keys[idx] = key
+
+		b.containers = append(b.containers, nil)
+		copy(b.containers[idx+1:], b.containers[idx:])
+		b.containers[idx] = array(nil)
+	}
+
+	b.containers[idx] = b.containers[idx].add(low)
+}
+
+// Remove removes the integer from the set.
+func (b *Bitmap) Remove(x uint32) {
+	key, low := split(x)
+
+	idx, found := b.find(key)
+	if !found {
+		return
+	}
+
+	c := b.containers[idx].remove(low)
+
+	// Empty containers are not kept.
+	if c.count() == 0 {
+		b.keys = append(b.keys[:idx], b.keys[idx+1:]...)
+		b.containers = append(b.containers[:idx], b.containers[idx+1:]...)
+		return
+	}
+
+	b.containers[idx] = c
+}
+
+// Contains reports whether the integer is in the set.
+func (b *Bitmap) Contains(x uint32) bool {
+	key, low := split(x)
+
+	idx, found := b.find(key)
+	if !found {
+		return false
+	}
+
+	return b.containers[idx].contains(low)
+}
+
+// Count returns the number of integers in the set.
+func (b *Bitmap) Count() int {
+	var n int
+	for _, c := range b.containers {
+		n += c.count()
+	}
+
+	return n
+}
+
+// Each calls fn for every integer in the set in increasing order until fn
+// returns false.
+func (b *Bitmap) Each(fn func(x uint32) bool) {
+	for i, c := range b.containers {
+		if !c.each(uint32(b.keys[i])<<16, fn) {
+			return
+		}
+	}
+}
+
+// Union returns a new set with the integers found in either set.
+func (b *Bitmap) Union(o *Bitmap) *Bitmap {
+	var u Bitmap
+
+	// Merge the sorted keys, combining the containers with the same key.
+	i, j := 0, 0
+	for i < len(b.keys) || j < len(o.keys) {
+		switch {
+		case j == len(o.keys) || (i < len(b.keys) && b.keys[i] < o.keys[j]):
+			u.keys = append(u.keys, b.keys[i])
+			u.containers = append(u.containers, b.containers[i].clone())
+			i++
+		case i == len(b.keys) || o.keys[j] < b.keys[i]:
+			u.keys = append(u.keys, o.keys[j])
+			u.containers = append(u.containers, o.containers[j].clone())
+			j++
+		default:
+			u.keys = append(u.keys, b.keys[i])
+			u.containers = append(u.containers, union(b.containers[i], o.containers[j]))
+			i++
+			j++
+		}
+	}
+
+	return &u
+}
+
+// Intersect returns a new set with the integers found in both sets.
+func (b *Bitmap) Intersect(o *Bitmap) *Bitmap {
+	var in Bitmap
+
+	// Only the containers with a key in both sets can share integers.
+	i, j := 0, 0
+	for i < len(b.keys) && j < len(o.keys) {
+		switch {
+		case b.keys[i] < o.keys[j]:
+			i++
+		case o.keys[j] < b.keys[i]:
+			j++
+		default:
+			if c := intersect(b.containers[i], o.containers[j]); c.count() > 0 {
+				in.keys = append(in.keys, b.keys[i])
+				in.containers = append(in.containers, c)
+			}
+			i++
+			j++
+		}
+	}
+
+	return &in
+}
+
+// SizeBytes returns the approximate number of bytes used by the containers.
+func (b *Bitmap) SizeBytes() int {
+	n := 2 * len(b.keys)
+	for _, c := range b.containers {
+		n += c.sizeBytes()
+	}
+
+	return n
+}
+
+// find returns the index of the key, or where it would need to be inserted
+// if it is not in the set.
+func (b *Bitmap) find(key uint16) (int, bool) {
+	idx := sort.Search(len(b.keys), func(i int) bool { return b.keys[i] >= key })
+	return idx, idx < len(b.keys) && b.keys[idx] == key
+}
+
+// split returns the high 16 bits that select the container and the low 16
+// bits stored in it.
+func split(x uint32) (uint16, uint16) {
+	return uint16(x >> 16), uint16(x)
+}
+
+// =============================================================================
+
+// container holds the low 16 bits of the integers that share the same high
+// 16 bits. The methods that change the container return the container to
+// use from then on, which can be of a different type.
+type container interface {
+	add(low uint16) container
+	remove(low uint16) container
+	contains(low uint16) bool
+	count() int
+	each(high uint32, fn func(x uint32) bool) bool
+	clone() container
+	sizeBytes() int
+}
+
+// union returns a new container with the integers in either container.
+func union(a, b container) container {
+	if aa, ok := a.(array); ok {
+		if ab, ok := b.(array); ok {
+			return mergeArrays(aa, ab)
+		}
+	}
+
+	bm := toBitmap(a)
+	b.each(0, func(x uint32) bool {
+		bm.add(uint16(x))
+		return true
+	})
+
+	return bm
+}
+
+// intersect returns a new container with the integers in both containers.
+func intersect(a, b container) container {
+
+	// Keep the integers of an array found in the other container.
+	if _, ok := b.(array); ok {
+		a, b = b, a
+	}
+	if aa, ok := a.(array); ok {
+		var in array
+		for _, low := range aa {
+			if b.contains(low) {
+				in = append(in, low)
+			}
+		}
+		return in
+	}
+
+	// Both are bitmaps, so they can be combined 64 integers at a time.
+	ba, bb := a.(*bitmap), b.(*bitmap)
+	bm := bitmap{bits: ba.bits.Intersect(bb.bits)}
+	bm.n = bm.bits.Count()
+	if bm.n <= arrayMax {
+		return toArray(&bm)
+	}
+
+	return &bm
+}
+
+// mergeArrays returns the union of two array containers, which becomes a
+// bitmap when there are too many integers.
+func mergeArrays(a, b array) container {
+	m := make(array, 0, len(a)+len(b))
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case j == len(b) || (i < len(a) && a[i] < b[j]):
+			m = append(m, a[i])
+			i++
+		case i == len(a) || b[j] < a[i]:
+			m = append(m, b[j])
+			j++
+		default:
+			m = append(m, a[i])
+			i++
+			j++
+		}
+	}
+
+	if len(m) > arrayMax {
+		return toBitmap(m)
+	}
+
+	return m
+}
+
+// toBitmap returns a new bitmap container with the integers of c.
+func toBitmap(c container) *bitmap {
+	bm := bitmap{bits: bitset.New(containerBits)}
+	c.each(0, func(x uint32) bool {
+		bm.bits.Set(uint(x))
+		return true
+	})
+	bm.n = c.count()
+
+	return &bm
+}
+
+// toArray returns a new array container with the integers of c.
+func toArray(c container) array {
+	a := make(array, 0, c.count())
+	c.each(0, func(x uint32) bool {
+		a = append(a, uint16(x))
+		return true
+	})
+
+	return a
+}
+
+// =============================================================================
+
+// array is a container that holds the integers in a sorted slice.
+type array []uint16
+
+func (a array) add(low uint16) container {
+	idx := sort.Search(len(a), func(i int) bool { return a[i] >= low })
+	if idx < len(a) && a[idx] == low {
+		return a
+	}
+
+	a = append(a, 0)
+	copy(a[idx+1:], a[idx:])
+	a[idx] = low
+
+	if len(a) > arrayMax {
+		return toBitmap(a)
+	}
+
+	return a
+}
+
+func (a array) remove(low uint16) container {
+	idx := sort.Search(len(a), func(i int) bool { return a[i] >= low })
+	if idx == len(a) || a[idx] != low {
+		return a
+	}
+
+	return append(a[:idx], a[idx+1:]...)
+}
+
+func (a array) contains(low uint16) bool {
+	idx := sort.Search(len(a), func(i int) bool { return a[i] >= low })
+	return idx < len(a) && a[idx] == low
+}
+
+func (a array) count() int {
+	return len(a)
+}
+
+func (a array) each(high uint32, fn func(x uint32) bool) bool {
+	for _, low := range a {
+		if !fn(high | uint32(low)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (a array) clone() container {
+	return append(array(nil), a...)
+}
+
+func (a array) sizeBytes() int {
+	return 2 * len(a)
+}
+
+// =============================================================================
+
+// bitmap is a container that holds the integers in a bitset with a bit for
+// every possible integer.
+type bitmap struct {
+	bits *bitset.BitSet
+	n    int
+}
+
+func (bm *bitmap) add(low uint16) container {
+	if !bm.bits.Test(uint(low)) {
+		bm.bits.Set(uint(low))
+		bm.n++
+	}
+
+	return bm
+}
+
+func (bm *bitmap) remove(low uint16) container {
+	if bm.bits.Test(uint(low)) {
+		bm.bits.Clear(uint(low))
+		bm.n--
+	}
+
+	if bm.n <= arrayMax {
+		return toArray(bm)
+	}
+
+	return bm
+}
+
+func (bm *bitmap) contains(low uint16) bool {
+	return bm.bits.Test(uint(low))
+}
+
+func (bm *bitmap) count() int {
+	return bm.n
+}
+
+func (bm *bitmap) each(high uint32, fn func(x uint32) bool) bool {
+	ok := true
+	bm.bits.Each(func(i uint) bool {
+		ok = fn(high | uint32(i))
+		return ok
+	})
+
+	return ok
+}
+
+func (bm *bitmap) clone() container {
+	return &bitmap{
+		bits: bm.bits.Clone(),
+		n:    bm.n,
+	}
+}
+
+func (bm *bitmap) sizeBytes() int {
+	return containerBits / 8
+}
@@ -0,0 +1,155 @@
+package roaring_test
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/ardanlabs/gotraining/topics/go/algorithms/bits/roaring"
+)
+
+const succeed = "\u2713"
+const failed = "\u2717"
+
+// generate builds a set and the map holding the same integers. The
+// integers are spread over a few chunks with different densities, so
+// both kinds of containers are used.
+func generate(n int, chunks []uint32) (*roaring.Bitmap, map[uint32]bool) {
+	b := roaring.New()
+	m := make(map[uint32]bool)
+	for i := 0; i < n; i++ {
+		chunk := chunks[rand.Intn(len(chunks))]
+		x := chunk<<16 | uint32(rand.Intn(1<<16))
+		b.Add(x)
+		m[x] = true
+	}
+	return b, m
+}
+
+// check compares the set with the map.
+func check(t *testing.T, testID int, b *roaring.Bitmap, m map[uint32]bool) {
+	t.Helper()
+
+	if b.Count() != len(m) {
+		t.Fatalf("\t%s\tTest %d:\tShould count %d integers : %d", failed, testID, len(m), b.Count())
+	}
+
+	var exp []uint32
+	for x := range m {
+		if !b.Contains(x) {
+			t.Fatalf("\t%s\tTest %d:\tShould find %d in the set.", failed, testID, x)
+		}
+		exp = append(exp, x)
+	}
+	sort.Slice(exp, func(i, j int) bool { return exp[i] < exp[j] })
+
+	var i int
+	b.Each(func(x uint32) bool {
+		if i >= len(exp) || x != exp[i] {
+			t.Fatalf("\t%s\tTest %d:\tShould get the integers in order : %d", failed, testID, x)
+		}
+		i++
+		return true
+	})
+}
+
+func TestBitmap(t *testing.T) {
+	t.Log("Given the need to test the roaring bitmap functionality.")
+	{
+		t.Logf("\tTest 0:\tWhen adding and removing integers.")
+		{
+			b := roaring.New()
+			for _, x := range []uint32{0, 1, 65535, 65536, 1 << 31, 1<<32 - 1} {
+				b.Add(x)
+			}
+			b.Add(1)
+			b.Remove(1)
+			b.Remove(12345)
+
+			for _, x := range []uint32{0, 65535, 65536, 1 << 31, 1<<32 - 1} {
+				if !b.Contains(x) {
+					t.Fatalf("\t%s\tTest 0:\tShould find %d in the set.", failed, x)
+				}
+			}
+			for _, x := range []uint32{1, 2, 65537, 1<<31 + 1} {
+				if b.Contains(x) {
+					t.Fatalf("\t%s\tTest 0:\tShould not find %d in the set.", failed, x)
+				}
+			}
+			if b.Count() != 5 {
+				t.Fatalf("\t%s\tTest 0:\tShould count 5 integers : %d", failed, b.Count())
+			}
+			t.Logf("\t%s\tTest 0:\tShould find only the integers that were added.", succeed)
+		}
+
+		t.Logf("\tTest 1:\tWhen a container grows and shrinks past the array limit.")
+		{
+			b, m := generate(20000, []uint32{7, 9000, 65535})
+			check(t, 1, b, m)
+			t.Logf("\t%s\tTest 1:\tShould hold every integer after growing.", succeed)
+
+			// Remove most of the integers so the bitmaps become arrays.
+			for x := range m {
+				if rand.Intn(10) > 0 {
+					b.Remove(x)
+					delete(m, x)
+				}
+			}
+			check(t, 1, b, m)
+			t.Logf("\t%s\tTest 1:\tShould hold every integer after shrinking.", succeed)
+		}
+
+		t.Logf("\tTest 2:\tWhen combining sets.")
+		{
+			a, ma := generate(30000, []uint32{1, 2, 3})
+			b, mb := generate(3000, []uint32{2, 3, 4})
+
+			union := make(map[uint32]bool)
+			inter := make(map[uint32]bool)
+			for x := range ma {
+				union[x] = true
+				if mb[x] {
+					inter[x] = true
+				}
+			}
+			for x := range mb {
+				union[x] = true
+			}
+
+			check(t, 2, a.Union(b), union)
+			check(t, 2, b.Union(a), union)
+			check(t, 2, a.Intersect(b), inter)
+			check(t, 2, b.Intersect(a), inter)
+			check(t, 2, a.Intersect(a), ma)
+			t.Logf("\t%s\tTest 2:\tShould get the union and intersection.", succeed)
+
+			// The sets used must not change.
+			check(t, 2, a, ma)
+			check(t, 2, b, mb)
+			t.Logf("\t%s\tTest 2:\tShould leave the original sets unchanged.", succeed)
+		}
+
+		t.Logf("\tTest 3:\tWhen holding a few integers spread over the range.")
+		{
+			b := roaring.New()
+			for i := uint32(0); i < 1000; i++ {
+				b.Add(i * 4000037)
+			}
+
+			// A plain bitset would need 512MB.
+			if size := b.SizeBytes(); size > 8000 {
+				t.Fatalf("\t%s\tTest 3:\tShould use a few kilobytes : %d bytes", failed, size)
+			}
+			t.Logf("\t%s\tTest 3:\tShould use a few kilobytes : %d bytes.", succeed, b.SizeBytes())
+		}
+	}
+}
+
+var found bool
+
+func BenchmarkContains(b *testing.B) {
+	bm, _ := generate(100000, []uint32{0, 1, 2, 3, 100, 1000})
+	for i := 0; i < b.N; i++ {
+		found = bm.Contains(uint32(i))
+	}
+}
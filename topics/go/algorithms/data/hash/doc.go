@@ -24,9 +24,19 @@ Hash table
        └─────────────┘
 
 
-- hashKey(key) returns a number between 0 to len(buckets)-1
+- hashKey(key) returns a number which is masked to be between 0 to
+  len(buckets)-1
 - We use a slice of entries as a bucket to handles cases where two or more keys
   are hashed to the same bucket
+- The number of buckets doubles when the average number of entries per bucket
+  goes over the load factor and halves when it drops under a quarter of it
+- When the table is resized, the entries are moved from the old buckets to the
+  new buckets a few buckets at a time on every Store and Delete, so a single
+  call never pays the full resize cost. Lookups check both sets of buckets
+  until the move is complete
+- RobinHood is an alternative table using open addressing, where every entry
+  is stored in a single slice of slots. See more at
+  https://en.wikipedia.org/wiki/Hash_table#Robin_Hood_hashing
 - See more at https://en.wikipedia.org/wiki/Hash_table
 */
 package hash
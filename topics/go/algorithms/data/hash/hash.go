@@ -4,150 +4,462 @@ package hash
 import (
 	"fmt"
 	"hash/maphash"
+	"math"
+	"reflect"
 )
 
-const numBuckets = 256
+const (
+	// minBuckets is the initial number of buckets and the smallest size
+	// the table will shrink to. It must be a power of two.
+	minBuckets = 8
 
-// An entry where we store key and value in the hash.
-type entry struct {
-	key   string
-	value int
+	// DefaultLoadFactor is the average number of entries per bucket
+	// that triggers the table to double in size.
+	DefaultLoadFactor = 4.0
+
+	// migrateBuckets is the smallest number of buckets moved from the
+	// old table to the new table on every Store and Delete during a
+	// resize.
+	migrateBuckets = 2
+)
+
+// An entry where we store key and value in the hash. The hash of the key
+// is kept so we don't need to recalculate it when the table is resized.
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+	hash  uint64
 }
 
 // Hash is a simple Hash table implementation.
-type Hash struct {
-	buckets [][]entry
-	hash    maphash.Hash
+type Hash[K comparable, V any] struct {
+	buckets    [][]entry[K, V]
+	old        [][]entry[K, V]
+	migrated   int
+	step       int // Buckets moved on every Store and Delete.
+	moved      int // Buckets moved so far, used by the tests.
+	count      int
+	loadFactor float64
+	seed       maphash.Seed
+}
+
+// New returns a new hash table using the default load factor.
+func New[K comparable, V any]() *Hash[K, V] {
+	return NewWithLoadFactor[K, V](DefaultLoadFactor)
+}
+
+// NewWithLoadFactor returns a new hash table that grows once the average
+// number of entries per bucket goes over the specified load factor and
+// shrinks once it drops under a quarter of it.
+func NewWithLoadFactor[K comparable, V any](loadFactor float64) *Hash[K, V] {
+	if loadFactor <= 0 || math.IsNaN(loadFactor) || math.IsInf(loadFactor, 0) {
+		loadFactor = DefaultLoadFactor
+	}
+
+	return &Hash[K, V]{
+		buckets:    make([][]entry[K, V], minBuckets),
+		step:       migrateStep(loadFactor),
+		loadFactor: loadFactor,
+		seed:       maphash.MakeSeed(),
+	}
 }
 
-// New returns a new hash table.
-func New() *Hash {
-	return &Hash{
-		buckets: make([][]entry, numBuckets),
+// migrateStep returns the number of buckets to move on every Store and
+// Delete so a resize finishes before the next one is needed. The next
+// resize comes soonest when the table shrinks twice in a row: the old
+// table has n buckets and the count only has to drop from loadFactor*n/4
+// to loadFactor*n/8, which takes loadFactor*n/8 deletes. Moving 8/loadFactor
+// buckets on every call keeps up with that.
+func migrateStep(loadFactor float64) int {
+	step := int(math.Ceil(8 / loadFactor))
+	if step < migrateBuckets {
+		step = migrateBuckets
 	}
+	return step
 }
 
 // Store adds a value in the hash table based on the key.
-func (h *Hash) Store(key string, value int) {
+func (h *Hash[K, V]) Store(key K, value V) {
+	h.storeHashed(key, value, h.hashKey(key))
+}
 
-	// For the specified key, identify what bucket in
-	// the slice we need to store the key/value inside of.
-	idx := h.hashKey(key)
+// storeHashed adds a value in the hash table based on the key and the
+// hash already calculated for it.
+func (h *Hash[K, V]) storeHashed(key K, value V, hash uint64) {
 
-	// Extract a copy of the bucket from the hash table.
-	bucket := h.buckets[idx]
+	// If the table is being resized, move a few more buckets
+	// over so a single call never pays the full resize cost.
+	h.migrate()
 
-	// Iterate over the indexes for the specified bucket.
-	for idx := range bucket {
+	// Find the entry if it already exists in either table.
+	if bucket, idx := h.find(key, hash); bucket != nil {
 
-		// Compare the keys and if there is a match replace the
-		// existing entry value for the new value.
-		if bucket[idx].key == key {
-			bucket[idx].value = value
-			return
-		}
+		// There is a match so replace the existing entry
+		// value for the new value.
+		bucket[idx].value = value
+		h.checkSize()
+		return
 	}
 
-	// This key does not exist, so add this new value.
-	h.buckets[idx] = append(bucket, entry{key, value})
+	// This key does not exist, so add this new value. New entries
+	// always go into the current table.
+	idx := bucketIndex(hash, len(h.buckets))
+	h.buckets[idx] = append(h.buckets[idx], entry[K, V]{key, value, hash})
+	h.count++
+
+	// Check if the table needs to grow.
+	h.checkSize()
 }
 
 // Retrieve extracts a value from the hash table based on the key.
-func (h *Hash) Retrieve(key string) (int, error) {
+func (h *Hash[K, V]) Retrieve(key K) (V, error) {
+	return h.retrieveHashed(key, h.hashKey(key))
+}
 
-	// For the specified key, identify what bucket in
-	// the slice we need to store the key/value inside of.
-	idx := h.hashKey(key)
+// retrieveHashed extracts a value from the hash table based on the key
+// and the hash already calculated for it.
+func (h *Hash[K, V]) retrieveHashed(key K, hash uint64) (V, error) {
+	if value, ok := h.lookupHashed(key, hash); ok {
+		return value, nil
+	}
 
-	// Iterate over the entries for the specified bucket.
-	for _, entry := range h.buckets[idx] {
+	// The key was not found so return the error.
+	var zero V
+	return zero, fmt.Errorf("%v not found", formatKey(key))
+}
 
-		// Compare the keys and if there is a match return
-		// the value associated with the key.
-		if entry.key == key {
-			return entry.value, nil
-		}
+// Delete deletes an entry from the hash table.
+func (h *Hash[K, V]) Delete(key K) error {
+	return h.deleteHashed(key, h.hashKey(key))
+}
+
+// deleteHashed deletes an entry from the hash table based on the key and
+// the hash already calculated for it.
+func (h *Hash[K, V]) deleteHashed(key K, hash uint64) error {
+
+	// If the table is being resized, move a few more buckets over.
+	h.migrate()
+
+	// For the specified key, check the old table first and then
+	// the current table.
+	if h.delete(h.old, key, hash) || h.delete(h.buckets, key, hash) {
+		h.count--
+
+		// Check if the table can shrink.
+		h.checkSize()
+		return nil
 	}
 
+	// A resize put off by a previous call may be ready to start.
+	h.checkSize()
+
 	// The key was not found so return the error.
-	return 0, fmt.Errorf("%q not found", key)
+	return fmt.Errorf("%v not found", formatKey(key))
 }
 
-// Delete deletes an entry from the hash table.
-func (h *Hash) Delete(key string) error {
+// Len return the number of elements in the hash.
+func (h *Hash[K, V]) Len() int {
+	return h.count
+}
+
+// Do calls fn on each key/value. If fn return false stops the iteration.
+func (h *Hash[K, V]) Do(fn func(key K, value V) bool) {
+	for _, table := range [][][]entry[K, V]{h.old, h.buckets} {
+		for _, bucket := range table {
+			for _, entry := range bucket {
+				if ok := fn(entry.key, entry.value); !ok {
+					return
+				}
+			}
+		}
+	}
+}
+
+// lookupHashed extracts a value from the hash table based on the key and
+// the hash already calculated for it. It reports whether the key was
+// found.
+func (h *Hash[K, V]) lookupHashed(key K, hash uint64) (V, bool) {
 
-	// For the specified key, identify what bucket in
-	// the slice we need to store the key/value inside of.
-	bucketIdx := h.hashKey(key)
+	// Find the entry for the specified key in either table.
+	if bucket, idx := h.find(key, hash); bucket != nil {
+		return bucket[idx].value, true
+	}
+
+	var zero V
+	return zero, false
+}
+
+// find locates the entry for the specified key. It returns the bucket
+// holding the entry and the index of the entry inside that bucket, or a
+// nil bucket when the key does not exist.
+func (h *Hash[K, V]) find(key K, hash uint64) ([]entry[K, V], int) {
+	for _, table := range [][][]entry[K, V]{h.old, h.buckets} {
+		if table == nil {
+			continue
+		}
+
+		// Identify what bucket in the table the key belongs to
+		// and iterate over the entries for that bucket.
+		bucket := table[bucketIndex(hash, len(table))]
+		for idx := range bucket {
+			if bucket[idx].hash == hash && bucket[idx].key == key {
+				return bucket, idx
+			}
+		}
+	}
 
-	// Extract a copy of the bucket from the hash table.
-	bucket := h.buckets[bucketIdx]
+	return nil, -1
+}
+
+// delete removes the entry for the specified key from the table. It
+// reports whether the key was found.
+func (h *Hash[K, V]) delete(table [][]entry[K, V], key K, hash uint64) bool {
+	if table == nil {
+		return false
+	}
+
+	// Identify what bucket in the table the key belongs to.
+	bucketIdx := bucketIndex(hash, len(table))
+	bucket := table[bucketIdx]
 
 	// Iterate over the entries for the specified bucket.
 	for entryIdx, entry := range bucket {
 
 		// Compare the keys and if there is a match remove
 		// the entry from the bucket.
-		if entry.key == key {
-
-			// Remove the entry based on its index position.
-			bucket = removeEntry(bucket, entryIdx)
+		if entry.hash == hash && entry.key == key {
 
-			// Replace the existing bucket for the new one.
-			h.buckets[bucketIdx] = bucket
-			return nil
+			// Remove the entry based on its index position and
+			// replace the existing bucket for the new one.
+			table[bucketIdx] = removeEntry(bucket, entryIdx)
+			return true
 		}
 	}
 
-	// The key was not found so return the error.
-	return fmt.Errorf("%q not found", key)
+	return false
 }
 
-// Len return the number of elements in the hash. This function currently
-// uses a linear traversal but could be improved with meta-data.
-func (h *Hash) Len() int {
-	sum := 0
-	for _, bucket := range h.buckets {
-		sum += len(bucket)
+// checkSize grows the table when the load factor is exceeded and shrinks
+// it when the count drops under a quarter of it. A resize in progress is
+// finished first, so a resize can be put off until a later Store or
+// Delete. The migrate step makes sure that is rare.
+func (h *Hash[K, V]) checkSize() {
+	if h.old != nil {
+		return
+	}
+
+	switch {
+	case float64(h.count) > h.loadFactor*float64(len(h.buckets)):
+		h.resize(2 * len(h.buckets))
+	case len(h.buckets) > minBuckets && float64(h.count) < h.loadFactor*float64(len(h.buckets))/4:
+		h.resize(len(h.buckets) / 2)
 	}
-	return sum
 }
 
-// Do calls fn on each key/value. If fn return false stops the iteration.
-func (h *Hash) Do(fn func(key string, value int) bool) {
-	for _, bucket := range h.buckets {
-		for _, entry := range bucket {
-			if ok := fn(entry.key, entry.value); !ok {
-				return
-			}
+// resize starts moving the entries into a new table of the specified
+// size. Entries are moved a few buckets at a time by migrate. There is
+// never more than one old table, so a resize only starts once the
+// previous one is finished.
+func (h *Hash[K, V]) resize(size int) {
+	h.old = h.buckets
+	h.buckets = make([][]entry[K, V], size)
+	h.migrated = 0
+}
+
+// migrate moves the next few buckets from the old table into the
+// current table. Once every bucket has been moved, the old table
+// is released.
+func (h *Hash[K, V]) migrate() {
+	if h.old == nil {
+		return
+	}
+
+	for i := 0; i < h.step && h.migrated < len(h.old); i++ {
+
+		// Move every entry in this bucket to the bucket it belongs
+		// to in the current table. We kept the hash of every key so
+		// there is no need to hash the key again.
+		for _, entry := range h.old[h.migrated] {
+			idx := bucketIndex(entry.hash, len(h.buckets))
+			h.buckets[idx] = append(h.buckets[idx], entry)
 		}
+
+		// Release the bucket so it is not found again by find.
+		h.old[h.migrated] = nil
+		h.migrated++
+		h.moved++
+	}
+
+	if h.migrated == len(h.old) {
+		h.old = nil
+		h.migrated = 0
 	}
 }
 
-// hashKey calculates the bucket index position to use
-// for the specified key.
-func (h *Hash) hashKey(key string) int {
+// hashKey calculates the hash value for the specified key.
+func (h *Hash[K, V]) hashKey(key K) uint64 {
+	return hashKey(h.seed, key)
+}
+
+// hashKey calculates the hash value for the specified key using the
+// specified seed. A local maphash is used so lookups don't modify the
+// hash table.
+func hashKey[K comparable](seed maphash.Seed, key K) uint64 {
+
+	// String keys are the most common so hash them directly.
+	if s, ok := any(key).(string); ok {
+		return maphash.String(seed, s)
+	}
+
+	var mh maphash.Hash
 
-	// Reset the maphash to initial state so we'll get the same
+	// Use the seed of the table so we'll get the same
 	// hash value for the same key.
-	h.hash.Reset()
+	mh.SetSeed(seed)
 
 	// Write the key to the maphash to update the current state.
-	// We don't check error value since WriteString never fails.
-	h.hash.WriteString(key)
+	// We don't check error value since writes never fail.
+	writeKey(&mh, key)
+
+	// Ask the maphash for its current state.
+	return mh.Sum64()
+}
 
-	// Ask the maphash for its current state which we will
-	// use to calculate the final bucket index.
-	n := h.hash.Sum64()
+// bucketIndex calculates the bucket index position to use for the
+// specified hash. Since the number of buckets is always a power of two,
+// we can mask the hash instead of using the modulus operator.
+func bucketIndex(hash uint64, numBuckets int) int {
+	return int(hash & uint64(numBuckets-1))
+}
 
-	// Use the modulu operator to return a value in the range
-	// of our bucket length defined by the const numBuckets.
-	return int(n % numBuckets)
+// writeKey writes the bytes representing the key to the maphash. Keys that
+// are equal must write the same bytes.
+func writeKey[K comparable](mh *maphash.Hash, key K) {
+	switch k := any(key).(type) {
+	case string:
+		mh.WriteString(k)
+	case int:
+		writeUint64(mh, uint64(k))
+	case int8:
+		writeUint64(mh, uint64(k))
+	case int16:
+		writeUint64(mh, uint64(k))
+	case int32:
+		writeUint64(mh, uint64(k))
+	case int64:
+		writeUint64(mh, uint64(k))
+	case uint:
+		writeUint64(mh, uint64(k))
+	case uint8:
+		writeUint64(mh, uint64(k))
+	case uint16:
+		writeUint64(mh, uint64(k))
+	case uint32:
+		writeUint64(mh, uint64(k))
+	case uint64:
+		writeUint64(mh, k)
+	case uintptr:
+		writeUint64(mh, uint64(k))
+	case float32:
+		writeFloat64(mh, float64(k))
+	case float64:
+		writeFloat64(mh, k)
+	case bool:
+		if k {
+			mh.WriteByte(1)
+			return
+		}
+		mh.WriteByte(0)
+	default:
+
+		// Any other comparable type is walked using reflection. This
+		// is slower but follows the rules of ==.
+		writeValue(mh, reflect.ValueOf(&key).Elem())
+	}
+}
+
+// writeValue writes the bytes representing the value to the maphash,
+// following the rules of == for each kind. Arrays and structs are
+// written element by element, and pointers and channels by address,
+// since they are equal when they point to the same thing.
+func writeValue(mh *maphash.Hash, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			mh.WriteByte(1)
+			return
+		}
+		mh.WriteByte(0)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeUint64(mh, uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeUint64(mh, v.Uint())
+	case reflect.Float32, reflect.Float64:
+		writeFloat64(mh, v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		writeFloat64(mh, real(c))
+		writeFloat64(mh, imag(c))
+	case reflect.String:
+		writeUint64(mh, uint64(v.Len()))
+		mh.WriteString(v.String())
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		writeUint64(mh, uint64(v.Pointer()))
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			writeValue(mh, v.Index(i))
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			writeValue(mh, v.Field(i))
+		}
+	case reflect.Interface:
+
+		// Values of different dynamic types are never equal, so only
+		// the value needs to be written.
+		if v.IsNil() {
+			mh.WriteByte(0)
+			return
+		}
+		mh.WriteByte(1)
+		writeValue(mh, v.Elem())
+	default:
+
+		// Only values that are not comparable, like slices inside an
+		// interface, get here and == panics for those anyway.
+		panic(fmt.Sprintf("hash: key of type %s is not comparable", v.Type()))
+	}
+}
+
+// writeUint64 writes the 8 bytes of the value to the maphash.
+func writeUint64(mh *maphash.Hash, v uint64) {
+	var buf [8]byte
+	for i := range buf {
+		buf[i] = byte(v >> (8 * i))
+	}
+	mh.Write(buf[:])
+}
+
+// writeFloat64 writes the bits of the value to the maphash. Since 0 and -0
+// are equal keys, they must produce the same bytes.
+func writeFloat64(mh *maphash.Hash, v float64) {
+	if v == 0 {
+		v = 0
+	}
+	writeUint64(mh, math.Float64bits(v))
+}
+
+// formatKey returns the representation of the key used in error messages.
+func formatKey[K comparable](key K) string {
+	if s, ok := any(key).(string); ok {
+		return fmt.Sprintf("%q", s)
+	}
+	return fmt.Sprintf("%v", key)
 }
 
 // removeEntry performs the physical act of removing an
 // entry from a bucket,
-func removeEntry(bucket []entry, idx int) []entry {
+func removeEntry[K comparable, V any](bucket []entry[K, V], idx int) []entry[K, V] {
 
 	// https://github.com/golang/go/wiki/SliceTricks
 	// Cut out the entry by taking all entries from
@@ -155,9 +467,11 @@ func removeEntry(bucket []entry, idx int) []entry {
 	// index specified.
 	copy(bucket[idx:], bucket[idx+1:])
 
-	// Set the proper length for the new slice since
-	// an entry was removed. The length needs to be
-	// reduced by 1.
+	// Clear the last entry so the key and value can be
+	// garbage collected, then set the proper length for the
+	// new slice since an entry was removed.
+	var zero entry[K, V]
+	bucket[len(bucket)-1] = zero
 	bucket = bucket[:len(bucket)-1]
 
 	// Look to see if the current allocation for the
@@ -168,7 +482,7 @@ func removeEntry(bucket []entry, idx int) []entry {
 
 // reduceAllocation looks to see if memory can be freed to
 // when a bucket has lost a percent of entries.
-func reduceAllocation(bucket []entry) []entry {
+func reduceAllocation[K comparable, V any](bucket []entry[K, V]) []entry[K, V] {
 
 	// If the bucket if more than ½ full, do nothing.
 	if cap(bucket) < 2*len(bucket) {
@@ -178,7 +492,7 @@ func reduceAllocation(bucket []entry) []entry {
 	// Free memory when the bucket shrinks a lot. If we don't do that,
 	// the underlying bucket array will stay in memory and will be in
 	// the biggest size the bucket ever was
-	newBucket := make([]entry, len(bucket))
+	newBucket := make([]entry[K, V], len(bucket))
 	copy(newBucket, bucket)
 	return newBucket
 }
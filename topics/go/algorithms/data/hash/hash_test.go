@@ -4,46 +4,52 @@
 
 	package hash
 
-	const numBuckets = 256
-
 	// An entry where we store key and value in the hash.
-	type entry struct {
-		key   string
-		value int
+	type entry[K comparable, V any] struct {
+		key   K
+		value V
+		hash  uint64
 	}
 
 	// Hash is a simple Hash table implementation.
-	type Hash struct {
-		buckets [][]entry
-		hash    maphash.Hash
+	type Hash[K comparable, V any] struct {
+		buckets    [][]entry[K, V]
+		old        [][]entry[K, V]
+		migrated   int
+		count      int
+		loadFactor float64
+		seed       maphash.Seed
 	}
 
-	// New returns a new hash table.
-	func New() *Hash {
-		return &Hash{
-			buckets: make([][]entry, numBuckets),
-		}
-	}
+	// New returns a new hash table using the default load factor.
+	func New[K comparable, V any]() *Hash[K, V]
+
+	// NewWithLoadFactor returns a new hash table that grows once the average
+	// number of entries per bucket goes over the specified load factor and
+	// shrinks once it drops under a quarter of it.
+	func NewWithLoadFactor[K comparable, V any](loadFactor float64) *Hash[K, V]
 
 	// Store adds a value in the hash table based on the key.
-	func (h *Hash) Store(key string, value int)
+	func (h *Hash[K, V]) Store(key K, value V)
 
 	// Retrieve extracts a value from the hash table based on the key.
-	func (h *Hash) Retrieve(key string) (int, error)
+	func (h *Hash[K, V]) Retrieve(key K) (V, error)
 
 	// Delete deletes an entry from the hash table.
-	func (h *Hash) Delete(key string) error
+	func (h *Hash[K, V]) Delete(key K) error
 
 	// Len return the number of elements in the hash.
-	func (h *Hash) Len() int
+	func (h *Hash[K, V]) Len() int
 
 	// Do calls fn on each key/value. If fn return false stops the iteration.
-	func (h *Hash) Do(fn func(key string, value int) bool)
+	func (h *Hash[K, V]) Do(fn func(key K, value V) bool)
 */
 
 package hash_test
 
 import (
+	"fmt"
+	"math"
 	"testing"
 
 	"github.com/ardanlabs/gotraining/topics/go/algorithms/data/hash"
@@ -58,7 +64,7 @@ func TestHash(t *testing.T) {
 		testID := 0
 		t.Logf("\tTest %d:\tWhen checking basic hashing operations", testID)
 		{
-			h := hash.New()
+			h := hash.New[string, int]()
 			k1, v1 := "key1", 1
 			k2, v2 := "key2", 2
 			h.Store(k1, v1)
@@ -128,3 +134,144 @@ func TestHash(t *testing.T) {
 		}
 	}
 }
+
+func TestHashResize(t *testing.T) {
+	t.Log("Given the need to test hash resizing.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen storing and deleting many keys", testID)
+		{
+			const n = 10_000
+
+			h := hash.NewWithLoadFactor[string, int](2)
+			for i := 0; i < n; i++ {
+				h.Store(fmt.Sprintf("key%d", i), i)
+			}
+
+			if h.Len() != n {
+				t.Errorf("\t%s\tTest %d:\tShould have the correct number of entries after growing.", failed, testID)
+				t.Fatalf("\t\tTest %d:\tGot %d, Expected %d", testID, h.Len(), n)
+			}
+			t.Logf("\t%s\tTest %d:\tShould have the correct number of entries after growing.", succeed, testID)
+
+			for i := 0; i < n; i++ {
+				key := fmt.Sprintf("key%d", i)
+				v, err := h.Retrieve(key)
+				if err != nil || v != i {
+					t.Errorf("\t%s\tTest %d:\tShould be able to retrieve every value after growing.", failed, testID)
+					t.Fatalf("\t\tTest %d:\tGot %d, %v, Expected %d for %q", testID, v, err, i, key)
+				}
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to retrieve every value after growing.", succeed, testID)
+
+			for i := 0; i < n-10; i++ {
+				if err := h.Delete(fmt.Sprintf("key%d", i)); err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to delete a value : %v", failed, testID, err)
+				}
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to delete a value.", succeed, testID)
+
+			if h.Len() != 10 {
+				t.Errorf("\t%s\tTest %d:\tShould have the correct number of entries after shrinking.", failed, testID)
+				t.Fatalf("\t\tTest %d:\tGot %d, Expected %d", testID, h.Len(), 10)
+			}
+			t.Logf("\t%s\tTest %d:\tShould have the correct number of entries after shrinking.", succeed, testID)
+
+			count := 0
+			h.Do(func(key string, value int) bool {
+				if value < n-10 {
+					t.Fatalf("\t%s\tTest %d:\tShould only see the remaining values : %s", failed, testID, key)
+				}
+				count++
+				return true
+			})
+			if count != 10 {
+				t.Errorf("\t%s\tTest %d:\tShould be able to run Do for the remaining values.", failed, testID)
+				t.Fatalf("\t\tTest %d:\tGot %d, Expected %d", testID, count, 10)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to run Do for the remaining values.", succeed, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen using keys that are not strings", testID)
+		{
+			type point struct{ x, y int }
+
+			h := hash.New[point, string]()
+			for i := 0; i < 100; i++ {
+				h.Store(point{i, -i}, fmt.Sprint(i))
+			}
+
+			for i := 0; i < 100; i++ {
+				v, err := h.Retrieve(point{i, -i})
+				if err != nil || v != fmt.Sprint(i) {
+					t.Errorf("\t%s\tTest %d:\tShould be able to retrieve every value.", failed, testID)
+					t.Fatalf("\t\tTest %d:\tGot %q, %v, Expected %q", testID, v, err, fmt.Sprint(i))
+				}
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to retrieve every value.", succeed, testID)
+
+			if _, err := h.Retrieve(point{1, 1}); err == nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to see the key does not exist.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to see the key does not exist.", succeed, testID)
+		}
+	}
+}
+
+func TestHashCompositeKeys(t *testing.T) {
+	t.Log("Given the need to use keys that follow the rules of ==.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen the value a pointer key points to changes", testID)
+		{
+			type node struct{ value int }
+
+			n := &node{value: 1}
+			h := hash.New[*node, string]()
+			h.Store(n, "node")
+
+			n.value = 2
+			if v, err := h.Retrieve(n); err != nil || v != "node" {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve the value by address : %q, %v", failed, testID, v, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to retrieve the value by address.", succeed, testID)
+
+			if _, err := h.Retrieve(&node{value: 2}); err == nil {
+				t.Fatalf("\t%s\tTest %d:\tShould not find a different pointer to an equal value.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould not find a different pointer to an equal value.", succeed, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen a struct key holds 0 or -0", testID)
+		{
+			type reading struct {
+				name  string
+				value float64
+				pos   [2]float32
+			}
+
+			negZero := math.Copysign(0, -1)
+			key := reading{name: "temp", value: 0, pos: [2]float32{0, 1}}
+			neg := reading{name: "temp", value: negZero, pos: [2]float32{float32(negZero), 1}}
+			if key != neg {
+				t.Fatalf("\t%s\tTest %d:\tShould have keys that are equal.", failed, testID)
+			}
+
+			h := hash.New[reading, int]()
+			h.Store(key, 1)
+
+			if v, err := h.Retrieve(neg); err != nil || v != 1 {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to retrieve the value with -0 : %d, %v", failed, testID, v, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to retrieve the value with -0.", succeed, testID)
+
+			h.Store(neg, 2)
+			if h.Len() != 1 {
+				t.Fatalf("\t%s\tTest %d:\tShould replace the value stored with 0 : %d entries", failed, testID, h.Len())
+			}
+			t.Logf("\t%s\tTest %d:\tShould replace the value stored with 0.", succeed, testID)
+		}
+	}
+}
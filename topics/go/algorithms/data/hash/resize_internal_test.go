@@ -0,0 +1,65 @@
+package hash
+
+import "testing"
+
+const succeed = "\u2713"
+const failed = "\u2717"
+
+// TestResizeWork validates a single Store or Delete never moves more than
+// a few buckets, no matter the load factor.
+func TestResizeWork(t *testing.T) {
+	const keys = 20000
+
+	t.Log("Given the need to spread the cost of a resize over many calls.")
+	{
+		for testID, loadFactor := range []float64{0.1, 0.25, 0.5, 1, DefaultLoadFactor} {
+			t.Logf("\tTest %d:\tWhen using a load factor of %v.", testID, loadFactor)
+			{
+				h := NewWithLoadFactor[int, int](loadFactor)
+
+				// check fails when the call moved more buckets than a
+				// single step.
+				check := func(op string, key int, moved int) {
+					t.Helper()
+					if n := h.moved - moved; n > h.step {
+						t.Fatalf("\t%s\tTest %d:\tShould move at most %d buckets on %s %d : %d", failed, testID, h.step, op, key, n)
+					}
+				}
+
+				for key := 0; key < keys; key++ {
+					moved := h.moved
+					h.Store(key, key)
+					check("Store", key, moved)
+				}
+				t.Logf("\t%s\tTest %d:\tShould move at most %d buckets on every Store.", succeed, testID, h.step)
+
+				if float64(h.count) > 2*loadFactor*float64(len(h.buckets)) {
+					t.Fatalf("\t%s\tTest %d:\tShould keep up with the load factor : %d entries in %d buckets", failed, testID, h.count, len(h.buckets))
+				}
+				t.Logf("\t%s\tTest %d:\tShould keep up with the load factor.", succeed, testID)
+
+				for key := 0; key < keys; key++ {
+					moved := h.moved
+					if err := h.Delete(key); err != nil {
+						t.Fatalf("\t%s\tTest %d:\tShould be able to delete %d : %v", failed, testID, key, err)
+					}
+					check("Delete", key, moved)
+				}
+				t.Logf("\t%s\tTest %d:\tShould move at most %d buckets on every Delete.", succeed, testID, h.step)
+
+				// A shrink put off by the last Delete starts on the
+				// calls that follow.
+				for i := 0; i < 100 && len(h.buckets) > minBuckets; i++ {
+					moved := h.moved
+					h.Delete(-1)
+					check("Delete", -1, moved)
+				}
+
+				if h.Len() != 0 || len(h.buckets) != minBuckets {
+					t.Fatalf("\t%s\tTest %d:\tShould shrink the table : %d entries in %d buckets", failed, testID, h.Len(), len(h.buckets))
+				}
+				t.Logf("\t%s\tTest %d:\tShould shrink the table.", succeed, testID)
+			}
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package hash
+
+import (
+	"fmt"
+	"hash/maphash"
+)
+
+const (
+	// minSlots is the initial number of slots and the smallest size the
+	// open addressing table will shrink to. It must be a power of two.
+	minSlots = 8
+
+	// maxLoadNum and maxLoadDen define the highest ratio of used slots,
+	// 7/8, before the open addressing table doubles in size.
+	maxLoadNum = 7
+	maxLoadDen = 8
+)
+
+// A slot where we store key and value in the open addressing table. The
+// dist field is the distance from the slot the key hashes to plus one, so
+// a zero value means the slot is empty.
+type slot[K comparable, V any] struct {
+	key   K
+	value V
+	hash  uint64
+	dist  int
+}
+
+// RobinHood is a hash table implementation using open addressing with
+// Robin Hood hashing. It provides the same method set as Hash but stores
+// every entry in a single slice of slots instead of a slice per bucket.
+//
+// On insert, an entry that is further away from the slot it hashes to
+// takes the place of an entry that is closer to its own slot, which keeps
+// probe sequences short. On delete, the entries following the removed one
+// are shifted back by one slot so no tombstones are needed.
+type RobinHood[K comparable, V any] struct {
+	slots []slot[K, V]
+	count int
+	seed  maphash.Seed
+}
+
+// NewRobinHood returns a new open addressing hash table.
+func NewRobinHood[K comparable, V any]() *RobinHood[K, V] {
+	return &RobinHood[K, V]{
+		slots: make([]slot[K, V], minSlots),
+		seed:  maphash.MakeSeed(),
+	}
+}
+
+// Store adds a value in the hash table based on the key.
+func (rh *RobinHood[K, V]) Store(key K, value V) {
+
+	// If the key already exists, replace the existing value
+	// for the new value.
+	hash := hashKey(rh.seed, key)
+	if idx := rh.find(key, hash); idx != -1 {
+		rh.slots[idx].value = value
+		return
+	}
+
+	// Check if the table needs to grow before adding the new entry.
+	if (rh.count+1)*maxLoadDen > len(rh.slots)*maxLoadNum {
+		rh.resize(2 * len(rh.slots))
+	}
+
+	rh.insert(slot[K, V]{key: key, value: value, hash: hash})
+	rh.count++
+}
+
+// Retrieve extracts a value from the hash table based on the key.
+func (rh *RobinHood[K, V]) Retrieve(key K) (V, error) {
+	if idx := rh.find(key, hashKey(rh.seed, key)); idx != -1 {
+		return rh.slots[idx].value, nil
+	}
+
+	// The key was not found so return the error.
+	var zero V
+	return zero, fmt.Errorf("%v not found", formatKey(key))
+}
+
+// Delete deletes an entry from the hash table.
+func (rh *RobinHood[K, V]) Delete(key K) error {
+	idx := rh.find(key, hashKey(rh.seed, key))
+	if idx == -1 {
+		return fmt.Errorf("%v not found", formatKey(key))
+	}
+
+	// Shift every following entry back by one slot until we find an
+	// empty slot or an entry that is already in the slot it hashes to.
+	mask := len(rh.slots) - 1
+	for {
+		next := (idx + 1) & mask
+		if rh.slots[next].dist <= 1 {
+			break
+		}
+
+		rh.slots[idx] = rh.slots[next]
+		rh.slots[idx].dist--
+		idx = next
+	}
+
+	// Clear the last slot so the key and value can be garbage collected.
+	var zero slot[K, V]
+	rh.slots[idx] = zero
+	rh.count--
+
+	// Check if the table can shrink.
+	if len(rh.slots) > minSlots && rh.count*maxLoadDen < len(rh.slots) {
+		rh.resize(len(rh.slots) / 2)
+	}
+
+	return nil
+}
+
+// Len return the number of elements in the hash.
+func (rh *RobinHood[K, V]) Len() int {
+	return rh.count
+}
+
+// Do calls fn on each key/value. If fn return false stops the iteration.
+func (rh *RobinHood[K, V]) Do(fn func(key K, value V) bool) {
+	for _, s := range rh.slots {
+		if s.dist == 0 {
+			continue
+		}
+		if ok := fn(s.key, s.value); !ok {
+			return
+		}
+	}
+}
+
+// find returns the index of the slot holding the specified key or -1
+// when the key does not exist.
+func (rh *RobinHood[K, V]) find(key K, hash uint64) int {
+	mask := len(rh.slots) - 1
+	idx := int(hash) & mask
+
+	for dist := 1; ; dist++ {
+		s := &rh.slots[idx]
+
+		// If the slot is empty or the entry in the slot is closer to
+		// its own slot than we are to ours, the key would have taken
+		// this slot on insert, so the key does not exist.
+		if s.dist < dist {
+			return -1
+		}
+
+		if s.hash == hash && s.key == key {
+			return idx
+		}
+
+		idx = (idx + 1) & mask
+	}
+}
+
+// insert places a new entry in the table. The caller must make sure the
+// key does not exist and that there is at least one empty slot.
+func (rh *RobinHood[K, V]) insert(entry slot[K, V]) {
+	mask := len(rh.slots) - 1
+	idx := int(entry.hash) & mask
+	entry.dist = 1
+
+	for {
+		s := &rh.slots[idx]
+
+		// We found an empty slot so place the entry here.
+		if s.dist == 0 {
+			*s = entry
+			return
+		}
+
+		// The entry in this slot is closer to its own slot than we are
+		// to ours. Take the slot from the rich and keep looking for a
+		// place for the entry we just displaced.
+		if s.dist < entry.dist {
+			*s, entry = entry, *s
+		}
+
+		idx = (idx + 1) & mask
+		entry.dist++
+	}
+}
+
+// resize moves every entry into a new slice of slots of the
+// specified size.
+func (rh *RobinHood[K, V]) resize(size int) {
+	slots := rh.slots
+	rh.slots = make([]slot[K, V], size)
+
+	// We kept the hash of every key so there is no need
+	// to hash the key again.
+	for _, s := range slots {
+		if s.dist != 0 {
+			rh.insert(s)
+		}
+	}
+}
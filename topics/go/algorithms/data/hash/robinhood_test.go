@@ -0,0 +1,181 @@
+package hash_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/ardanlabs/gotraining/topics/go/algorithms/data/hash"
+)
+
+// table is the method set shared by both hash table implementations.
+type table interface {
+	Store(key string, value int)
+	Retrieve(key string) (int, error)
+	Delete(key string) error
+	Len() int
+	Do(fn func(key string, value int) bool)
+}
+
+func TestRobinHood(t *testing.T) {
+	t.Log("Given the need to test open addressing hash functionality.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen running random operations against a map", testID)
+		{
+			const n = 5_000
+
+			rh := hash.NewRobinHood[string, int]()
+			m := make(map[string]int)
+			rnd := rand.New(rand.NewSource(42))
+
+			for i := 0; i < 10*n; i++ {
+				key := fmt.Sprintf("key%d", rnd.Intn(n))
+
+				switch rnd.Intn(3) {
+				case 0, 1:
+					rh.Store(key, i)
+					m[key] = i
+				case 2:
+					err := rh.Delete(key)
+					if _, exists := m[key]; exists != (err == nil) {
+						t.Fatalf("\t%s\tTest %d:\tShould only be able to delete existing keys : %q, %v", failed, testID, key, err)
+					}
+					delete(m, key)
+				}
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to store and delete values.", succeed, testID)
+
+			if rh.Len() != len(m) {
+				t.Errorf("\t%s\tTest %d:\tShould have the correct number of entries.", failed, testID)
+				t.Fatalf("\t\tTest %d:\tGot %d, Expected %d", testID, rh.Len(), len(m))
+			}
+			t.Logf("\t%s\tTest %d:\tShould have the correct number of entries.", succeed, testID)
+
+			for i := 0; i < n; i++ {
+				key := fmt.Sprintf("key%d", i)
+				v, err := rh.Retrieve(key)
+				exp, exists := m[key]
+				if exists != (err == nil) || v != exp {
+					t.Errorf("\t%s\tTest %d:\tShould retrieve the same values as the map.", failed, testID)
+					t.Fatalf("\t\tTest %d:\tGot %d, %v, Expected %d, %v for %q", testID, v, err, exp, exists, key)
+				}
+			}
+			t.Logf("\t%s\tTest %d:\tShould retrieve the same values as the map.", succeed, testID)
+
+			count := 0
+			rh.Do(func(key string, value int) bool {
+				if m[key] != value {
+					t.Fatalf("\t%s\tTest %d:\tShould see the same values in Do : %q", failed, testID, key)
+				}
+				count++
+				return true
+			})
+			if count != len(m) {
+				t.Errorf("\t%s\tTest %d:\tShould be able to run Do %d times.", failed, testID, len(m))
+				t.Fatalf("\t\tTest %d:\tGot %d, Expected %d", testID, count, len(m))
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to run Do %d times.", succeed, testID, count)
+
+			for key := range m {
+				if err := rh.Delete(key); err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to delete every value : %v", failed, testID, err)
+				}
+			}
+			if rh.Len() != 0 {
+				t.Errorf("\t%s\tTest %d:\tShould be empty after deleting every value.", failed, testID)
+				t.Fatalf("\t\tTest %d:\tGot %d, Expected %d", testID, rh.Len(), 0)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be empty after deleting every value.", succeed, testID)
+		}
+	}
+}
+
+// benchKeys is the number of keys used by the benchmarks.
+const benchKeys = 100_000
+
+var benchValue int
+
+// generateKeys is for generate the keys used by the benchmarks.
+func generateKeys(n int) []string {
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key%d", i)
+	}
+	return keys
+}
+
+// benchTables returns a constructor for every hash table implementation.
+func benchTables() []struct {
+	name string
+	make func() table
+} {
+	return []struct {
+		name string
+		make func() table
+	}{
+		{"chained", func() table { return hash.New[string, int]() }},
+		{"robinhood", func() table { return hash.NewRobinHood[string, int]() }},
+	}
+}
+
+// BenchmarkInsert measures an insert heavy workload.
+func BenchmarkInsert(b *testing.B) {
+	keys := generateKeys(benchKeys)
+
+	for _, tbl := range benchTables() {
+		b.Run(tbl.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				h := tbl.make()
+				for j, key := range keys {
+					h.Store(key, j)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkLookup measures a lookup heavy workload.
+func BenchmarkLookup(b *testing.B) {
+	keys := generateKeys(benchKeys)
+
+	for _, tbl := range benchTables() {
+		h := tbl.make()
+		for j, key := range keys {
+			h.Store(key, j)
+		}
+
+		b.Run(tbl.name, func(b *testing.B) {
+			b.ReportAllocs()
+			var v int
+			for i := 0; i < b.N; i++ {
+				v, _ = h.Retrieve(keys[i%len(keys)])
+			}
+			benchValue = v
+		})
+	}
+}
+
+// BenchmarkDelete measures a delete heavy workload.
+func BenchmarkDelete(b *testing.B) {
+	keys := generateKeys(benchKeys)
+
+	for _, tbl := range benchTables() {
+		b.Run(tbl.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				h := tbl.make()
+				for j, key := range keys {
+					h.Store(key, j)
+				}
+				b.StartTimer()
+
+				for _, key := range keys {
+					h.Delete(key)
+				}
+			}
+		})
+	}
+}
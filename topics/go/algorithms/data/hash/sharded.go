@@ -0,0 +1,163 @@
+package hash
+
+import (
+	"hash/maphash"
+	"sync"
+)
+
+// DefaultShards is the number of shards used by NewSharded when an
+// invalid number of shards is specified.
+const DefaultShards = 32
+
+// shard is one partition of the sharded hash table with its own lock.
+type shard[K comparable, V any] struct {
+	mu   sync.RWMutex
+	hash *Hash[K, V]
+}
+
+// Sharded is a hash table that is safe for concurrent use. Keys are
+// partitioned across a number of Hash shards, each protected by its own
+// read/write mutex, so goroutines working with keys in different shards
+// don't contend on the same lock. Every key is hashed once, and the hash
+// picks the shard and is passed on to it.
+type Sharded[K comparable, V any] struct {
+	shards []shard[K, V]
+	seed   maphash.Seed
+}
+
+// NewSharded returns a new concurrent hash table with the specified
+// number of shards.
+func NewSharded[K comparable, V any](shards int) *Sharded[K, V] {
+	if shards <= 0 {
+		shards = DefaultShards
+	}
+
+	s := Sharded[K, V]{
+		shards: make([]shard[K, V], shards),
+		seed:   maphash.MakeSeed(),
+	}
+	for i := range s.shards {
+		s.shards[i].hash = New[K, V]()
+		s.shards[i].hash.seed = s.seed
+	}
+	return &s
+}
+
+// Store adds a value in the hash table based on the key.
+func (s *Sharded[K, V]) Store(key K, value V) {
+	sh, hash := s.shardFor(key)
+
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	sh.hash.storeHashed(key, value, hash)
+}
+
+// Retrieve extracts a value from the hash table based on the key.
+func (s *Sharded[K, V]) Retrieve(key K) (V, error) {
+	sh, hash := s.shardFor(key)
+
+	sh.mu.RLock()
+	defer sh.mu.RUnlock()
+
+	return sh.hash.retrieveHashed(key, hash)
+}
+
+// Delete deletes an entry from the hash table.
+func (s *Sharded[K, V]) Delete(key K) error {
+	sh, hash := s.shardFor(key)
+
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	return sh.hash.deleteHashed(key, hash)
+}
+
+// LoadOrStore returns the existing value for the key if present.
+// Otherwise, it stores and returns the specified value. The loaded
+// result is true if the value was loaded, false if stored.
+func (s *Sharded[K, V]) LoadOrStore(key K, value V) (actual V, loaded bool) {
+	sh, hash := s.shardFor(key)
+
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	if actual, ok := sh.hash.lookupHashed(key, hash); ok {
+		return actual, true
+	}
+
+	sh.hash.storeHashed(key, value, hash)
+	return value, false
+}
+
+// CompareAndSwap swaps the old and new values for the key if the value
+// stored in the hash table is equal to old. It reports whether the swap
+// was performed. Like sync.Map, it panics if the values are of a type
+// that is not comparable.
+func (s *Sharded[K, V]) CompareAndSwap(key K, old V, new V) bool {
+	sh, hash := s.shardFor(key)
+
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	current, ok := sh.hash.lookupHashed(key, hash)
+	if !ok || any(current) != any(old) {
+		return false
+	}
+
+	sh.hash.storeHashed(key, new, hash)
+	return true
+}
+
+// Len return the number of elements in the hash. Since the shards are
+// locked one at a time, the result may be stale when other goroutines
+// are modifying the hash table.
+func (s *Sharded[K, V]) Len() int {
+	sum := 0
+	for i := range s.shards {
+		sh := &s.shards[i]
+
+		sh.mu.RLock()
+		sum += sh.hash.Len()
+		sh.mu.RUnlock()
+	}
+	return sum
+}
+
+// Range calls fn on each key/value. If fn return false stops the iteration.
+// Each shard is copied while holding its lock and fn is called on the copy,
+// so fn sees a consistent snapshot of every shard and is free to call other
+// methods on the hash table.
+func (s *Sharded[K, V]) Range(fn func(key K, value V) bool) {
+	var snapshot []entry[K, V]
+
+	for i := range s.shards {
+		sh := &s.shards[i]
+
+		// Take the snapshot of this shard, reusing the memory from
+		// the previous shard.
+		snapshot = snapshot[:0]
+		sh.mu.RLock()
+		sh.hash.Do(func(key K, value V) bool {
+			snapshot = append(snapshot, entry[K, V]{key: key, value: value})
+			return true
+		})
+		sh.mu.RUnlock()
+
+		for _, entry := range snapshot {
+			if ok := fn(entry.key, entry.value); !ok {
+				return
+			}
+		}
+	}
+}
+
+// shardFor returns the shard the specified key belongs to and the hash
+// of the key. The shard is picked using the high bits of the hash, since
+// the shard uses the low bits to pick a bucket. Using the same bits for
+// both would leave most of the buckets of every shard empty.
+func (s *Sharded[K, V]) shardFor(key K) (*shard[K, V], uint64) {
+	hash := hashKey(s.seed, key)
+	idx := (hash >> 32) * uint64(len(s.shards)) >> 32
+	return &s.shards[idx], hash
+}
@@ -0,0 +1,53 @@
+package hash
+
+import "testing"
+
+// TestShardedBuckets validates the shards keep the hash used to pick the
+// shard and spread their keys over every bucket.
+func TestShardedBuckets(t *testing.T) {
+	const shards = 32
+	const keys = shards * 1000
+
+	t.Log("Given the need to hash every key once.")
+	{
+		s := NewSharded[int, int](shards)
+		for key := 0; key < keys; key++ {
+			s.Store(key, key)
+		}
+
+		t.Logf("\tTest 0:\tWhen storing %d keys in %d shards.", keys, shards)
+		{
+			for i := range s.shards {
+				h := s.shards[i].hash
+				for _, table := range [][][]entry[int, int]{h.old, h.buckets} {
+					for _, bucket := range table {
+						for _, e := range bucket {
+							if sh, hash := s.shardFor(e.key); sh != &s.shards[i] || hash != e.hash {
+								t.Fatalf("\t%s\tTest 0:\tShould keep the hash used to pick the shard for key %d.", failed, e.key)
+							}
+						}
+					}
+				}
+
+				// A shard in the middle of a resize has buckets that
+				// haven't been filled yet.
+				if h.old != nil {
+					continue
+				}
+
+				used := 0
+				for _, bucket := range h.buckets {
+					if len(bucket) > 0 {
+						used++
+					}
+				}
+
+				if used < len(h.buckets)*3/4 {
+					t.Fatalf("\t%s\tTest 0:\tShould use most of the buckets of shard %d : %d of %d", failed, i, used, len(h.buckets))
+				}
+			}
+			t.Logf("\t%s\tTest 0:\tShould keep the hash used to pick the shard.", succeed)
+			t.Logf("\t%s\tTest 0:\tShould use most of the buckets of every shard.", succeed)
+		}
+	}
+}
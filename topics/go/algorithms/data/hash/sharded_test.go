@@ -0,0 +1,177 @@
+package hash_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/ardanlabs/gotraining/topics/go/algorithms/data/hash"
+)
+
+func TestSharded(t *testing.T) {
+	t.Log("Given the need to test concurrent hash functionality.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen storing and deleting from many goroutines", testID)
+		{
+			const goroutines = 8
+			const n = 1_000
+
+			s := hash.NewSharded[string, int](4)
+
+			var wg sync.WaitGroup
+			wg.Add(goroutines)
+			for g := 0; g < goroutines; g++ {
+				go func(g int) {
+					defer wg.Done()
+					for i := 0; i < n; i++ {
+						key := fmt.Sprintf("g%d-key%d", g, i)
+						s.Store(key, i)
+						if v, err := s.Retrieve(key); err != nil || v != i {
+							t.Errorf("\t%s\tTest %d:\tShould be able to retrieve %q : %d, %v", failed, testID, key, v, err)
+							return
+						}
+						if i%2 == 1 {
+							if err := s.Delete(key); err != nil {
+								t.Errorf("\t%s\tTest %d:\tShould be able to delete %q : %v", failed, testID, key, err)
+								return
+							}
+						}
+					}
+				}(g)
+			}
+			wg.Wait()
+			t.Logf("\t%s\tTest %d:\tShould be able to store, retrieve and delete values.", succeed, testID)
+
+			if exp := goroutines * n / 2; s.Len() != exp {
+				t.Errorf("\t%s\tTest %d:\tShould have the correct number of entries.", failed, testID)
+				t.Fatalf("\t\tTest %d:\tGot %d, Expected %d", testID, s.Len(), exp)
+			}
+			t.Logf("\t%s\tTest %d:\tShould have the correct number of entries.", succeed, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen using LoadOrStore from many goroutines", testID)
+		{
+			const goroutines = 8
+
+			s := hash.NewSharded[string, int](0)
+
+			var wg sync.WaitGroup
+			var mu sync.Mutex
+			stored := 0
+
+			wg.Add(goroutines)
+			for g := 0; g < goroutines; g++ {
+				go func(g int) {
+					defer wg.Done()
+					actual, loaded := s.LoadOrStore("key", g)
+					if !loaded {
+						mu.Lock()
+						stored++
+						mu.Unlock()
+					}
+					if v, _ := s.Retrieve("key"); v != actual {
+						t.Errorf("\t%s\tTest %d:\tShould see the stored value : %d, %d", failed, testID, v, actual)
+					}
+				}(g)
+			}
+			wg.Wait()
+
+			if stored != 1 {
+				t.Errorf("\t%s\tTest %d:\tShould only store the value once.", failed, testID)
+				t.Fatalf("\t\tTest %d:\tGot %d, Expected %d", testID, stored, 1)
+			}
+			t.Logf("\t%s\tTest %d:\tShould only store the value once.", succeed, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen using CompareAndSwap to increment a counter", testID)
+		{
+			const goroutines = 8
+			const n = 500
+
+			s := hash.NewSharded[string, int](2)
+			s.Store("counter", 0)
+
+			var wg sync.WaitGroup
+			wg.Add(goroutines)
+			for g := 0; g < goroutines; g++ {
+				go func() {
+					defer wg.Done()
+					for i := 0; i < n; i++ {
+						for {
+							v, _ := s.Retrieve("counter")
+							if s.CompareAndSwap("counter", v, v+1) {
+								break
+							}
+						}
+					}
+				}()
+			}
+			wg.Wait()
+
+			if v, _ := s.Retrieve("counter"); v != goroutines*n {
+				t.Errorf("\t%s\tTest %d:\tShould not lose any increments.", failed, testID)
+				t.Fatalf("\t\tTest %d:\tGot %d, Expected %d", testID, v, goroutines*n)
+			}
+			t.Logf("\t%s\tTest %d:\tShould not lose any increments.", succeed, testID)
+
+			if s.CompareAndSwap("missing", 0, 1) {
+				t.Fatalf("\t%s\tTest %d:\tShould not swap a missing key.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould not swap a missing key.", succeed, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen ranging while other goroutines store values", testID)
+		{
+			const n = 1_000
+
+			s := hash.NewSharded[int, int](8)
+			for i := 0; i < n; i++ {
+				s.Store(i, i)
+			}
+
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				for i := n; i < 2*n; i++ {
+					s.Store(i, i)
+				}
+			}()
+
+			seen := make(map[int]bool)
+			s.Range(func(key int, value int) bool {
+				if key != value {
+					t.Errorf("\t%s\tTest %d:\tShould see matching keys and values : %d, %d", failed, testID, key, value)
+				}
+
+				// Calling back into the hash table must not deadlock.
+				s.Store(-key-1, -key-1)
+
+				seen[key] = true
+				return true
+			})
+			<-done
+
+			for i := 0; i < n; i++ {
+				if !seen[i] {
+					t.Fatalf("\t%s\tTest %d:\tShould see every value stored before Range : %d", failed, testID, i)
+				}
+			}
+			t.Logf("\t%s\tTest %d:\tShould see every value stored before Range.", succeed, testID)
+
+			count := 0
+			s.Range(func(key int, value int) bool {
+				count++
+				return count < 10
+			})
+			if count != 10 {
+				t.Errorf("\t%s\tTest %d:\tShould stop when fn returns false.", failed, testID)
+				t.Fatalf("\t\tTest %d:\tGot %d, Expected %d", testID, count, 10)
+			}
+			t.Logf("\t%s\tTest %d:\tShould stop when fn returns false.", succeed, testID)
+		}
+	}
+}
@@ -6,29 +6,29 @@ package list
 
 import (
 	"fmt"
-	"strings"
 )
 
 // Node represents the data being stored.
-type Node struct {
-	Data string
-	next *Node
-	prev *Node
+type Node[T comparable] struct {
+	Data T
+	next *Node[T]
+	prev *Node[T]
 }
 
-// List represents a list of nodes.
-type List struct {
+// List represents a list of nodes. Data is compared with the == operator
+// when looking for nodes.
+type List[T comparable] struct {
 	Count int
-	first *Node
-	last  *Node
+	first *Node[T]
+	last  *Node[T]
 }
 
 // Add places a new node at the end of the list.
-func (l *List) Add(data string) *Node {
+func (l *List[T]) Add(data T) *Node[T] {
 
 	// When creating the new node, have the new node
 	// point to the last node in the list.
-	n := Node{
+	n := Node[T]{
 		Data: data,
 		prev: l.last,
 	}
@@ -61,11 +61,11 @@ func (l *List) Add(data string) *Node {
 }
 
 // AddFront places a new node at the front of the list.
-func (l *List) AddFront(data string) *Node {
+func (l *List[T]) AddFront(data T) *Node[T] {
 
 	// When creating the new node, have the new node
 	// point to the first node in the list.
-	n := Node{
+	n := Node[T]{
 		Data: data,
 		next: l.first,
 	}
@@ -98,7 +98,7 @@ func (l *List) AddFront(data string) *Node {
 }
 
 // Find traverses the list looking for the specified data.
-func (l *List) Find(data string) (*Node, error) {
+func (l *List[T]) Find(data T) (*Node[T], error) {
 	n := l.first
 	for n != nil {
 		if n.Data == data {
@@ -106,12 +106,12 @@ func (l *List) Find(data string) (*Node, error) {
 		}
 		n = n.next
 	}
-	return nil, fmt.Errorf("unable to locate %q in list", data)
+	return nil, fmt.Errorf("unable to locate %v in list", formatData(data))
 }
 
 // FindReverse traverses the list in the opposite direction
 // looking for the specified data.
-func (l *List) FindReverse(data string) (*Node, error) {
+func (l *List[T]) FindReverse(data T) (*Node[T], error) {
 	n := l.last
 	for n != nil {
 		if n.Data == data {
@@ -119,12 +119,12 @@ func (l *List) FindReverse(data string) (*Node, error) {
 		}
 		n = n.prev
 	}
-	return nil, fmt.Errorf("unable to locate %q in list", data)
+	return nil, fmt.Errorf("unable to locate %v in list", formatData(data))
 }
 
 // Remove traverses the list looking for the specified data
 // and if found, removes the node from the list.
-func (l *List) Remove(data string) (*Node, error) {
+func (l *List[T]) Remove(data T) (*Node[T], error) {
 	n, err := l.Find(data)
 	if err != nil {
 		return nil, err
@@ -141,7 +141,7 @@ func (l *List) Remove(data string) (*Node, error) {
 
 // Operate accepts a function that takes a node and calls
 // the specified function for every node found.
-func (l *List) Operate(f func(n *Node) error) error {
+func (l *List[T]) Operate(f func(n *Node[T]) error) error {
 	n := l.first
 	for n != nil {
 		if err := f(n); err != nil {
@@ -154,7 +154,7 @@ func (l *List) Operate(f func(n *Node) error) error {
 
 // OperateReverse accepts a function that takes a node and
 // calls the specified function for every node found.
-func (l *List) OperateReverse(f func(n *Node) error) error {
+func (l *List[T]) OperateReverse(f func(n *Node[T]) error) error {
 	n := l.last
 	for n != nil {
 		if err := f(n); err != nil {
@@ -165,8 +165,11 @@ func (l *List) OperateReverse(f func(n *Node) error) error {
 	return nil
 }
 
-// AddSort adds a node based on lexical ordering.
-func (l *List) AddSort(data string) *Node {
+// AddSort adds a node based on the ordering provided by the compare
+// function, which returns a negative number when a is less than b, zero
+// when they are equal and a positive number when a is greater than b.
+// For strings, strings.Compare provides lexical ordering.
+func (l *List[T]) AddSort(data T, compare func(a, b T) int) *Node[T] {
 
 	// If the list was empty add the data
 	// as the first node.
@@ -180,14 +183,14 @@ func (l *List) AddSort(data string) *Node {
 
 		// If this data is greater than the current node,
 		// keep traversing until it is less than or equal.
-		if strings.Compare(data, n.Data) > 0 {
+		if compare(data, n.Data) > 0 {
 			n = n.next
 			continue
 		}
 
 		// Create the new node and place it before the
 		// current node.
-		new := Node{
+		new := Node[T]{
 			Data: data,
 			next: n,
 			prev: n.prev,
@@ -215,6 +218,14 @@ func (l *List) AddSort(data string) *Node {
 		return n
 	}
 
-	// This must be the largest string, so add to the end.
+	// This must be the largest data, so add to the end.
 	return l.Add(data)
 }
+
+// formatData returns the representation of the data used in error messages.
+func formatData[T comparable](data T) string {
+	if s, ok := any(data).(string); ok {
+		return fmt.Sprintf("%q", s)
+	}
+	return fmt.Sprintf("%v", data)
+}
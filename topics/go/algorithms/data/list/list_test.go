@@ -5,52 +5,54 @@
 	package list
 
 	// Node represents the data being stored.
-	type Node struct {
-		Data string
-		next *Node
-		prev *Node
+	type Node[T comparable] struct {
+		Data T
+		next *Node[T]
+		prev *Node[T]
 	}
 
 	// List represents a list of nodes.
-	type List struct {
+	type List[T comparable] struct {
 		Count int
-		first *Node
-		last  *Node
+		first *Node[T]
+		last  *Node[T]
 	}
 
 	// Add places a new node at the end of the list.
-	func (l *List) Add(data string) *Node
+	func (l *List[T]) Add(data T) *Node[T]
 
 	// AddFront places a new node at the front of the list.
-	func (l *List) AddFront(data string) *Node
+	func (l *List[T]) AddFront(data T) *Node[T]
 
 	// Find traverses the list looking for the specified data.
-	func (l *List) Find(data string) (*Node, error)
+	func (l *List[T]) Find(data T) (*Node[T], error)
 
 	// FindReverse traverses the list in the opposite direction
 	// looking for the specified data.
-	func (l *List) FindReverse(data string) (*Node, error)
+	func (l *List[T]) FindReverse(data T) (*Node[T], error)
 
 	// Remove traverses the list looking for the specified data
 	// and if found, removes the node from the list.
-	func (l *List) Remove(data string) (*Node, error)
+	func (l *List[T]) Remove(data T) (*Node[T], error)
 
 	// Operate accepts a function that takes a node and calls
 	// the specified function for every node found.
-	func (l *List) Operate(f func(n *Node) error) error
+	func (l *List[T]) Operate(f func(n *Node[T]) error) error
 
 	// OperateReverse accepts a function that takes a node and
 	// calls the specified function for every node found.
-	func (l *List) OperateReverse(f func(n *Node) error) error
+	func (l *List[T]) OperateReverse(f func(n *Node[T]) error) error
 
-	// AddSort adds a node based on lexical ordering.
-	func (l *List) AddSort(data string) *Node
+	// AddSort adds a node based on the ordering provided by the
+	// compare function.
+	func (l *List[T]) AddSort(data T, compare func(a, b T) int) *Node[T]
 */
 
 package list_test
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/ardanlabs/gotraining/topics/go/algorithms/data/list"
@@ -66,7 +68,7 @@ func TestAdd(t *testing.T) {
 		const nodes = 5
 		t.Logf("\tTest 0:\tWhen adding %d nodes", nodes)
 		{
-			var l list.List
+			var l list.List[string]
 
 			var orgNodeData string
 			for i := 0; i < nodes; i++ {
@@ -82,7 +84,7 @@ func TestAdd(t *testing.T) {
 			t.Logf("\t%s\tTest 0:\tShould be able to add %d nodes.", succeed, nodes)
 
 			var nodeData string
-			f := func(n *list.Node) error {
+			f := func(n *list.Node[string]) error {
 				nodeData += n.Data
 				return nil
 			}
@@ -107,7 +109,7 @@ func TestAddFront(t *testing.T) {
 		const nodes = 5
 		t.Logf("\tTest 0:\tWhen adding %d nodes", nodes)
 		{
-			var l list.List
+			var l list.List[string]
 
 			var orgNodeData string
 			for i := 0; i < nodes; i++ {
@@ -123,7 +125,7 @@ func TestAddFront(t *testing.T) {
 			t.Logf("\t%s\tTest 0:\tShould be able to add %d nodes.", succeed, nodes)
 
 			var nodeData string
-			f := func(n *list.Node) error {
+			f := func(n *list.Node[string]) error {
 				nodeData += n.Data
 				return nil
 			}
@@ -148,7 +150,7 @@ func TestFind(t *testing.T) {
 		const nodes = 5
 		t.Logf("\tTest 0:\tWhen adding %d nodes", nodes)
 		{
-			var l list.List
+			var l list.List[string]
 
 			var orgNodeData string
 			for i := 0; i < nodes; i++ {
@@ -180,7 +182,7 @@ func TestFindReverse(t *testing.T) {
 		const nodes = 5
 		t.Logf("\tTest 0:\tWhen adding %d nodes", nodes)
 		{
-			var l list.List
+			var l list.List[string]
 
 			var orgNodeData string
 			for i := 0; i < nodes; i++ {
@@ -212,7 +214,7 @@ func TestRemove(t *testing.T) {
 		const nodes = 5
 		t.Logf("\tTest 0:\tWhen adding %d nodes", nodes)
 		{
-			var l list.List
+			var l list.List[string]
 
 			var orgNodeData string
 			for i := 0; i < nodes; i++ {
@@ -250,10 +252,10 @@ func TestAddSort(t *testing.T) {
 		orgNodeData := []string{"grape", "apple", "plum", "mango", "kiwi"}
 		t.Logf("\tTest 0:\tWhen adding %d nodes", len(orgNodeData))
 		{
-			var l list.List
+			var l list.List[string]
 
 			for _, data := range orgNodeData {
-				l.AddSort(data)
+				l.AddSort(data, strings.Compare)
 			}
 
 			if l.Count != len(orgNodeData) {
@@ -263,7 +265,7 @@ func TestAddSort(t *testing.T) {
 			t.Logf("\t%s\tTest 0:\tShould be able to add %d nodes.", succeed, len(orgNodeData))
 
 			var nodeData string
-			f := func(n *list.Node) error {
+			f := func(n *list.Node[string]) error {
 				nodeData += n.Data
 				return nil
 			}
@@ -280,7 +282,7 @@ func TestAddSort(t *testing.T) {
 			t.Logf("\t%s\tTest 0:\tShould be able to traverse over %d nodes in sort order.", succeed, len(orgNodeData))
 
 			nodeData = ""
-			f = func(n *list.Node) error {
+			f = func(n *list.Node[string]) error {
 				nodeData += n.Data
 				return nil
 			}
@@ -298,3 +300,46 @@ func TestAddSort(t *testing.T) {
 		}
 	}
 }
+
+// TestAddSortCompare validates the AddSort functionality with data that
+// is not a string.
+func TestAddSortCompare(t *testing.T) {
+	t.Log("Given the need to test AddSort functionality with a custom compare function.")
+	{
+		type user struct {
+			Name string
+			Age  int
+		}
+		users := []user{{"bill", 40}, {"ale", 25}, {"jill", 33}}
+		byAge := func(a, b user) int { return a.Age - b.Age }
+
+		t.Logf("\tTest 0:\tWhen adding %d nodes", len(users))
+		{
+			var l list.List[user]
+
+			for _, u := range users {
+				l.AddSort(u, byAge)
+			}
+
+			var names string
+			f := func(n *list.Node[user]) error {
+				names += n.Data.Name
+				return nil
+			}
+			if err := l.Operate(f); err != nil {
+				t.Fatalf("\t%s\tTest 0:\tShould be able to operate on the list : %v", failed, err)
+			}
+
+			if names != "alejillbill" {
+				t.Logf("\t%s\tTest 0:\tShould be able to traverse over %d nodes in sort order.", failed, len(users))
+				t.Fatalf("\t\tTest 0:\tGot %s, Expected %s.", names, "alejillbill")
+			}
+			t.Logf("\t%s\tTest 0:\tShould be able to traverse over %d nodes in sort order.", succeed, len(users))
+
+			if _, err := l.Find(user{"jill", 33}); err != nil {
+				t.Fatalf("\t%s\tTest 0:\tShould be able to find a node : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 0:\tShould be able to find a node.", succeed)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Set of error variables returned by the blocking queue.
+var (
+	ErrClosed = errors.New("queue is closed")
+	ErrFull   = errors.New("queue at capacity")
+	ErrEmpty  = errors.New("queue is empty")
+)
+
+// Blocking is a bounded queue that is safe for concurrent use by
+// producer and consumer goroutines. Put blocks while the queue is at
+// capacity and Take blocks while the queue is empty.
+//
+// Waiting goroutines are woken up by closing a channel, which lets them
+// also wait on the cancellation of a context. A new channel is created
+// for the next set of waiters.
+type Blocking[T any] struct {
+	mu       sync.Mutex
+	q        *Queue[T]
+	notEmpty chan struct{}
+	notFull  chan struct{}
+	closed   bool
+}
+
+// NewBlocking returns a blocking queue with a set capacity.
+func NewBlocking[T any](cap int) (*Blocking[T], error) {
+	q, err := New[T](cap)
+	if err != nil {
+		return nil, err
+	}
+
+	b := Blocking[T]{
+		q:        q,
+		notEmpty: make(chan struct{}),
+		notFull:  make(chan struct{}),
+	}
+	return &b, nil
+}
+
+// Put inserts data into the queue, waiting for available capacity. It
+// returns ErrClosed if the queue is closed and the context error if the
+// context is done before the data is inserted.
+func (b *Blocking[T]) Put(ctx context.Context, data T) error {
+	for {
+		b.mu.Lock()
+		err := b.put(data)
+		wait := b.notFull
+		b.mu.Unlock()
+
+		if !errors.Is(err, ErrFull) {
+			return err
+		}
+
+		// Wait for a consumer to take data or for the queue to be
+		// closed before trying again.
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Take removes data from the queue, waiting for data to be available. Once
+// the queue is closed, the remaining data can still be taken and ErrClosed
+// is returned when the queue is empty. The context error is returned if
+// the context is done before data is available.
+func (b *Blocking[T]) Take(ctx context.Context) (T, error) {
+	for {
+		b.mu.Lock()
+		data, err := b.take()
+		wait := b.notEmpty
+		b.mu.Unlock()
+
+		if !errors.Is(err, ErrEmpty) {
+			return data, err
+		}
+
+		// Wait for a producer to put data or for the queue to be
+		// closed before trying again.
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+}
+
+// TryPut inserts data into the queue without waiting. It returns ErrFull
+// if the queue is at capacity and ErrClosed if the queue is closed.
+func (b *Blocking[T]) TryPut(data T) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.put(data)
+}
+
+// TryTake removes data from the queue without waiting. It returns
+// ErrEmpty if there is no data and ErrClosed if the queue is closed
+// and all the data has been taken.
+func (b *Blocking[T]) TryTake() (T, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.take()
+}
+
+// Close stops the queue from accepting new data and wakes up every
+// waiting goroutine. Data already in the queue can still be taken.
+// Calling Close more than once has no effect.
+func (b *Blocking[T]) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+
+	b.closed = true
+	close(b.notEmpty)
+	close(b.notFull)
+}
+
+// Len returns the number of items in the queue.
+func (b *Blocking[T]) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.q.Count
+}
+
+// put inserts the data and wakes up the goroutines waiting for data. The
+// lock must be held by the caller.
+func (b *Blocking[T]) put(data T) error {
+	if b.closed {
+		return ErrClosed
+	}
+
+	if err := b.q.Enqueue(data); err != nil {
+		return ErrFull
+	}
+
+	close(b.notEmpty)
+	b.notEmpty = make(chan struct{})
+	return nil
+}
+
+// take removes data and wakes up the goroutines waiting for capacity. The
+// lock must be held by the caller.
+func (b *Blocking[T]) take() (T, error) {
+	data, err := b.q.Dequeue()
+	if err != nil {
+		if b.closed {
+			return data, ErrClosed
+		}
+		return data, ErrEmpty
+	}
+
+	if !b.closed {
+		close(b.notFull)
+		b.notFull = make(chan struct{})
+	}
+	return data, nil
+}
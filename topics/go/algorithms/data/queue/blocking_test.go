@@ -0,0 +1,172 @@
+package queue_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/gotraining/topics/go/algorithms/data/queue"
+)
+
+// TestBlockingStress validates the blocking queue with many producers
+// and consumers.
+func TestBlockingStress(t *testing.T) {
+	t.Log("Given the need to share a queue between goroutines.")
+	{
+		const producers = 8
+		const consumers = 8
+		const items = 1000
+
+		t.Logf("\tTest 0:\tWhen %d producers put %d items each for %d consumers", producers, items, consumers)
+		{
+			b, err := queue.NewBlocking[int](4)
+			if err != nil {
+				t.Fatalf("\t%s\tTest 0:\tShould be able to create a queue : %v", failed, err)
+			}
+
+			ctx := context.Background()
+
+			var pwg sync.WaitGroup
+			pwg.Add(producers)
+			for p := 0; p < producers; p++ {
+				go func(p int) {
+					defer pwg.Done()
+					for i := 0; i < items; i++ {
+						if err := b.Put(ctx, p*items+i); err != nil {
+							t.Errorf("\t%s\tTest 0:\tShould be able to put an item : %v", failed, err)
+							return
+						}
+					}
+				}(p)
+			}
+
+			results := make(chan []int, consumers)
+			for c := 0; c < consumers; c++ {
+				go func() {
+					var taken []int
+					for {
+						d, err := b.Take(ctx)
+						if errors.Is(err, queue.ErrClosed) {
+							results <- taken
+							return
+						}
+						if err != nil {
+							t.Errorf("\t%s\tTest 0:\tShould be able to take an item : %v", failed, err)
+						}
+						taken = append(taken, d)
+					}
+				}()
+			}
+
+			pwg.Wait()
+			b.Close()
+
+			seen := make([]bool, producers*items)
+			for c := 0; c < consumers; c++ {
+				for _, d := range <-results {
+					if seen[d] {
+						t.Fatalf("\t%s\tTest 0:\tShould take every item once : %d", failed, d)
+					}
+					seen[d] = true
+				}
+			}
+			for d, ok := range seen {
+				if !ok {
+					t.Fatalf("\t%s\tTest 0:\tShould take every item : %d is missing", failed, d)
+				}
+			}
+			t.Logf("\t%s\tTest 0:\tShould take every item exactly once.", succeed)
+		}
+	}
+}
+
+// TestBlockingCancel validates Put and Take return when the context
+// is cancelled.
+func TestBlockingCancel(t *testing.T) {
+	t.Log("Given the need to stop waiting on a queue.")
+	{
+		t.Logf("\tTest 0:\tWhen the context is cancelled")
+		{
+			b, err := queue.NewBlocking[string](1)
+			if err != nil {
+				t.Fatalf("\t%s\tTest 0:\tShould be able to create a queue : %v", failed, err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+
+			if _, err := b.Take(ctx); !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("\t%s\tTest 0:\tShould stop waiting to take : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 0:\tShould stop waiting to take.", succeed)
+
+			if err := b.TryPut("a"); err != nil {
+				t.Fatalf("\t%s\tTest 0:\tShould be able to try to put an item : %v", failed, err)
+			}
+			if err := b.TryPut("b"); !errors.Is(err, queue.ErrFull) {
+				t.Fatalf("\t%s\tTest 0:\tShould not be able to try to put an item when full : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 0:\tShould not be able to try to put an item when full.", succeed)
+
+			if err := b.Put(ctx, "b"); !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("\t%s\tTest 0:\tShould stop waiting to put : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 0:\tShould stop waiting to put.", succeed)
+		}
+	}
+}
+
+// TestBlockingClose validates Close wakes up waiting goroutines and
+// lets the remaining items be drained.
+func TestBlockingClose(t *testing.T) {
+	t.Log("Given the need to close a queue.")
+	{
+		t.Logf("\tTest 0:\tWhen there are goroutines waiting")
+		{
+			b, err := queue.NewBlocking[int](2)
+			if err != nil {
+				t.Fatalf("\t%s\tTest 0:\tShould be able to create a queue : %v", failed, err)
+			}
+
+			ctx := context.Background()
+			b.Put(ctx, 1)
+			b.Put(ctx, 2)
+
+			errs := make(chan error)
+			go func() {
+				errs <- b.Put(ctx, 3)
+			}()
+
+			// Give the goroutine time to start waiting.
+			time.Sleep(10 * time.Millisecond)
+			b.Close()
+
+			if err := <-errs; !errors.Is(err, queue.ErrClosed) {
+				t.Fatalf("\t%s\tTest 0:\tShould wake up a waiting producer : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 0:\tShould wake up a waiting producer.", succeed)
+
+			if err := b.TryPut(4); !errors.Is(err, queue.ErrClosed) {
+				t.Fatalf("\t%s\tTest 0:\tShould not be able to put after close : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 0:\tShould not be able to put after close.", succeed)
+
+			for _, exp := range []int{1, 2} {
+				if d, err := b.Take(ctx); err != nil || d != exp {
+					t.Fatalf("\t%s\tTest 0:\tShould drain the remaining items : %d, %v", failed, d, err)
+				}
+			}
+			t.Logf("\t%s\tTest 0:\tShould drain the remaining items.", succeed)
+
+			if _, err := b.Take(ctx); !errors.Is(err, queue.ErrClosed) {
+				t.Fatalf("\t%s\tTest 0:\tShould see the queue is closed once drained : %v", failed, err)
+			}
+			if _, err := b.TryTake(); !errors.Is(err, queue.ErrClosed) {
+				t.Fatalf("\t%s\tTest 0:\tShould see the queue is closed once drained : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 0:\tShould see the queue is closed once drained.", succeed)
+		}
+	}
+}
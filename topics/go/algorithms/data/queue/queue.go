@@ -8,119 +8,219 @@ import (
 	"errors"
 )
 
-// Data represents what is being stored on the queue.
-type Data struct {
-	Name string
-}
+// Mode defines what the queue does when data is enqueued and the
+// queue is at capacity.
+type Mode int
+
+// Set of modes a queue can be created with.
+const (
+
+	// Bounded rejects new data when the queue is at capacity.
+	Bounded Mode = iota
+
+	// Grow doubles the capacity of the queue when it is at capacity.
+	Grow
+
+	// GrowShrink doubles the capacity of the queue when it is at capacity
+	// and halves it when it is only a quarter full, but never below the
+	// initial capacity.
+	GrowShrink
+
+	// Overwrite drops the oldest data in the queue to make room for the
+	// new data when the queue is at capacity.
+	Overwrite
+)
 
 // Queue represents a list of data.
-type Queue struct {
-	Count int
-	data  []*Data
-	front int
-	end   int
+type Queue[T any] struct {
+	Count  int
+	data   []T
+	front  int
+	end    int
+	mode   Mode
+	minCap int
 }
 
 // New returns a queue with a set capacity.
-func New(cap int) (*Queue, error) {
+func New[T any](cap int) (*Queue[T], error) {
+	return NewWithMode[T](cap, Bounded)
+}
+
+// NewWithMode returns a queue with an initial capacity and a mode that
+// defines what happens when the queue is at capacity.
+func NewWithMode[T any](cap int, mode Mode) (*Queue[T], error) {
 	if cap <= 0 {
 		return nil, errors.New("invalid capacity")
 	}
 
-	q := Queue{
-		front: 0,
-		end:   0,
-		data:  make([]*Data, cap),
+	if mode < Bounded || mode > Overwrite {
+		return nil, errors.New("invalid mode")
+	}
+
+	q := Queue[T]{
+		front:  0,
+		end:    0,
+		data:   make([]T, cap),
+		mode:   mode,
+		minCap: cap,
 	}
 	return &q, nil
 }
 
-// Enqueue inserts data into the queue if there
-// is available capacity.
-func (q *Queue) Enqueue(data *Data) error {
-
-	// If the front of the queue is right behind the end or
-	// if the front is at the end of the capacity and the end
-	// is at the beginning of the capacity, the queue is full.
-	//  F  E  - Enqueue (Full) |  E        F - Enqueue (Full)
-	// [A][B][C]               | [A][B][C]
-	if q.front+1 == q.end ||
-		q.front == len(q.data) && q.end == 0 {
-		return errors.New("queue at capacity")
-	}
+// Enqueue inserts data into the queue. If the queue is at capacity, what
+// happens depends on the mode of the queue.
+func (q *Queue[T]) Enqueue(data T) error {
 
-	switch {
-	case q.front == len(q.data):
+	// The front is where the next data is added and the end is where
+	// the oldest data is. When the count matches the capacity, the
+	// front has circled around and caught up with the end.
+	//     E  F                |  EF
+	// [A][B][ ][ ]            | [A][B][C] (Full)
+	if q.Count == len(q.data) {
+		switch q.mode {
+		case Grow, GrowShrink:
+			q.resize(2 * len(q.data))
 
-		// If we are at the end of the capacity, then
-		// circle back to the beginning of the capacity by
-		// moving the front pointer to the beginning.
-		q.front = 0
-		q.data[q.front] = data
-	default:
+		case Overwrite:
+
+			// Drop the oldest data by moving the end pointer.
+			// The new data will take its place.
+			q.Dequeue()
 
-		// Add the data to the current front position
-		// and then move the front pointer.
-		q.data[q.front] = data
-		q.front++
+		default:
+			return errors.New("queue at capacity")
+		}
 	}
 
+	// Add the data to the current front position and then move the
+	// front pointer, circling back to the beginning of the capacity
+	// when we reach the end of it.
+	q.data[q.front] = data
+	q.front = q.next(q.front)
 	q.Count++
 
 	return nil
 }
 
 // Dequeue removes data into the queue if data exists.
-func (q *Queue) Dequeue() (*Data, error) {
+func (q *Queue[T]) Dequeue() (T, error) {
+	var zero T
 
-	// If the front and end are the same, the
-	// queue is empty
+	// If there is no data the queue is empty.
 	//  EF - (Empty)
 	// [  ][ ][ ]
-	if q.front == q.end {
-		return nil, errors.New("queue is empty")
+	if q.Count == 0 {
+		return zero, errors.New("queue is empty")
 	}
 
-	var data *Data
-	switch {
-	case q.end == len(q.data):
+	// Remove the data from the current end position and then move the
+	// end pointer, circling back to the beginning of the capacity when
+	// we reach the end of it. The slot is cleared so the data can be
+	// garbage collected.
+	data := q.data[q.end]
+	q.data[q.end] = zero
+	q.end = q.next(q.end)
+	q.Count--
 
-		// If we are at the end of the capacity, then
-		// circle back to the beginning of the capacity by
-		// moving the end pointer to the beginning.
-		q.end = 0
-		data = q.data[q.end]
-	default:
+	// Look to see if the capacity can be reduced due to the
+	// amount of data removed from the queue.
+	if q.mode == GrowShrink && len(q.data) > q.minCap && q.Count <= len(q.data)/4 {
+		size := len(q.data) / 2
+		if size < q.minCap {
+			size = q.minCap
+		}
+		q.resize(size)
+	}
+
+	return data, nil
+}
 
-		// Remove the data from the current end position
-		// and then move the end pointer.
-		data = q.data[q.end]
-		q.end++
+// Peek returns the oldest data in the queue without removing it.
+func (q *Queue[T]) Peek() (T, error) {
+	if q.Count == 0 {
+		var zero T
+		return zero, errors.New("queue is empty")
 	}
 
-	q.Count--
+	return q.data[q.end], nil
+}
 
-	return data, nil
+// Len returns the number of items in the queue.
+func (q *Queue[T]) Len() int {
+	return q.Count
 }
 
-// Operate accepts a function that takes data and calls
-// the specified function for every piece of data found.
-func (q *Queue) Operate(f func(d *Data) error) error {
-	end := q.end
-	for {
-		if end == q.front {
-			break
-		}
+// Cap returns the current capacity of the queue.
+func (q *Queue[T]) Cap() int {
+	return len(q.data)
+}
 
-		if end == len(q.data) {
-			end = 0
+// Clear removes all the data from the queue. A queue that can shrink goes
+// back to its initial capacity.
+func (q *Queue[T]) Clear() {
+	if q.mode == GrowShrink {
+		q.data = make([]T, q.minCap)
+	} else {
+		var zero T
+		for i := range q.data {
+			q.data[i] = zero
 		}
+	}
 
-		if err := f(q.data[end]); err != nil {
+	q.front = 0
+	q.end = 0
+	q.Count = 0
+}
+
+// Operate accepts a function that takes data and calls
+// the specified function for every piece of data found.
+func (q *Queue[T]) Operate(f func(d T) error) error {
+	idx := q.end
+	for i := 0; i < q.Count; i++ {
+		if err := f(q.data[idx]); err != nil {
 			return err
 		}
-
-		end++
+		idx = q.next(idx)
 	}
 	return nil
 }
+
+// next returns the index position after the specified index, circling
+// back to the beginning of the capacity.
+func (q *Queue[T]) next(idx int) int {
+	idx++
+	if idx == len(q.data) {
+		return 0
+	}
+	return idx
+}
+
+// resize moves the data into a new slice of the specified capacity. The
+// data is re-linearised so the oldest data is at index 0.
+//
+//	  F  E                    E        F
+//	[C][ ][A][B]  ->  [A][B][C][ ][ ][ ][ ][ ]
+func (q *Queue[T]) resize(size int) {
+	data := make([]T, size)
+
+	switch {
+	case q.end+q.Count <= len(q.data):
+
+		// The data has not circled around, so copy it in one go.
+		copy(data, q.data[q.end:q.end+q.Count])
+
+	default:
+
+		// Copy from the end pointer to the end of the capacity and then
+		// from the beginning of the capacity to the front pointer.
+		n := copy(data, q.data[q.end:])
+		copy(data[n:], q.data[:q.front])
+	}
+
+	q.data = data
+	q.end = 0
+	q.front = q.Count
+	if q.front == size {
+		q.front = 0
+	}
+}
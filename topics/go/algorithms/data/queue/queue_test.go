@@ -4,32 +4,57 @@
 
 	package queue
 
-	// Data represents what is being stored on the queue.
-	type Data struct {
-		Name string
-	}
+	// Mode defines what the queue does when data is enqueued and the
+	// queue is at capacity.
+	type Mode int
+
+	// Set of modes a queue can be created with.
+	const (
+		Bounded Mode = iota
+		Grow
+		GrowShrink
+		Overwrite
+	)
 
 	// Queue represents a list of data.
-	type Queue struct {
-		Count int
-		data  []*Data
-		front int
-		end   int
+	type Queue[T any] struct {
+		Count  int
+		data   []T
+		front  int
+		end    int
+		mode   Mode
+		minCap int
 	}
 
 	// New returns a queue with a set capacity.
-	func New(cap int) (*Queue, error)
+	func New[T any](cap int) (*Queue[T], error)
+
+	// NewWithMode returns a queue with an initial capacity and a mode that
+	// defines what happens when the queue is at capacity.
+	func NewWithMode[T any](cap int, mode Mode) (*Queue[T], error)
 
-	// Enqueue inserts data into the queue if there
-	// is available capacity.
-	func (q *Queue) Enqueue(data *Data) error
+	// Enqueue inserts data into the queue. If the queue is at capacity, what
+	// happens depends on the mode of the queue.
+	func (q *Queue[T]) Enqueue(data T) error
 
 	// Dequeue removes data into the queue if data exists.
-	func (q *Queue) Dequeue() (*Data, error)
+	func (q *Queue[T]) Dequeue() (T, error)
+
+	// Peek returns the oldest data in the queue without removing it.
+	func (q *Queue[T]) Peek() (T, error)
+
+	// Len returns the number of items in the queue.
+	func (q *Queue[T]) Len() int
+
+	// Cap returns the current capacity of the queue.
+	func (q *Queue[T]) Cap() int
+
+	// Clear removes all the data from the queue.
+	func (q *Queue[T]) Clear()
 
 	// Operate accepts a function that takes data and calls
 	// the specified function for every piece of data found.
-	func (q *Queue) Operate(f func(d *Data) error) error
+	func (q *Queue[T]) Operate(f func(d T) error) error
 */
 
 package queue_test
@@ -51,14 +76,14 @@ func TestNew(t *testing.T) {
 		t.Logf("\tTest 0:\tWhen creating a new queue with invalid capacity.")
 		{
 			var cap int
-			_, err := queue.New(cap)
+			_, err := queue.New[string](cap)
 			if err == nil {
 				t.Fatalf("\t%s\tTest 0:\tShould not be able to create a queue for %d items : %v", failed, cap, err)
 			}
 			t.Logf("\t%s\tTest 0:\tShould not be able to create a queue for %d items.", succeed, cap)
 
 			cap = -1
-			_, err = queue.New(cap)
+			_, err = queue.New[string](cap)
 			if err == nil {
 				t.Fatalf("\t%s\tTest 0:\tShould not be able to create a queue for %d items : %v", failed, cap, err)
 			}
@@ -74,7 +99,7 @@ func TestEnqueue(t *testing.T) {
 		const items = 5
 		t.Logf("\tTest 0:\tWhen enqueuing %d items", items)
 		{
-			q, err := queue.New(items)
+			q, err := queue.New[string](items)
 			if err != nil {
 				t.Fatalf("\t%s\tTest 0:\tShould be able to create a queue for %d items : %v", failed, items, err)
 			}
@@ -84,7 +109,7 @@ func TestEnqueue(t *testing.T) {
 			for i := 0; i < items; i++ {
 				name := fmt.Sprintf("Name%d", i)
 				orgData += name
-				if err := q.Enqueue(&queue.Data{Name: name}); err != nil {
+				if err := q.Enqueue(name); err != nil {
 					t.Fatalf("\t%s\tTest 0:\tShould be able to enqueue item %d in the queue : %v", failed, i, err)
 				}
 			}
@@ -96,8 +121,8 @@ func TestEnqueue(t *testing.T) {
 			t.Logf("\t%s\tTest 0:\tShould be able to enqueue %d items.", succeed, items)
 
 			var data string
-			f := func(d *queue.Data) error {
-				data += d.Name
+			f := func(d string) error {
+				data += d
 				return nil
 			}
 			if err := q.Operate(f); err != nil {
@@ -121,7 +146,7 @@ func TestDequeue(t *testing.T) {
 		const items = 5
 		t.Logf("\tTest 0:\tWhen dequeuing %d items", items)
 		{
-			q, err := queue.New(items)
+			q, err := queue.New[string](items)
 			if err != nil {
 				t.Fatalf("\t%s\tTest 0:\tShould be able to create a queue for %d items : %v", failed, items, err)
 			}
@@ -131,7 +156,7 @@ func TestDequeue(t *testing.T) {
 			for i := 0; i < items; i++ {
 				name := fmt.Sprintf("Name%d", i)
 				orgData += name
-				if err := q.Enqueue(&queue.Data{Name: name}); err != nil {
+				if err := q.Enqueue(name); err != nil {
 					t.Fatalf("\t%s\tTest 0:\tShould be able to enqueue item %d in the queue : %v", failed, i+1, err)
 				}
 			}
@@ -148,7 +173,7 @@ func TestDequeue(t *testing.T) {
 				if err != nil {
 					t.Fatalf("\t%s\tTest 0:\tShould be able to dequeue an item from the queue : %d, %v", failed, i+1, err)
 				}
-				data += d.Name
+				data += d
 			}
 
 			if data != orgData {
@@ -167,14 +192,14 @@ func TestEnqueueFull(t *testing.T) {
 		const items = 5
 		t.Logf("\tTest 0:\tWhen enqueuing %d items", items)
 		{
-			q, err := queue.New(items)
+			q, err := queue.New[string](items)
 			if err != nil {
 				t.Fatalf("\t%s\tTest 0:\tShould be able to create a queue for %d items : %v", failed, items, err)
 			}
 			t.Logf("\t%s\tTest 0:\tShould be able to create a queue for %d items.", succeed, items)
 
 			for i := 0; i < items; i++ {
-				if err := q.Enqueue(&queue.Data{Name: "test"}); err != nil {
+				if err := q.Enqueue("test"); err != nil {
 					t.Fatalf("\t%s\tTest 0:\tShould be able to enqueue item %d in the queue : %v", failed, i+1, err)
 				}
 			}
@@ -186,7 +211,7 @@ func TestEnqueueFull(t *testing.T) {
 			}
 			t.Logf("\t%s\tTest 0:\tShould be able to see queue is full.", succeed)
 
-			if err := q.Enqueue(&queue.Data{Name: "test"}); err == nil {
+			if err := q.Enqueue("test"); err == nil {
 				t.Fatalf("\t%s\tTest 0:\tShould not be able to enqueue another item in the queue.", failed)
 			}
 			t.Logf("\t%s\tTest 0:\tShould not be able to enqueue another item in the queue.", succeed)
@@ -199,7 +224,7 @@ func TestEnqueueFull(t *testing.T) {
 			t.Logf("\t%s\tTest 0:\tShould be able to dequeue %d items from the queue.", succeed, items-1)
 
 			for i := 0; i < items-1; i++ {
-				if err := q.Enqueue(&queue.Data{Name: "test"}); err != nil {
+				if err := q.Enqueue("test"); err != nil {
 					t.Fatalf("\t%s\tTest 0:\tShould be able to enqueue item %d back in the queue : %v", failed, i+1, err)
 				}
 			}
@@ -211,7 +236,7 @@ func TestEnqueueFull(t *testing.T) {
 			}
 			t.Logf("\t%s\tTest 0:\tShould be able to see queue is full.", succeed)
 
-			if err := q.Enqueue(&queue.Data{Name: "test"}); err == nil {
+			if err := q.Enqueue("test"); err == nil {
 				t.Fatalf("\t%s\tTest 0:\tShould not be able to enqueue another item in the queue.", failed)
 			}
 			t.Logf("\t%s\tTest 0:\tShould not be able to enqueue another item in the queue.", succeed)
@@ -226,14 +251,14 @@ func TestDequeueEmpty(t *testing.T) {
 		const items = 5
 		t.Logf("\tTest 0:\tWhen enqueuing %d items", items)
 		{
-			q, err := queue.New(items)
+			q, err := queue.New[string](items)
 			if err != nil {
 				t.Fatalf("\t%s\tTest 0:\tShould be able to create a queue for %d items : %v", failed, items, err)
 			}
 			t.Logf("\t%s\tTest 0:\tShould be able to create a queue for %d items.", succeed, items)
 
 			for i := 0; i < items; i++ {
-				if err := q.Enqueue(&queue.Data{Name: "test"}); err != nil {
+				if err := q.Enqueue("test"); err != nil {
 					t.Fatalf("\t%s\tTest 0:\tShould be able to enqueue item %d in the queue : %v", failed, i+1, err)
 				}
 			}
@@ -258,7 +283,7 @@ func TestDequeueEmpty(t *testing.T) {
 			}
 			t.Logf("\t%s\tTest 0:\tShould be able to see queue has 1 item.", succeed)
 
-			if err := q.Enqueue(&queue.Data{Name: "test"}); err != nil {
+			if err := q.Enqueue("test"); err != nil {
 				t.Fatalf("\t%s\tTest 0:\tShould be able to enqueue another item in the queue.", failed)
 			}
 			t.Logf("\t%s\tTest 0:\tShould be able to enqueue another item in the queue.", succeed)
@@ -283,3 +308,120 @@ func TestDequeueEmpty(t *testing.T) {
 		}
 	}
 }
+
+// TestGrow validates the Grow and GrowShrink modes.
+func TestGrow(t *testing.T) {
+	t.Log("Given the need to test a queue that grows.")
+	{
+		const items = 100
+		for testID, mode := range []queue.Mode{queue.Grow, queue.GrowShrink} {
+			t.Logf("\tTest %d:\tWhen enqueuing %d items in a queue with mode %d", testID, items, mode)
+			{
+				q, err := queue.NewWithMode[int](3, mode)
+				if err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to create a queue : %v", failed, testID, err)
+				}
+
+				// Move the pointers forward so the data circles around
+				// before the queue grows.
+				for i := 0; i < 2; i++ {
+					q.Enqueue(-1)
+					q.Dequeue()
+				}
+
+				for i := 0; i < items; i++ {
+					if err := q.Enqueue(i); err != nil {
+						t.Fatalf("\t%s\tTest %d:\tShould be able to enqueue item %d : %v", failed, testID, i, err)
+					}
+				}
+				t.Logf("\t%s\tTest %d:\tShould be able to enqueue %d items.", succeed, testID, items)
+
+				if q.Len() != items || q.Cap() < items {
+					t.Logf("\t%s\tTest %d:\tShould have grown to hold every item.", failed, testID)
+					t.Fatalf("\t\tTest %d:\tGot %d/%d, Expected %d.", testID, q.Len(), q.Cap(), items)
+				}
+				t.Logf("\t%s\tTest %d:\tShould have grown to hold every item.", succeed, testID)
+
+				if d, err := q.Peek(); err != nil || d != 0 {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to peek the oldest item : %d, %v", failed, testID, d, err)
+				}
+				t.Logf("\t%s\tTest %d:\tShould be able to peek the oldest item.", succeed, testID)
+
+				for i := 0; i < items-2; i++ {
+					d, err := q.Dequeue()
+					if err != nil || d != i {
+						t.Fatalf("\t%s\tTest %d:\tShould dequeue items in FIFO order : %d, %v, Expected %d", failed, testID, d, err, i)
+					}
+				}
+				t.Logf("\t%s\tTest %d:\tShould dequeue items in FIFO order.", succeed, testID)
+
+				switch mode {
+				case queue.Grow:
+					if q.Cap() < items {
+						t.Fatalf("\t%s\tTest %d:\tShould not shrink : %d.", failed, testID, q.Cap())
+					}
+					t.Logf("\t%s\tTest %d:\tShould not shrink.", succeed, testID)
+
+				case queue.GrowShrink:
+					if q.Cap() > 8 {
+						t.Fatalf("\t%s\tTest %d:\tShould shrink : %d.", failed, testID, q.Cap())
+					}
+					t.Logf("\t%s\tTest %d:\tShould shrink.", succeed, testID)
+				}
+
+				var data []int
+				q.Operate(func(d int) error {
+					data = append(data, d)
+					return nil
+				})
+				if len(data) != 2 || data[0] != items-2 || data[1] != items-1 {
+					t.Logf("\t%s\tTest %d:\tShould keep the remaining items in order.", failed, testID)
+					t.Fatalf("\t\tTest %d:\tGot %v, Expected %v.", testID, data, []int{items - 2, items - 1})
+				}
+				t.Logf("\t%s\tTest %d:\tShould keep the remaining items in order.", succeed, testID)
+
+				q.Clear()
+				if _, err := q.Peek(); err == nil || q.Len() != 0 {
+					t.Fatalf("\t%s\tTest %d:\tShould be empty after clear.", failed, testID)
+				}
+				t.Logf("\t%s\tTest %d:\tShould be empty after clear.", succeed, testID)
+			}
+		}
+	}
+}
+
+// TestOverwrite validates the Overwrite mode.
+func TestOverwrite(t *testing.T) {
+	t.Log("Given the need to test a queue that drops the oldest items.")
+	{
+		const items = 5
+		t.Logf("\tTest 0:\tWhen enqueuing more than %d items", items)
+		{
+			q, err := queue.NewWithMode[int](items, queue.Overwrite)
+			if err != nil {
+				t.Fatalf("\t%s\tTest 0:\tShould be able to create a queue : %v", failed, err)
+			}
+
+			for i := 0; i < 3*items+2; i++ {
+				if err := q.Enqueue(i); err != nil {
+					t.Fatalf("\t%s\tTest 0:\tShould be able to enqueue item %d : %v", failed, i, err)
+				}
+			}
+			t.Logf("\t%s\tTest 0:\tShould be able to enqueue every item.", succeed)
+
+			if q.Len() != items || q.Cap() != items {
+				t.Logf("\t%s\tTest 0:\tShould keep the capacity.", failed)
+				t.Fatalf("\t\tTest 0:\tGot %d/%d, Expected %d.", q.Len(), q.Cap(), items)
+			}
+			t.Logf("\t%s\tTest 0:\tShould keep the capacity.", succeed)
+
+			for i := 2*items + 2; i < 3*items+2; i++ {
+				d, err := q.Dequeue()
+				if err != nil || d != i {
+					t.Fatalf("\t%s\tTest 0:\tShould only keep the newest items : %d, %v, Expected %d", failed, d, err, i)
+				}
+			}
+			t.Logf("\t%s\tTest 0:\tShould only keep the newest items.", succeed)
+		}
+	}
+}
@@ -6,39 +6,35 @@ package stack
 
 import "errors"
 
-// Data represents what is being stored on the stack.
-type Data struct {
-	Name string
-}
-
 // Stack represents a stack of data.
-type Stack struct {
-	data []*Data
+type Stack[T any] struct {
+	data []T
 }
 
 // Make allows the creation of a stack with an initial
 // capacity for efficiency. Otherwise a stack can be
 // used in its zero value state.
-func Make(cap int) *Stack {
-	return &Stack{
-		data: make([]*Data, 0, cap),
+func Make[T any](cap int) *Stack[T] {
+	return &Stack[T]{
+		data: make([]T, 0, cap),
 	}
 }
 
 // Count returns the number of items in the stack.
-func (s *Stack) Count() int {
+func (s *Stack[T]) Count() int {
 	return len(s.data)
 }
 
 // Push adds data into the top of the stack.
-func (s *Stack) Push(data *Data) {
+func (s *Stack[T]) Push(data T) {
 	s.data = append(s.data, data)
 }
 
 // Pop removes data from the top of the stack.
-func (s *Stack) Pop() (*Data, error) {
+func (s *Stack[T]) Pop() (T, error) {
 	if len(s.data) == 0 {
-		return nil, errors.New("stack empty")
+		var zero T
+		return zero, errors.New("stack empty")
 	}
 
 	// Calculate the top level index.
@@ -47,7 +43,10 @@ func (s *Stack) Pop() (*Data, error) {
 	// Copy the data from that index position.
 	data := s.data[idx]
 
-	// Remove the top level index from the slice.
+	// Clear the top level index so the data can be garbage
+	// collected and remove it from the slice.
+	var zero T
+	s.data[idx] = zero
 	s.data = s.data[:idx]
 
 	return data, nil
@@ -56,9 +55,10 @@ func (s *Stack) Pop() (*Data, error) {
 // Peek provides the data stored on the stack based
 // on the level from the bottom. A value of 0 would
 // return the top piece of data.
-func (s *Stack) Peek(level int) (*Data, error) {
+func (s *Stack[T]) Peek(level int) (T, error) {
 	if level < 0 || level > (len(s.data)-1) {
-		return nil, errors.New("invalid level position")
+		var zero T
+		return zero, errors.New("invalid level position")
 	}
 	idx := (len(s.data) - 1) - level
 	return s.data[idx], nil
@@ -67,7 +67,7 @@ func (s *Stack) Peek(level int) (*Data, error) {
 // Operate accepts a function that takes data and calls
 // the specified function for every piece of data found.
 // It traverses from the top down through the stack.
-func (s *Stack) Operate(f func(data *Data) error) error {
+func (s *Stack[T]) Operate(f func(data T) error) error {
 	for i := len(s.data) - 1; i > -1; i-- {
 		if err := f(s.data[i]); err != nil {
 			return err
@@ -4,39 +4,34 @@
 
 	package stack
 
-	// Data represents what is being stored on the stack.
-	type Data struct {
-		Name string
-	}
-
 	// Stack represents a stack of data.
-	type Stack struct {
-		data []*Data
+	type Stack[T any] struct {
+		data []T
 	}
 
 	// Make allows the creation of a stack with an initial
 	// capacity for efficiency. Otherwise a stack can be
 	// used in its zero value state.
-	func Make(cap int) *Stack
+	func Make[T any](cap int) *Stack[T]
 
 	// Count returns the number of items in the stack.
-	func (s *Stack) Count() int
+	func (s *Stack[T]) Count() int
 
 	// Push adds data into the top of the stack.
-	func (s *Stack) Push(data *Data)
+	func (s *Stack[T]) Push(data T)
 
 	// Pop removes data from the top of the stack.
-	func (s *Stack) Pop() (*Data, error)
+	func (s *Stack[T]) Pop() (T, error)
 
 	// Peek provides the data stored on the stack based
 	// on the level from the bottom. A value of 0 would
 	// return the top piece of data.
-	func (s *Stack) Peek(level int) (*Data, error)
+	func (s *Stack[T]) Peek(level int) (T, error)
 
 	// Operate accepts a function that takes data and calls
 	// the specified function for every piece of data found.
 	// It traverses from the top down through the stack.
-	func (s *Stack) Operate(f func(data *Data) error) error
+	func (s *Stack[T]) Operate(f func(data T) error) error
 */
 
 package stack_test
@@ -58,13 +53,13 @@ func TestPush(t *testing.T) {
 		const items = 5
 		t.Logf("\tTest 0:\tWhen pushing %d items", items)
 		{
-			var s stack.Stack
+			var s stack.Stack[string]
 
 			var orgData string
 			for i := 0; i < items; i++ {
 				name := fmt.Sprintf("Name%d", i)
 				orgData = name + orgData
-				s.Push(&stack.Data{Name: name})
+				s.Push(name)
 			}
 
 			if s.Count() != items {
@@ -74,8 +69,8 @@ func TestPush(t *testing.T) {
 			t.Logf("\t%s\tTest 0:\tShould be able to push %d items.", succeed, items)
 
 			var data string
-			f := func(d *stack.Data) error {
-				data += d.Name
+			f := func(d string) error {
+				data += d
 				return nil
 			}
 			if err := s.Operate(f); err != nil {
@@ -99,7 +94,7 @@ func TestPop(t *testing.T) {
 		const items = 5
 		t.Logf("\tTest 0:\tWhen popping %d items", items)
 		{
-			var s stack.Stack
+			var s stack.Stack[string]
 
 			if _, err := s.Pop(); err == nil {
 				t.Fatalf("\t%s\tTest 0:\tShould not be able to pop an empty stack : %v", failed, err)
@@ -109,7 +104,7 @@ func TestPop(t *testing.T) {
 			for i := 0; i < items; i++ {
 				name := fmt.Sprintf("Name%d", i)
 				orgData = name + orgData
-				s.Push(&stack.Data{Name: name})
+				s.Push(name)
 			}
 
 			if s.Count() != items {
@@ -124,7 +119,7 @@ func TestPop(t *testing.T) {
 				if err != nil {
 					t.Logf("\t%s\tTest 0:\tShould be able to pop an item.", failed)
 				}
-				popData += data.Name
+				popData += data
 			}
 
 			if s.Count() != 0 {
@@ -149,7 +144,7 @@ func TestPeek(t *testing.T) {
 		const items = 5
 		t.Logf("\tTest 0:\tWhen peeking %d items", items)
 		{
-			s := stack.Make(5)
+			s := stack.Make[string](5)
 
 			if _, err := s.Peek(0); err == nil {
 				t.Fatalf("\t%s\tTest 0:\tShould not be able to peek an empty stack : %s", failed, err)
@@ -159,7 +154,7 @@ func TestPeek(t *testing.T) {
 			for i := 0; i < items; i++ {
 				name := fmt.Sprintf("Name%d", i)
 				orgData = name + orgData
-				s.Push(&stack.Data{Name: name})
+				s.Push(name)
 			}
 
 			if s.Count() != items {
@@ -174,7 +169,7 @@ func TestPeek(t *testing.T) {
 				if err != nil {
 					t.Logf("\t%s\tTest 0:\tShould be able to peek an item.", failed)
 				}
-				popData += data.Name
+				popData += data
 			}
 
 			if s.Count() != items {
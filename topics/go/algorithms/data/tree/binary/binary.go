@@ -48,12 +48,33 @@ func (t *Tree) Delete(key int) error {
 		return err
 	}
 
-	if fakeParent.right == nil {
-		t.root = nil
-	}
+	// The root may have been replaced by one of its children.
+	t.root = fakeParent.right
 	return nil
 }
 
+// Len returns the number of values in the tree.
+func (t *Tree) Len() int {
+	return t.root.count()
+}
+
+// Rank returns the number of keys in the tree that are less than the
+// specified key. When the key exists, this is its zero based position
+// in ascending key order.
+func (t *Tree) Rank(key int) int {
+	return t.root.rank(key)
+}
+
+// Select returns the value with the k-th smallest key in the tree,
+// where k is zero based.
+func (t *Tree) Select(k int) (Data, error) {
+	if k < 0 || k >= t.root.count() {
+		return Data{}, errors.New("position out of range")
+	}
+
+	return t.root.selectKth(k), nil
+}
+
 // PreOrder traversal get the root node then traversing its child
 // nodes recursively.
 // Use cases: copying tree, mapping prefix notation.
@@ -113,6 +134,7 @@ func (t *Tree) PostOrder() []Data {
 type node struct {
 	data  Data
 	level int
+	size  int
 	tree  *Tree
 	left  *node
 	right *node
@@ -133,11 +155,20 @@ func (n *node) height() int {
 	return n.level
 }
 
+// count returns the number of nodes in the subtree rooted at the node,
+// including the node itself.
+func (n *node) count() int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
 // insert adds the node into the tree and makes sure the
 // tree stays balanced.
 func (n *node) insert(t *Tree, data Data) *node {
 	if n == nil {
-		return &node{data: data, level: 1, tree: t}
+		return &node{data: data, level: 1, size: 1, tree: t}
 	}
 
 	switch {
@@ -152,6 +183,7 @@ func (n *node) insert(t *Tree, data Data) *node {
 	}
 
 	n.level = max(n.left.height(), n.right.height()) + 1
+	n.size = n.left.count() + n.right.count() + 1
 	return n.rebalance()
 }
 
@@ -173,6 +205,41 @@ func (n *node) find(key int) (Data, error) {
 	}
 }
 
+// rank counts the keys in the subtree that are less than the specified key.
+func (n *node) rank(key int) int {
+	if n == nil {
+		return 0
+	}
+
+	switch {
+	case key <= n.data.Key:
+		return n.left.rank(key)
+
+	default:
+
+		// Every key in the left subtree and this key are less than
+		// the specified key.
+		return n.left.count() + 1 + n.right.rank(key)
+	}
+}
+
+// selectKth returns the value with the k-th smallest key in the subtree.
+// k must be between 0 and the size of the subtree minus one.
+func (n *node) selectKth(k int) Data {
+	leftSize := n.left.count()
+
+	switch {
+	case k < leftSize:
+		return n.left.selectKth(k)
+
+	case k == leftSize:
+		return n.data
+
+	default:
+		return n.right.selectKth(k - leftSize - 1)
+	}
+}
+
 // balRatio provides information about the balance ratio
 // of the node.
 func (n *node) balRatio() int {
@@ -192,6 +259,8 @@ func (n *node) rotateLeft() *node {
 	r.left = n
 	n.level = max(n.left.height(), n.right.height()) + 1
 	r.level = max(r.left.height(), r.right.height()) + 1
+	n.size = n.left.count() + n.right.count() + 1
+	r.size = r.left.count() + r.right.count() + 1
 	return r
 }
 
@@ -208,6 +277,8 @@ func (n *node) rotateRight() *node {
 	l.right = n
 	n.level = max(n.left.height(), n.right.height()) + 1
 	l.level = max(l.left.height(), l.right.height()) + 1
+	n.size = n.left.count() + n.right.count() + 1
+	l.size = l.left.count() + l.right.count() + 1
 	return l
 }
 
@@ -222,6 +293,7 @@ func (n *node) rotateLeftRight() *node {
 	n.left = n.left.rotateLeft()
 	n = n.rotateRight()
 	n.level = max(n.left.height(), n.right.height()) + 1
+	n.size = n.left.count() + n.right.count() + 1
 	return n
 }
 
@@ -236,6 +308,7 @@ func (n *node) rotateRightLeft() *node {
 	n.right = n.right.rotateRight()
 	n = n.rotateLeft()
 	n.level = max(n.left.height(), n.right.height()) + 1
+	n.size = n.left.count() + n.right.count() + 1
 	return n
 }
 
@@ -292,7 +365,8 @@ func (n *node) replaceNode(parent, replacement *node) error {
 // delete removes an element from the tree. It is an error to try
 // deleting an element that does not exist. In order to remove an
 // element properly, Delete needs to know the node’s parent node.
-// Parent must not be nil.
+// Parent must not be nil. The size of every node on the path to the
+// removed node is reduced by one.
 func (n *node) delete(key int, parent *node) error {
 	if n == nil {
 		return errors.New("value to be deleted does not exist in the tree")
@@ -300,10 +374,18 @@ func (n *node) delete(key int, parent *node) error {
 
 	switch {
 	case key < n.data.Key:
-		return n.left.delete(key, n)
+		if err := n.left.delete(key, n); err != nil {
+			return err
+		}
+		n.size--
+		return nil
 
 	case key > n.data.Key:
-		return n.right.delete(key, n)
+		if err := n.right.delete(key, n); err != nil {
+			return err
+		}
+		n.size--
+		return nil
 
 	default:
 		switch {
@@ -317,9 +399,16 @@ func (n *node) delete(key int, parent *node) error {
 			n.replaceNode(parent, n.left)
 			return nil
 		}
-		replacement, replParent := n.left.findMax(n)
+		replacement, _ := n.left.findMax(n)
 		n.data = replacement.data
-		return replacement.delete(replacement.data.Key, replParent)
+
+		// Delete the replacement starting from the left child so
+		// the size of every node on the path is updated.
+		if err := n.left.delete(replacement.data.Key, n); err != nil {
+			return err
+		}
+		n.size--
+		return nil
 	}
 }
 
@@ -0,0 +1,198 @@
+package binary_test
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/ardanlabs/gotraining/topics/go/algorithms/data/tree/binary"
+)
+
+const succeed = "\u2713"
+const failed = "\u2717"
+
+// generateTree is for generate a tree of unique random keys. It returns
+// the tree and the keys in ascending order.
+func generateTree(n int, rnd *rand.Rand) (*binary.Tree, []int) {
+	var tree binary.Tree
+	keys := make([]int, 0, n)
+	seen := make(map[int]bool)
+
+	for len(keys) < n {
+		key := rnd.Intn(10 * n)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		keys = append(keys, key)
+		tree.Insert(binary.Data{Key: key})
+	}
+
+	sort.Ints(keys)
+	return &tree, keys
+}
+
+// TestCursor validates the ordered iteration functionality.
+func TestCursor(t *testing.T) {
+	t.Log("Given the need to test ordered iteration over the tree.")
+	{
+		rnd := rand.New(rand.NewSource(1))
+		tree, keys := generateTree(500, rnd)
+
+		testID := 0
+		t.Logf("\tTest %d:\tWhen moving a cursor forward and backward.", testID)
+		{
+			var got []int
+			for c := tree.First(); c.Valid(); c.Next() {
+				got = append(got, c.Data().Key)
+			}
+			if !equal(got, keys) {
+				t.Fatalf("\t%s\tTest %d:\tShould see every key in ascending order.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould see every key in ascending order.", succeed, testID)
+
+			got = got[:0]
+			for c := tree.Last(); c.Valid(); c.Prev() {
+				got = append(got, c.Data().Key)
+			}
+			for i, j := 0, len(got)-1; i < j; i, j = i+1, j-1 {
+				got[i], got[j] = got[j], got[i]
+			}
+			if !equal(got, keys) {
+				t.Fatalf("\t%s\tTest %d:\tShould see every key in descending order.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould see every key in descending order.", succeed, testID)
+
+			c := tree.First()
+			for i := 0; i < 10; i++ {
+				c.Next()
+			}
+			c.Prev()
+			if c.Data().Key != keys[9] {
+				t.Errorf("\t%s\tTest %d:\tShould be able to change direction.", failed, testID)
+				t.Fatalf("\t\tTest %d:\tGot %d, Expected %d", testID, c.Data().Key, keys[9])
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to change direction.", succeed, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen seeking to a key.", testID)
+		{
+			for _, key := range []int{-1, keys[0], keys[100], keys[100] + 1, keys[len(keys)-1], keys[len(keys)-1] + 1} {
+				c := tree.Seek(key)
+
+				idx := sort.SearchInts(keys, key)
+				if idx == len(keys) {
+					if c.Valid() {
+						t.Fatalf("\t%s\tTest %d:\tShould not be valid after the largest key : %d", failed, testID, key)
+					}
+					continue
+				}
+
+				if !c.Valid() || c.Data().Key != keys[idx] {
+					t.Errorf("\t%s\tTest %d:\tShould be positioned on the ceiling of %d.", failed, testID, key)
+					t.Fatalf("\t\tTest %d:\tGot %d, Expected %d", testID, c.Data().Key, keys[idx])
+				}
+
+				n := 1
+				for c.Next() {
+					n++
+				}
+				if n != len(keys)-idx {
+					t.Errorf("\t%s\tTest %d:\tShould see the remaining keys after seeking to %d.", failed, testID, key)
+					t.Fatalf("\t\tTest %d:\tGot %d, Expected %d", testID, n, len(keys)-idx)
+				}
+			}
+			t.Logf("\t%s\tTest %d:\tShould be positioned on the ceiling of the key.", succeed, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen looking for the min, max, floor and ceiling.", testID)
+		{
+			min, err := tree.Min()
+			if err != nil || min.Key != keys[0] {
+				t.Fatalf("\t%s\tTest %d:\tShould find the min key : %d, %v", failed, testID, min.Key, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould find the min key.", succeed, testID)
+
+			max, err := tree.Max()
+			if err != nil || max.Key != keys[len(keys)-1] {
+				t.Fatalf("\t%s\tTest %d:\tShould find the max key : %d, %v", failed, testID, max.Key, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould find the max key.", succeed, testID)
+
+			for key := keys[0] - 1; key <= keys[len(keys)-1]+1; key++ {
+				idx := sort.SearchInts(keys, key)
+
+				ceiling, err := tree.Ceiling(key)
+				switch {
+				case idx == len(keys):
+					if err == nil {
+						t.Fatalf("\t%s\tTest %d:\tShould not find a ceiling for %d.", failed, testID, key)
+					}
+				case err != nil || ceiling.Key != keys[idx]:
+					t.Fatalf("\t%s\tTest %d:\tShould find the ceiling for %d : %d, %v", failed, testID, key, ceiling.Key, err)
+				}
+
+				if idx == len(keys) || keys[idx] != key {
+					idx--
+				}
+				floor, err := tree.Floor(key)
+				switch {
+				case idx < 0:
+					if err == nil {
+						t.Fatalf("\t%s\tTest %d:\tShould not find a floor for %d.", failed, testID, key)
+					}
+				case err != nil || floor.Key != keys[idx]:
+					t.Fatalf("\t%s\tTest %d:\tShould find the floor for %d : %d, %v", failed, testID, key, floor.Key, err)
+				}
+			}
+			t.Logf("\t%s\tTest %d:\tShould find the floor and ceiling for every key.", succeed, testID)
+
+			var empty binary.Tree
+			if _, err := empty.Min(); err == nil {
+				t.Fatalf("\t%s\tTest %d:\tShould not find the min of an empty tree.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould not find the min of an empty tree.", succeed, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen ranging over a set of keys.", testID)
+		{
+			lo, hi := keys[50]-1, keys[150]
+			var got []int
+			tree.Range(lo, hi, func(data binary.Data) bool {
+				got = append(got, data.Key)
+				return true
+			})
+			if !equal(got, keys[50:151]) {
+				t.Fatalf("\t%s\tTest %d:\tShould see every key between %d and %d.", failed, testID, lo, hi)
+			}
+			t.Logf("\t%s\tTest %d:\tShould see every key between %d and %d.", succeed, testID, lo, hi)
+
+			count := 0
+			tree.Range(lo, hi, func(data binary.Data) bool {
+				count++
+				return count < 5
+			})
+			if count != 5 {
+				t.Errorf("\t%s\tTest %d:\tShould stop when fn returns false.", failed, testID)
+				t.Fatalf("\t\tTest %d:\tGot %d, Expected %d", testID, count, 5)
+			}
+			t.Logf("\t%s\tTest %d:\tShould stop when fn returns false.", succeed, testID)
+		}
+	}
+}
+
+// equal reports whether both slices have the same keys.
+func equal(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,258 @@
+package binary
+
+import (
+	"errors"
+)
+
+// Cursor provides ordered iteration over the tree without materialising
+// all the values into a slice. A cursor keeps the path from the root to
+// the current node, so moving to the next or previous value is O(1)
+// amortised and O(log n) in the worst case.
+//
+// A cursor is invalidated by any Insert or Delete on the tree.
+type Cursor struct {
+	path []*node
+}
+
+// Valid reports whether the cursor is positioned on a value.
+func (c *Cursor) Valid() bool {
+	return len(c.path) > 0
+}
+
+// Data returns the value the cursor is positioned on. It returns the
+// zero value when the cursor is not valid.
+func (c *Cursor) Data() Data {
+	if len(c.path) == 0 {
+		return Data{}
+	}
+	return c.path[len(c.path)-1].data
+}
+
+// Next moves the cursor to the next value in ascending key order. It
+// reports whether the cursor is still valid.
+func (c *Cursor) Next() bool {
+	if len(c.path) == 0 {
+		return false
+	}
+
+	// If the current node has a right subtree, the next value is
+	// the leftmost node of that subtree.
+	n := c.path[len(c.path)-1]
+	if n.right != nil {
+		c.pushLeft(n.right)
+		return true
+	}
+
+	// Otherwise walk up until we come up from a left child. That
+	// parent is the next value.
+	for {
+		child := c.path[len(c.path)-1]
+		c.path = c.path[:len(c.path)-1]
+		if len(c.path) == 0 {
+			return false
+		}
+		if c.path[len(c.path)-1].left == child {
+			return true
+		}
+	}
+}
+
+// Prev moves the cursor to the previous value in ascending key order. It
+// reports whether the cursor is still valid.
+func (c *Cursor) Prev() bool {
+	if len(c.path) == 0 {
+		return false
+	}
+
+	// If the current node has a left subtree, the previous value is
+	// the rightmost node of that subtree.
+	n := c.path[len(c.path)-1]
+	if n.left != nil {
+		c.pushRight(n.left)
+		return true
+	}
+
+	// Otherwise walk up until we come up from a right child. That
+	// parent is the previous value.
+	for {
+		child := c.path[len(c.path)-1]
+		c.path = c.path[:len(c.path)-1]
+		if len(c.path) == 0 {
+			return false
+		}
+		if c.path[len(c.path)-1].right == child {
+			return true
+		}
+	}
+}
+
+// pushLeft adds the node and all its left descendants to the path.
+func (c *Cursor) pushLeft(n *node) {
+	for ; n != nil; n = n.left {
+		c.path = append(c.path, n)
+	}
+}
+
+// pushRight adds the node and all its right descendants to the path.
+func (c *Cursor) pushRight(n *node) {
+	for ; n != nil; n = n.right {
+		c.path = append(c.path, n)
+	}
+}
+
+// =============================================================================
+
+// First returns a cursor positioned on the smallest key in the tree.
+func (t *Tree) First() *Cursor {
+	var c Cursor
+	c.pushLeft(t.root)
+	return &c
+}
+
+// Last returns a cursor positioned on the largest key in the tree.
+func (t *Tree) Last() *Cursor {
+	var c Cursor
+	c.pushRight(t.root)
+	return &c
+}
+
+// Seek returns a cursor positioned on the smallest key that is greater
+// than or equal to the specified key. The cursor is not valid when there
+// is no such key.
+func (t *Tree) Seek(key int) *Cursor {
+	var c Cursor
+
+	// Walk down the tree remembering the path. The last node on the
+	// path with a key greater than or equal to the specified key is
+	// where the cursor needs to be positioned.
+	last := -1
+	for n := t.root; n != nil; {
+		c.path = append(c.path, n)
+
+		switch {
+		case key == n.data.Key:
+			return &c
+
+		case key < n.data.Key:
+			last = len(c.path) - 1
+			n = n.left
+
+		default:
+			n = n.right
+		}
+	}
+
+	c.path = c.path[:last+1]
+	return &c
+}
+
+// Min returns the value with the smallest key in the tree.
+func (t *Tree) Min() (Data, error) {
+	if t.root == nil {
+		return Data{}, errors.New("cannot find min from an empty tree")
+	}
+
+	n := t.root
+	for n.left != nil {
+		n = n.left
+	}
+	return n.data, nil
+}
+
+// Max returns the value with the largest key in the tree.
+func (t *Tree) Max() (Data, error) {
+	if t.root == nil {
+		return Data{}, errors.New("cannot find max from an empty tree")
+	}
+
+	n := t.root
+	for n.right != nil {
+		n = n.right
+	}
+	return n.data, nil
+}
+
+// Floor returns the value with the largest key that is less than or
+// equal to the specified key.
+func (t *Tree) Floor(key int) (Data, error) {
+	var floor *node
+	for n := t.root; n != nil; {
+		switch {
+		case key == n.data.Key:
+			return n.data, nil
+
+		case key < n.data.Key:
+			n = n.left
+
+		default:
+			floor = n
+			n = n.right
+		}
+	}
+
+	if floor == nil {
+		return Data{}, errors.New("key not found")
+	}
+	return floor.data, nil
+}
+
+// Ceiling returns the value with the smallest key that is greater than
+// or equal to the specified key.
+func (t *Tree) Ceiling(key int) (Data, error) {
+	var ceiling *node
+	for n := t.root; n != nil; {
+		switch {
+		case key == n.data.Key:
+			return n.data, nil
+
+		case key < n.data.Key:
+			ceiling = n
+			n = n.left
+
+		default:
+			n = n.right
+		}
+	}
+
+	if ceiling == nil {
+		return Data{}, errors.New("key not found")
+	}
+	return ceiling.data, nil
+}
+
+// Range calls fn in ascending key order on each value with a key between
+// lo and hi inclusive. If fn return false stops the iteration. Subtrees
+// that are outside of the range are never visited.
+func (t *Tree) Range(lo, hi int, fn func(data Data) bool) {
+	t.root.rangeKeys(lo, hi, fn)
+}
+
+// rangeKeys traverses the node in order, skipping the subtrees that are
+// outside of the range. It reports whether the iteration should continue.
+func (n *node) rangeKeys(lo, hi int, fn func(data Data) bool) bool {
+	if n == nil {
+		return true
+	}
+
+	// The left subtree can only have keys in the range when this
+	// key is greater than lo.
+	if lo < n.data.Key {
+		if !n.left.rangeKeys(lo, hi, fn) {
+			return false
+		}
+	}
+
+	if lo <= n.data.Key && n.data.Key <= hi {
+		if !fn(n.data) {
+			return false
+		}
+	}
+
+	// The right subtree can only have keys in the range when this
+	// key is less than hi.
+	if n.data.Key < hi {
+		return n.right.rangeKeys(lo, hi, fn)
+	}
+
+	return true
+}
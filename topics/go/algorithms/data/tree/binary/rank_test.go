@@ -0,0 +1,100 @@
+package binary
+
+import (
+	"sort"
+	"testing"
+	"testing/quick"
+)
+
+const succeed = "\u2713"
+const failed = "\u2717"
+
+// op is a random insert or delete applied to the tree.
+type op struct {
+	Delete bool
+	Key    uint8
+}
+
+// checkSizes validates the size of every node in the subtree and
+// returns the number of nodes found.
+func checkSizes(t *testing.T, n *node) int {
+	if n == nil {
+		return 0
+	}
+
+	size := checkSizes(t, n.left) + checkSizes(t, n.right) + 1
+	if n.size != size {
+		t.Errorf("\t\tnode %d: Got size %d, Expected %d", n.data.Key, n.size, size)
+	}
+	return size
+}
+
+// TestRankSelect validates subtree sizes and order statistics after
+// random sequences of inserts and deletes.
+func TestRankSelect(t *testing.T) {
+	t.Log("Given the need to test order statistics on the tree.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen applying random inserts and deletes.", testID)
+		{
+			property := func(ops []op) bool {
+				var tree Tree
+				keys := make(map[int]bool)
+
+				for _, op := range ops {
+					// Keep the keys in a small range so deletes find keys.
+					key := int(op.Key % 64)
+					switch {
+					case op.Delete:
+						err := tree.Delete(key)
+						if keys[key] != (err == nil) {
+							t.Logf("\t\tdelete %d: Got %v, Expected exists %v", key, err, keys[key])
+							return false
+						}
+						delete(keys, key)
+					default:
+						tree.Insert(Data{Key: key})
+						keys[key] = true
+					}
+
+					if checkSizes(t, tree.root) != len(keys) || tree.Len() != len(keys) {
+						return false
+					}
+				}
+
+				sorted := make([]int, 0, len(keys))
+				for key := range keys {
+					sorted = append(sorted, key)
+				}
+				sort.Ints(sorted)
+
+				for k, key := range sorted {
+					data, err := tree.Select(k)
+					if err != nil || data.Key != key {
+						t.Logf("\t\tselect %d: Got %d, %v, Expected %d", k, data.Key, err, key)
+						return false
+					}
+				}
+
+				for key := -1; key <= 64; key++ {
+					if rank := tree.Rank(key); rank != sort.SearchInts(sorted, key) {
+						t.Logf("\t\trank %d: Got %d, Expected %d", key, rank, sort.SearchInts(sorted, key))
+						return false
+					}
+				}
+
+				if _, err := tree.Select(len(sorted)); err == nil {
+					t.Logf("\t\tselect %d: Expected an error", len(sorted))
+					return false
+				}
+
+				return true
+			}
+
+			if err := quick.Check(property, &quick.Config{MaxCount: 500}); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould keep sizes, Rank and Select correct : %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould keep sizes, Rank and Select correct.", succeed, testID)
+		}
+	}
+}
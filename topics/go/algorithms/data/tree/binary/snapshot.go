@@ -0,0 +1,304 @@
+package binary
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+)
+
+// Snapshot format
+//
+//	┌───────┬─────────┬───────┬─────────────────────────────┬──────────┐
+//	│ magic │ version │ count │ key │ len(name) │ name │ ... │ checksum │
+//	└───────┴─────────┴───────┴─────────────────────────────┴──────────┘
+//
+// - magic is the 4 bytes "BTRE"
+// - version is a single byte, currently 1
+// - count, key and len(name) are varints, name is the raw bytes
+// - entries are written in ascending key order
+// - checksum is the big endian CRC-32 (IEEE) of every byte before it
+
+const (
+	snapshotMagic   = "BTRE"
+	snapshotVersion = 1
+
+	// maxNameLen protects against allocating huge buffers when the
+	// length of a name is corrupt.
+	maxNameLen = 1 << 24
+)
+
+// Set of error variables returned when reading a snapshot.
+var (
+	ErrTruncated = errors.New("snapshot is truncated")
+	ErrCorrupt   = errors.New("snapshot is corrupt")
+)
+
+// WriteTo writes a snapshot of the tree to w. It implements the
+// io.WriterTo interface.
+func (t *Tree) WriteTo(w io.Writer) (int64, error) {
+	sw := snapshotWriter{
+		w:   bufio.NewWriter(w),
+		crc: crc32.NewIEEE(),
+	}
+
+	sw.write([]byte(snapshotMagic))
+	sw.write([]byte{snapshotVersion})
+	sw.writeUvarint(uint64(t.root.count()))
+
+	// Write the entries in ascending key order so the tree can be
+	// rebuilt balanced without any rotations.
+	t.root.inOrder(func(n *node) {
+		sw.writeVarint(int64(n.data.Key))
+		sw.writeUvarint(uint64(len(n.data.Name)))
+		sw.write([]byte(n.data.Name))
+	})
+
+	// The checksum is not part of its own calculation.
+	var sum [4]byte
+	binary.BigEndian.PutUint32(sum[:], sw.crc.Sum32())
+	sw.write(sum[:])
+
+	if sw.err == nil {
+		sw.err = sw.w.Flush()
+	}
+	return sw.n, sw.err
+}
+
+// ReadFrom replaces the contents of the tree with the snapshot read from
+// r. It implements the io.ReaderFrom interface. The tree is left unchanged
+// when the snapshot is truncated or corrupt.
+//
+// ReadFrom never reads past the end of the snapshot, so r can be used for
+// whatever follows it. When r doesn't implement io.ByteReader the varints
+// are read one byte at a time, so wrap a slow reader with a bufio.Reader
+// and keep reading from that.
+func (t *Tree) ReadFrom(r io.Reader) (int64, error) {
+	br, ok := r.(snapshotSource)
+	if !ok {
+		br = &byteReader{Reader: r}
+	}
+
+	sr := snapshotReader{
+		r:   br,
+		crc: crc32.NewIEEE(),
+	}
+
+	magic := make([]byte, len(snapshotMagic))
+	if err := sr.read(magic); err != nil {
+		return sr.n, err
+	}
+	if string(magic) != snapshotMagic {
+		return sr.n, fmt.Errorf("%w: invalid magic %q", ErrCorrupt, magic)
+	}
+
+	var version [1]byte
+	if err := sr.read(version[:]); err != nil {
+		return sr.n, err
+	}
+	if version[0] != snapshotVersion {
+		return sr.n, fmt.Errorf("%w: unsupported version %d", ErrCorrupt, version[0])
+	}
+
+	count, err := sr.readUvarint()
+	if err != nil {
+		return sr.n, err
+	}
+
+	// Don't trust the count for the initial allocation since it
+	// could be corrupt.
+	capacity := count
+	if capacity > 1024 {
+		capacity = 1024
+	}
+	values := make([]Data, 0, capacity)
+	for i := uint64(0); i < count; i++ {
+		key, err := sr.readVarint()
+		if err != nil {
+			return sr.n, err
+		}
+
+		// Keys must be unique and in ascending order.
+		if len(values) > 0 && int(key) <= values[len(values)-1].Key {
+			return sr.n, fmt.Errorf("%w: key %d out of order", ErrCorrupt, key)
+		}
+
+		nameLen, err := sr.readUvarint()
+		if err != nil {
+			return sr.n, err
+		}
+		if nameLen > maxNameLen {
+			return sr.n, fmt.Errorf("%w: name length %d too large", ErrCorrupt, nameLen)
+		}
+
+		name := make([]byte, nameLen)
+		if err := sr.read(name); err != nil {
+			return sr.n, err
+		}
+
+		values = append(values, Data{Key: int(key), Name: string(name)})
+	}
+
+	// Calculate the checksum before reading it since it is not part
+	// of its own calculation.
+	expected := sr.crc.Sum32()
+	var sum [4]byte
+	if err := sr.read(sum[:]); err != nil {
+		return sr.n, err
+	}
+	if got := binary.BigEndian.Uint32(sum[:]); got != expected {
+		return sr.n, fmt.Errorf("%w: checksum %08x, expected %08x", ErrCorrupt, got, expected)
+	}
+
+	t.root = build(t, values)
+	return sr.n, nil
+}
+
+// build creates a balanced subtree from values sorted by key. Picking the
+// middle value as the root of every subtree means no rotations are needed,
+// so the tree is built in O(n).
+func build(t *Tree, values []Data) *node {
+	if len(values) == 0 {
+		return nil
+	}
+
+	mid := len(values) / 2
+	n := node{
+		data:  values[mid],
+		tree:  t,
+		left:  build(t, values[:mid]),
+		right: build(t, values[mid+1:]),
+	}
+	n.level = max(n.left.height(), n.right.height()) + 1
+	n.size = n.left.count() + n.right.count() + 1
+
+	return &n
+}
+
+// =============================================================================
+
+// snapshotWriter writes the snapshot while calculating the checksum. Once
+// a write fails, every following write is skipped.
+type snapshotWriter struct {
+	w   *bufio.Writer
+	crc hash.Hash32
+	buf [binary.MaxVarintLen64]byte
+	n   int64
+	err error
+}
+
+// write writes the bytes to the underlying writer and the checksum.
+func (sw *snapshotWriter) write(p []byte) {
+	if sw.err != nil {
+		return
+	}
+
+	var n int
+	n, sw.err = sw.w.Write(p)
+	sw.n += int64(n)
+	sw.crc.Write(p[:n])
+}
+
+// writeUvarint writes an unsigned varint.
+func (sw *snapshotWriter) writeUvarint(v uint64) {
+	n := binary.PutUvarint(sw.buf[:], v)
+	sw.write(sw.buf[:n])
+}
+
+// writeVarint writes a signed varint.
+func (sw *snapshotWriter) writeVarint(v int64) {
+	n := binary.PutVarint(sw.buf[:], v)
+	sw.write(sw.buf[:n])
+}
+
+// snapshotSource is what the snapshotReader needs to read from. Readers
+// that don't provide it are wrapped with a byteReader.
+type snapshotSource interface {
+	io.Reader
+	io.ByteReader
+}
+
+// byteReader adds the io.ByteReader interface to a reader without any
+// buffering, so nothing past the snapshot is consumed.
+type byteReader struct {
+	io.Reader
+	b [1]byte
+}
+
+// ReadByte implements the io.ByteReader interface.
+func (br *byteReader) ReadByte() (byte, error) {
+	if _, err := io.ReadFull(br.Reader, br.b[:]); err != nil {
+		return 0, err
+	}
+	return br.b[0], nil
+}
+
+// snapshotReader reads the snapshot while calculating the checksum. The
+// last error returned by the underlying reader is kept to tell it apart
+// from a malformed varint.
+type snapshotReader struct {
+	r   snapshotSource
+	crc hash.Hash32
+	n   int64
+	err error
+}
+
+// read fills p from the underlying reader.
+func (sr *snapshotReader) read(p []byte) error {
+	n, err := io.ReadFull(sr.r, p)
+	sr.n += int64(n)
+	sr.crc.Write(p[:n])
+	return snapshotErr(err)
+}
+
+// ReadByte implements the io.ByteReader interface so the reader can be
+// used with the binary varint functions.
+func (sr *snapshotReader) ReadByte() (byte, error) {
+	b, err := sr.r.ReadByte()
+	if err != nil {
+		sr.err = err
+		return 0, err
+	}
+
+	sr.n++
+	sr.crc.Write([]byte{b})
+	return b, nil
+}
+
+// readUvarint reads an unsigned varint.
+func (sr *snapshotReader) readUvarint() (uint64, error) {
+	v, err := binary.ReadUvarint(sr)
+	return v, sr.varintErr(err)
+}
+
+// readVarint reads a signed varint.
+func (sr *snapshotReader) readVarint() (int64, error) {
+	v, err := binary.ReadVarint(sr)
+	return v, sr.varintErr(err)
+}
+
+// varintErr converts the error returned while reading a varint. When the
+// underlying reader did not fail, the varint itself is malformed.
+func (sr *snapshotReader) varintErr(err error) error {
+	if err != nil && sr.err == nil {
+		return fmt.Errorf("%w: %v", ErrCorrupt, err)
+	}
+	return snapshotErr(err)
+}
+
+// snapshotErr converts the errors returned while reading into the
+// errors returned by ReadFrom.
+func snapshotErr(err error) error {
+	switch {
+	case err == nil:
+		return nil
+
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return ErrTruncated
+	}
+
+	return err
+}
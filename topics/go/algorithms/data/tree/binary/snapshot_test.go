@@ -0,0 +1,162 @@
+package binary_test
+
+import (
+	"bytes"
+	stdbinary "encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/ardanlabs/gotraining/topics/go/algorithms/data/tree/binary"
+)
+
+// TestSnapshot validates the WriteTo and ReadFrom functionality.
+func TestSnapshot(t *testing.T) {
+	t.Log("Given the need to save and restore a tree.")
+	{
+		rnd := rand.New(rand.NewSource(2))
+		_, keys := generateTree(1000, rnd)
+
+		var tree binary.Tree
+		for _, i := range rnd.Perm(len(keys)) {
+			tree.Insert(binary.Data{Key: keys[i], Name: fmt.Sprintf("name%d", keys[i])})
+		}
+
+		var buf bytes.Buffer
+
+		testID := 0
+		t.Logf("\tTest %d:\tWhen writing and reading a snapshot.", testID)
+		{
+			n, err := tree.WriteTo(&buf)
+			if err != nil || n != int64(buf.Len()) {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to write the snapshot : %d, %v", failed, testID, n, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to write the snapshot.", succeed, testID)
+
+			var restored binary.Tree
+			restored.Insert(binary.Data{Key: -5})
+
+			n, err = restored.ReadFrom(bytes.NewReader(buf.Bytes()))
+			if err != nil || n != int64(buf.Len()) {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to read the snapshot : %d, %v", failed, testID, n, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to read the snapshot.", succeed, testID)
+
+			got := restored.InOrder()
+			if len(got) != len(keys) {
+				t.Errorf("\t%s\tTest %d:\tShould restore every value.", failed, testID)
+				t.Fatalf("\t\tTest %d:\tGot %d, Expected %d", testID, len(got), len(keys))
+			}
+			for i, data := range got {
+				if data.Key != keys[i] || data.Name != fmt.Sprintf("name%d", keys[i]) {
+					t.Fatalf("\t%s\tTest %d:\tShould restore every value : %v", failed, testID, data)
+				}
+			}
+			t.Logf("\t%s\tTest %d:\tShould restore every value.", succeed, testID)
+
+			for k, key := range keys {
+				if restored.Rank(key) != k {
+					t.Fatalf("\t%s\tTest %d:\tShould restore a tree with correct ranks : %d", failed, testID, key)
+				}
+			}
+			t.Logf("\t%s\tTest %d:\tShould restore a tree with correct ranks.", succeed, testID)
+
+			restored.Insert(binary.Data{Key: -1})
+			if err := restored.Delete(keys[0]); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to modify the restored tree : %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to modify the restored tree.", succeed, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen writing and reading an empty tree.", testID)
+		{
+			var empty binary.Tree
+			var b bytes.Buffer
+			if _, err := empty.WriteTo(&b); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to write the snapshot : %v", failed, testID, err)
+			}
+
+			var restored binary.Tree
+			restored.Insert(binary.Data{Key: 1})
+			if _, err := restored.ReadFrom(&b); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to read the snapshot : %v", failed, testID, err)
+			}
+			if restored.Len() != 0 {
+				t.Fatalf("\t%s\tTest %d:\tShould restore an empty tree : %d", failed, testID, restored.Len())
+			}
+			t.Logf("\t%s\tTest %d:\tShould restore an empty tree.", succeed, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen reading a truncated snapshot.", testID)
+		{
+			data := buf.Bytes()
+
+			// Cut inside and at the edges of the header, around the first
+			// entries and the checksum, and before the last byte. Each
+			// entry is the varint key, the varint length and the name.
+			header := len("BTRE") + 1 + stdbinary.PutUvarint(make([]byte, stdbinary.MaxVarintLen64), uint64(len(keys)))
+			cuts := []int{0, 1, 4, 5, 6, header}
+			offset := header
+			for _, key := range keys[:3] {
+				name := fmt.Sprintf("name%d", key)
+				keyLen := stdbinary.PutVarint(make([]byte, stdbinary.MaxVarintLen64), int64(key))
+				cuts = append(cuts, offset+1, offset+keyLen, offset+keyLen+1)
+				offset += keyLen + stdbinary.PutUvarint(make([]byte, stdbinary.MaxVarintLen64), uint64(len(name))) + len(name)
+				cuts = append(cuts, offset)
+			}
+			cuts = append(cuts, len(data)/2, len(data)-4, len(data)-3, len(data)-1)
+
+			for _, size := range cuts {
+				var restored binary.Tree
+				_, err := restored.ReadFrom(bytes.NewReader(data[:size]))
+				if !errors.Is(err, binary.ErrTruncated) {
+					t.Fatalf("\t%s\tTest %d:\tShould detect a snapshot truncated to %d bytes : %v", failed, testID, size, err)
+				}
+				if restored.Len() != 0 {
+					t.Fatalf("\t%s\tTest %d:\tShould leave the tree unchanged.", failed, testID)
+				}
+			}
+			t.Logf("\t%s\tTest %d:\tShould detect a truncated snapshot.", succeed, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen reading a snapshot followed by other data.", testID)
+		{
+			// A MultiReader is not an io.ByteReader.
+			r := io.MultiReader(bytes.NewReader(buf.Bytes()), strings.NewReader("next"))
+
+			var restored binary.Tree
+			if _, err := restored.ReadFrom(r); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to read the snapshot : %v", failed, testID, err)
+			}
+
+			rest, err := io.ReadAll(r)
+			if err != nil || string(rest) != "next" {
+				t.Fatalf("\t%s\tTest %d:\tShould not read past the snapshot : %q %v", failed, testID, rest, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould not read past the snapshot.", succeed, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen reading a corrupt snapshot.", testID)
+		{
+			data := buf.Bytes()
+			for i := 0; i < len(data); i += 7 {
+				corrupt := append([]byte(nil), data...)
+				corrupt[i] ^= 0x40
+
+				var restored binary.Tree
+				_, err := restored.ReadFrom(bytes.NewReader(corrupt))
+				if !errors.Is(err, binary.ErrCorrupt) && !errors.Is(err, binary.ErrTruncated) {
+					t.Fatalf("\t%s\tTest %d:\tShould detect a corrupt byte at %d : %v", failed, testID, i, err)
+				}
+			}
+			t.Logf("\t%s\tTest %d:\tShould detect a corrupt snapshot.", succeed, testID)
+		}
+	}
+}
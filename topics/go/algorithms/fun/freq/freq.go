@@ -1,5 +1,7 @@
 // Package freq provides support for find the frequency in which a rune
-// is found in a collection of text documents.
+// is found in a collection of text documents. The same strategies can be
+// applied to a stream of any size to count runes, words or n-grams, or
+// to estimate the most frequent ones using a fixed amount of memory.
 package freq
 
 import (
@@ -0,0 +1,411 @@
+package freq
+
+import (
+	"io"
+	"runtime"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	heapsort "github.com/ardanlabs/gotraining/topics/go/algorithms/sorting/heap"
+)
+
+// DefaultChunkSize is the number of bytes read from a stream for every
+// chunk of work.
+const DefaultChunkSize = 64 << 10
+
+// Splitter describes how the text in a stream is broken into the tokens
+// to count. Streams are read in chunks that are counted independently, so
+// a chunk must never end in the middle of a token.
+type Splitter[K comparable] struct {
+
+	// ChunkSize is the number of bytes read for every chunk. It is
+	// DefaultChunkSize when it is zero. A chunk grows past this size
+	// when a single token is larger.
+	ChunkSize int
+
+	// cut returns the length of the prefix of buf that can be counted
+	// without knowing the bytes that follow.
+	cut func(buf []byte, atEOF bool) int
+
+	// count adds the tokens found in the chunk to the map.
+	count func(chunk []byte, m map[K]int)
+}
+
+// Runes returns a Splitter that counts every rune. Invalid UTF-8 is
+// counted as utf8.RuneError.
+func Runes() Splitter[rune] {
+	return Splitter[rune]{
+		cut: cutRune,
+		count: func(chunk []byte, m map[rune]int) {
+			for len(chunk) > 0 {
+				r, size := utf8.DecodeRune(chunk)
+				m[r]++
+				chunk = chunk[size:]
+			}
+		},
+	}
+}
+
+// Words returns a Splitter that counts the words separated by white space,
+// as defined by unicode.IsSpace.
+func Words() Splitter[string] {
+	return Splitter[string]{
+		cut: cutSpace,
+		count: func(chunk []byte, m map[string]int) {
+			fields(chunk, func(word []byte) {
+				m[string(word)]++
+			})
+		},
+	}
+}
+
+// NGrams returns a Splitter that counts the sequences of n runes found
+// within every word. A word shorter than n runes has no n-grams.
+func NGrams(n int) Splitter[string] {
+	if n < 1 {
+		n = 1
+	}
+
+	return Splitter[string]{
+		cut: cutSpace,
+		count: func(chunk []byte, m map[string]int) {
+			fields(chunk, func(word []byte) {
+
+				// Keep the byte offset of the start of the last n runes
+				// so every n-gram can be sliced out of the word.
+				starts := make([]int, 0, n+1)
+				for offset := range string(word) {
+					starts = append(starts, offset)
+					if len(starts) > n {
+						m[string(word[starts[0]:offset])]++
+						starts = starts[1:]
+					}
+				}
+				if len(starts) == n {
+					m[string(word[starts[0]:])]++
+				}
+			})
+		},
+	}
+}
+
+// cutRune cuts the buffer after the last complete rune.
+func cutRune(buf []byte, atEOF bool) int {
+	if atEOF {
+		return len(buf)
+	}
+
+	// Look for the start of the last rune in the final few bytes.
+	for i := len(buf) - 1; i >= 0 && i >= len(buf)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(buf[i]) {
+			if utf8.FullRune(buf[i:]) {
+				return len(buf)
+			}
+			return i
+		}
+	}
+
+	return len(buf)
+}
+
+// cutSpace cuts the buffer after the last white space.
+func cutSpace(buf []byte, atEOF bool) int {
+	if atEOF {
+		return len(buf)
+	}
+
+	// Only complete runes can be checked for white space.
+	end := cutRune(buf, false)
+	for end > 0 {
+		r, size := utf8.DecodeLastRune(buf[:end])
+		if unicode.IsSpace(r) {
+			return end
+		}
+		end -= size
+	}
+
+	return 0
+}
+
+// fields calls fn with every word in the chunk separated by white space.
+func fields(chunk []byte, fn func(word []byte)) {
+	start := -1
+	for offset, r := range string(chunk) {
+		switch {
+		case unicode.IsSpace(r):
+			if start >= 0 {
+				fn(chunk[start:offset])
+				start = -1
+			}
+		case start < 0:
+			start = offset
+		}
+	}
+
+	if start >= 0 {
+		fn(chunk[start:])
+	}
+}
+
+// =============================================================================
+
+// chunker reads a stream and returns chunks that end on a token boundary.
+type chunker[K comparable] struct {
+	r    io.Reader
+	sp   Splitter[K]
+	size int
+	buf  []byte
+	eof  bool
+}
+
+// newChunker returns a chunker for the stream.
+func newChunker[K comparable](r io.Reader, sp Splitter[K]) *chunker[K] {
+	size := sp.ChunkSize
+	if size <= 0 {
+		size = DefaultChunkSize
+	}
+
+	return &chunker[K]{
+		r:    r,
+		sp:   sp,
+		size: size,
+	}
+}
+
+// next returns the next chunk. The chunk is not used by the chunker again,
+// so it can be handed to another goroutine. It returns io.EOF once the
+// stream is done.
+func (c *chunker[K]) next() ([]byte, error) {
+	for {
+		if c.eof && len(c.buf) == 0 {
+			return nil, io.EOF
+		}
+
+		// Fill the buffer up to the chunk size, or past it when the
+		// buffer is already full and holds no token boundary.
+		if !c.eof {
+			want := c.size
+			if len(c.buf) >= want {
+				want = 2 * len(c.buf)
+			}
+			buf := make([]byte, want)
+			n := copy(buf, c.buf)
+
+			for n < want && !c.eof {
+				m, err := c.r.Read(buf[n:])
+				n += m
+				switch {
+				case err == io.EOF:
+					c.eof = true
+				case err != nil:
+					return nil, err
+				}
+			}
+			c.buf = buf[:n]
+		}
+
+		// Hand out everything up to the last token boundary and keep
+		// the rest for the next chunk.
+		end := c.sp.cut(c.buf, c.eof)
+		if end == 0 && !c.eof {
+			continue
+		}
+
+		chunk := c.buf[:end:end]
+		c.buf = c.buf[end:]
+		return chunk, nil
+	}
+}
+
+// merge adds the counts from the local map to the shared map.
+func merge[K comparable](mu *sync.Mutex, m, lm map[K]int) {
+	mu.Lock()
+	defer mu.Unlock()
+	for k, v := range lm {
+		m[k] += v
+	}
+}
+
+// SequentialStream uses a sequential algorithm to count the tokens in
+// the stream.
+func SequentialStream[K comparable](r io.Reader, sp Splitter[K]) (map[K]int, error) {
+	m := make(map[K]int)
+	c := newChunker(r, sp)
+
+	for {
+		chunk, err := c.next()
+		switch {
+		case err == io.EOF:
+			return m, nil
+		case err != nil:
+			return nil, err
+		}
+		sp.count(chunk, m)
+	}
+}
+
+// ConcurrentUnlimitedStream uses a concurrent algorithm based on an
+// unlimited fan out pattern, with a goroutine for every chunk.
+func ConcurrentUnlimitedStream[K comparable](r io.Reader, sp Splitter[K]) (map[K]int, error) {
+	m := make(map[K]int)
+	c := newChunker(r, sp)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for {
+		chunk, err := c.next()
+		if err != nil {
+			wg.Wait()
+			if err == io.EOF {
+				return m, nil
+			}
+			return nil, err
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			lm := make(map[K]int)
+			sp.count(chunk, lm)
+			merge(&mu, m, lm)
+		}()
+	}
+}
+
+// ConcurrentBoundedStream uses a concurrent algorithm based on a bounded
+// fan out and no channels. Every goroutine takes turns reading the next
+// chunk from the stream.
+func ConcurrentBoundedStream[K comparable](r io.Reader, sp Splitter[K]) (map[K]int, error) {
+	m := make(map[K]int)
+	c := newChunker(r, sp)
+
+	goroutines := runtime.GOMAXPROCS(0)
+
+	var mu sync.Mutex
+	var readMu sync.Mutex
+	var readErr error
+	var wg sync.WaitGroup
+	wg.Add(goroutines)
+
+	for g := 0; g < goroutines; g++ {
+		go func() {
+			lm := make(map[K]int)
+			defer func() {
+				merge(&mu, m, lm)
+				wg.Done()
+			}()
+
+			for {
+				readMu.Lock()
+				if readErr != nil {
+					readMu.Unlock()
+					return
+				}
+				chunk, err := c.next()
+				if err != nil {
+					readErr = err
+					readMu.Unlock()
+					return
+				}
+				readMu.Unlock()
+
+				sp.count(chunk, lm)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if readErr != io.EOF {
+		return nil, readErr
+	}
+	return m, nil
+}
+
+// ConcurrentBoundedChannelStream uses a concurrent algorithm based on a
+// bounded fan out using a channel to pass the chunks.
+func ConcurrentBoundedChannelStream[K comparable](r io.Reader, sp Splitter[K]) (map[K]int, error) {
+	m := make(map[K]int)
+	c := newChunker(r, sp)
+
+	g := runtime.GOMAXPROCS(0)
+	var wg sync.WaitGroup
+	wg.Add(g)
+
+	var mu sync.Mutex
+	ch := make(chan []byte, g)
+
+	for i := 0; i < g; i++ {
+		go func() {
+			lm := make(map[K]int)
+			defer func() {
+				merge(&mu, m, lm)
+				wg.Done()
+			}()
+
+			for chunk := range ch {
+				sp.count(chunk, lm)
+			}
+		}()
+	}
+
+	var err error
+	for {
+		var chunk []byte
+		if chunk, err = c.next(); err != nil {
+			break
+		}
+		ch <- chunk
+	}
+	close(ch)
+
+	wg.Wait()
+
+	if err != io.EOF {
+		return nil, err
+	}
+	return m, nil
+}
+
+// =============================================================================
+
+// Count is a token and the number of times it was found.
+type Count[K comparable] struct {
+	Key   K
+	Count int
+}
+
+// TopK returns the k tokens with the highest counts, highest first. Tokens
+// with the same count are returned in no particular order. A min heap of
+// size k is used, so only k counts are kept in order at any time.
+func TopK[K comparable](m map[K]int, k int) []Count[K] {
+	if k <= 0 {
+		return nil
+	}
+
+	// The smallest count kept is at the top of the heap, so it can be
+	// replaced when a larger count is found.
+	pq := heapsort.NewPriorityQueue(func(a, b Count[K]) bool {
+		return a.Count < b.Count
+	})
+
+	for key, count := range m {
+		switch {
+		case pq.Len() < k:
+			pq.Push(Count[K]{Key: key, Count: count})
+		default:
+			if min, _ := pq.Peek(); count > min.Count {
+				pq.Update(0, Count[K]{Key: key, Count: count})
+			}
+		}
+	}
+
+	top := make([]Count[K], pq.Len())
+	for i := len(top) - 1; i >= 0; i-- {
+		top[i], _ = pq.Pop()
+	}
+
+	return top
+}
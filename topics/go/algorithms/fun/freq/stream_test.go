@@ -0,0 +1,193 @@
+package freq_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/ardanlabs/gotraining/topics/go/algorithms/fun/freq"
+)
+
+// streams are the strategies for counting the tokens in a stream.
+func streams[K comparable]() []struct {
+	name  string
+	count func(r io.Reader, sp freq.Splitter[K]) (map[K]int, error)
+} {
+	return []struct {
+		name  string
+		count func(r io.Reader, sp freq.Splitter[K]) (map[K]int, error)
+	}{
+		{"SequentialStream", freq.SequentialStream[K]},
+		{"ConcurrentBoundedStream", freq.ConcurrentBoundedStream[K]},
+		{"ConcurrentBoundedChannelStream", freq.ConcurrentBoundedChannelStream[K]},
+		{"ConcurrentUnlimitedStream", freq.ConcurrentUnlimitedStream[K]},
+	}
+}
+
+// chunkSizes are small enough for chunks to end inside runes and words.
+var chunkSizes = []int{1, 2, 3, 7, 64, 0}
+
+// countWords counts the words and n-grams of the text without streaming,
+// to check the streaming results against.
+func countWords(text string, n int) map[string]int {
+	m := make(map[string]int)
+	for _, word := range strings.Fields(text) {
+		if n == 0 {
+			m[word]++
+			continue
+		}
+		runes := []rune(word)
+		for i := 0; i+n <= len(runes); i++ {
+			m[string(runes[i:i+n])]++
+		}
+	}
+	return m
+}
+
+func TestStreamRunes(t *testing.T) {
+	text := strings.Join(inp, "")
+
+	t.Log("Given the need to count the runes in a stream.")
+	{
+		for i, tt := range streams[rune]() {
+			t.Logf("\tTest %d:\tWhen running %q", i, tt.name)
+			{
+				for _, size := range chunkSizes {
+					sp := freq.Runes()
+					sp.ChunkSize = size
+
+					f, err := tt.count(iotest.HalfReader(strings.NewReader(text)), sp)
+					if err != nil {
+						t.Fatalf("\t%s\tShould be able to count with chunk size %d : %v", failed, size, err)
+					}
+
+					if !reflect.DeepEqual(f, out) {
+						t.Fatalf("\t%s\tShould count every rune with chunk size %d.", failed, size)
+					}
+				}
+				t.Logf("\t%s\tShould count every rune for every chunk size.", succeed)
+			}
+		}
+	}
+}
+
+func TestStreamWords(t *testing.T) {
+	text := strings.Repeat(sentence+"\n", 100) + "Über naïve café ünïcödé"
+
+	t.Log("Given the need to count the words and n-grams in a stream.")
+	{
+		for i, tt := range streams[string]() {
+			t.Logf("\tTest %d:\tWhen running %q", i, tt.name)
+			{
+				for _, n := range []int{0, 1, 2, 3} {
+					exp := countWords(text, n)
+
+					for _, size := range chunkSizes {
+						sp := freq.Words()
+						if n > 0 {
+							sp = freq.NGrams(n)
+						}
+						sp.ChunkSize = size
+
+						f, err := tt.count(strings.NewReader(text), sp)
+						if err != nil {
+							t.Fatalf("\t%s\tShould be able to count with chunk size %d : %v", failed, size, err)
+						}
+
+						if !reflect.DeepEqual(f, exp) {
+							t.Fatalf("\t%s\tShould count every %d-gram with chunk size %d.", failed, n, size)
+						}
+					}
+				}
+				t.Logf("\t%s\tShould count every word and n-gram for every chunk size.", succeed)
+			}
+		}
+	}
+}
+
+func TestStreamError(t *testing.T) {
+	errRead := errors.New("read failed")
+
+	t.Log("Given the need to report errors reading a stream.")
+	{
+		for i, tt := range streams[rune]() {
+			t.Logf("\tTest %d:\tWhen running %q", i, tt.name)
+			{
+				sp := freq.Runes()
+				sp.ChunkSize = 4
+
+				r := io.MultiReader(strings.NewReader(sentence), iotest.ErrReader(errRead))
+				if _, err := tt.count(r, sp); !errors.Is(err, errRead) {
+					t.Fatalf("\t%s\tShould get the read error : %v", failed, err)
+				}
+				t.Logf("\t%s\tShould get the read error.", succeed)
+			}
+		}
+	}
+}
+
+func TestTopK(t *testing.T) {
+	t.Log("Given the need to find the most frequent runes.")
+	{
+		tests := []struct {
+			k   int
+			exp []int
+		}{
+			{0, nil},
+			{3, []int{3700, 2000, 1100}},
+			{100, nil},
+		}
+
+		for i, tt := range tests {
+			t.Logf("\tTest %d:\tWhen asking for the top %d.", i, tt.k)
+			{
+				top := freq.TopK(out, tt.k)
+
+				exp := tt.exp
+				if tt.k > len(out) {
+					exp = nil
+					for _, c := range out {
+						exp = append(exp, c)
+					}
+				}
+
+				var got []int
+				for _, c := range top {
+					if out[c.Key] != c.Count {
+						t.Fatalf("\t%s\tShould get the count of %q : %d, Expected %d", failed, c.Key, c.Count, out[c.Key])
+					}
+					got = append(got, c.Count)
+				}
+
+				if len(got) != len(exp) {
+					t.Fatalf("\t%s\tShould get %d counts : %d", failed, len(exp), len(got))
+				}
+				for j := range got {
+					if j > 0 && got[j] > got[j-1] {
+						t.Fatalf("\t%s\tShould get the counts highest first : %v", failed, got)
+					}
+					if tt.exp != nil && got[j] != exp[j] {
+						t.Fatalf("\t%s\tShould get the top counts : %v, Expected %v", failed, got, exp)
+					}
+				}
+				t.Logf("\t%s\tShould get the top %d counts highest first.", succeed, len(exp))
+			}
+		}
+	}
+}
+
+func BenchmarkStream(b *testing.B) {
+	text := strings.Repeat(sentence+"\n", 10000)
+
+	for _, tt := range streams[string]() {
+		b.Run(fmt.Sprintf("words/%s", tt.name), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				tt.count(strings.NewReader(text), freq.Words())
+			}
+		})
+	}
+}
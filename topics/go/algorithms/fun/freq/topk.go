@@ -0,0 +1,228 @@
+package freq
+
+import (
+	"io"
+	"runtime"
+	"sync"
+
+	heapsort "github.com/ardanlabs/gotraining/topics/go/algorithms/sorting/heap"
+)
+
+// counterFactor is the number of counters kept for every token asked for
+// by the top-k stream functions. More counters make the counts closer to
+// the true counts.
+const counterFactor = 10
+
+// Estimate is a token and an estimate of the number of times it was found.
+// The true count is between Count-Error and Count.
+type Estimate[K comparable] struct {
+	Key   K
+	Count int
+	Error int
+}
+
+// counter is the estimate of a token kept in the heap, along with its
+// index position in the heap so it can be updated.
+type counter[K comparable] struct {
+	Estimate[K]
+	index int
+}
+
+// SpaceSaving finds the most frequent tokens in a stream using a fixed
+// number of counters, no matter how many different tokens there are. When
+// a token without a counter is found and every counter is taken, the
+// counter with the smallest count is given to the new token, which
+// inherits the count as its error. Any token found more than N/counters
+// times, where N is the total of the counts added, is sure to have a
+// counter.
+//
+// The algorithm is described in "Efficient Computation of Frequent and
+// Top-k Elements in Data Streams" by Metwally, Agrawal and El Abbadi.
+type SpaceSaving[K comparable] struct {
+	size     int
+	counters map[K]*counter[K]
+
+	// The smallest count is at the top of the heap, so the counter to
+	// give away is always known.
+	pq *heapsort.PriorityQueue[*counter[K]]
+}
+
+// NewSpaceSaving returns a SpaceSaving that keeps the specified number of
+// counters. At least one counter is kept.
+func NewSpaceSaving[K comparable](size int) *SpaceSaving[K] {
+	if size < 1 {
+		size = 1
+	}
+
+	ss := SpaceSaving[K]{
+		size:     size,
+		counters: make(map[K]*counter[K], size),
+		pq: heapsort.NewPriorityQueue(func(a, b *counter[K]) bool {
+			return a.Count < b.Count
+		}),
+	}
+	ss.pq.OnMove(func(c *counter[K], index int) {
+		c.index = index
+	})
+
+	return &ss
+}
+
+// Add adds n to the count of the token.
+func (ss *SpaceSaving[K]) Add(key K, n int) {
+	ss.add(key, n, 0)
+}
+
+// add adds n to the count of the token, which may already be off by the
+// specified error.
+func (ss *SpaceSaving[K]) add(key K, n int, err int) {
+	if c, ok := ss.counters[key]; ok {
+		c.Count += n
+		c.Error += err
+		ss.pq.Update(c.index, c)
+		return
+	}
+
+	if ss.pq.Len() < ss.size {
+		c := counter[K]{Estimate: Estimate[K]{Key: key, Count: n, Error: err}}
+		ss.counters[key] = &c
+		ss.pq.Push(&c)
+		return
+	}
+
+	// Give the counter with the smallest count to the token. The token
+	// may have been counted by it already, so that count is the error.
+	c, _ := ss.pq.Peek()
+	delete(ss.counters, c.Key)
+
+	c.Key = key
+	c.Error = c.Count + err
+	c.Count += n
+	ss.counters[key] = c
+	ss.pq.Update(c.index, c)
+}
+
+// Merge adds the counts of the other SpaceSaving, so the tokens of a
+// stream can be counted by more than one goroutine.
+func (ss *SpaceSaving[K]) Merge(other *SpaceSaving[K]) {
+
+	// Once every counter of the other SpaceSaving is taken, a token
+	// without one may have been counted by a counter that was given
+	// away, up to its smallest count.
+	if other.pq.Len() == other.size {
+		min, _ := other.pq.Peek()
+		for key, c := range ss.counters {
+			if _, ok := other.counters[key]; !ok {
+				c.Count += min.Count
+				c.Error += min.Count
+				ss.pq.Update(c.index, c)
+			}
+		}
+	}
+
+	for _, c := range other.counters {
+		ss.add(c.Key, c.Count, c.Error)
+	}
+}
+
+// Top returns the estimates of the k tokens with the highest counts,
+// highest first.
+func (ss *SpaceSaving[K]) Top(k int) []Estimate[K] {
+	if k <= 0 {
+		return nil
+	}
+
+	top := make([]Estimate[K], 0, len(ss.counters))
+	for _, c := range ss.counters {
+		top = append(top, c.Estimate)
+	}
+
+	heapsort.SortFunc(top, func(a, b Estimate[K]) bool {
+		return a.Count > b.Count
+	})
+
+	if len(top) > k {
+		top = top[:k]
+	}
+	return top
+}
+
+// =============================================================================
+
+// SequentialTopKStream uses a sequential algorithm to estimate the k most
+// frequent tokens in the stream. Unlike SequentialStream, the memory used
+// doesn't grow with the number of different tokens.
+func SequentialTopKStream[K comparable](r io.Reader, sp Splitter[K], k int) ([]Estimate[K], error) {
+	ss := NewSpaceSaving[K](k * counterFactor)
+	c := newChunker(r, sp)
+
+	for {
+		chunk, err := c.next()
+		switch {
+		case err == io.EOF:
+			return ss.Top(k), nil
+		case err != nil:
+			return nil, err
+		}
+		countChunk(sp, chunk, ss)
+	}
+}
+
+// ConcurrentBoundedTopKStream uses a concurrent algorithm based on a
+// bounded fan out using a channel to pass the chunks to estimate the k
+// most frequent tokens in the stream. Every goroutine keeps its own
+// counters, which are merged at the end.
+func ConcurrentBoundedTopKStream[K comparable](r io.Reader, sp Splitter[K], k int) ([]Estimate[K], error) {
+	ss := NewSpaceSaving[K](k * counterFactor)
+	c := newChunker(r, sp)
+
+	g := runtime.GOMAXPROCS(0)
+	var wg sync.WaitGroup
+	wg.Add(g)
+
+	var mu sync.Mutex
+	ch := make(chan []byte, g)
+
+	for i := 0; i < g; i++ {
+		go func() {
+			lss := NewSpaceSaving[K](k * counterFactor)
+			defer func() {
+				mu.Lock()
+				ss.Merge(lss)
+				mu.Unlock()
+				wg.Done()
+			}()
+
+			for chunk := range ch {
+				countChunk(sp, chunk, lss)
+			}
+		}()
+	}
+
+	var err error
+	for {
+		var chunk []byte
+		if chunk, err = c.next(); err != nil {
+			break
+		}
+		ch <- chunk
+	}
+	close(ch)
+
+	wg.Wait()
+
+	if err != io.EOF {
+		return nil, err
+	}
+	return ss.Top(k), nil
+}
+
+// countChunk counts the tokens in the chunk and adds the counts to the
+// SpaceSaving. The map only holds the tokens of a single chunk.
+func countChunk[K comparable](sp Splitter[K], chunk []byte, ss *SpaceSaving[K]) {
+	m := make(map[K]int)
+	sp.count(chunk, m)
+	for key, n := range m {
+		ss.Add(key, n)
+	}
+}
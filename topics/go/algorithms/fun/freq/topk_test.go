@@ -0,0 +1,140 @@
+package freq_test
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/ardanlabs/gotraining/topics/go/algorithms/fun/freq"
+)
+
+// topKStreams are the strategies for estimating the top tokens in a stream.
+var topKStreams = []struct {
+	name string
+	topK func(r io.Reader, sp freq.Splitter[string], k int) ([]freq.Estimate[string], error)
+}{
+	{"SequentialTopKStream", freq.SequentialTopKStream[string]},
+	{"ConcurrentBoundedTopKStream", freq.ConcurrentBoundedTopKStream[string]},
+}
+
+// heavyText returns a text where a few words are found many times among
+// thousands of words that are found once.
+func heavyText() string {
+	var words []string
+	for word, n := range map[string]int{"go": 1000, "gopher": 500, "chan": 300} {
+		for i := 0; i < n; i++ {
+			words = append(words, word)
+		}
+	}
+	for i := 0; i < 3000; i++ {
+		words = append(words, fmt.Sprintf("w%d", i))
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	rnd.Shuffle(len(words), func(i, j int) {
+		words[i], words[j] = words[j], words[i]
+	})
+
+	return strings.Join(words, " ")
+}
+
+func TestTopKStream(t *testing.T) {
+	text := heavyText()
+	exact := countWords(text, 0)
+
+	t.Log("Given the need to find the most frequent words with bounded memory.")
+	{
+		for i, tt := range topKStreams {
+			t.Logf("\tTest %d:\tWhen running %q", i, tt.name)
+			{
+				for _, size := range []int{64, 1024, 0} {
+					sp := freq.Words()
+					sp.ChunkSize = size
+
+					top, err := tt.topK(strings.NewReader(text), sp, 3)
+					if err != nil {
+						t.Fatalf("\t%s\tShould be able to count with chunk size %d : %v", failed, size, err)
+					}
+
+					var keys []string
+					for _, e := range top {
+						if n := exact[e.Key]; n > e.Count || n < e.Count-e.Error {
+							t.Fatalf("\t%s\tShould estimate the count of %q with chunk size %d : %d, Expected %d-%d", failed, e.Key, size, n, e.Count-e.Error, e.Count)
+						}
+						keys = append(keys, e.Key)
+					}
+
+					if got := strings.Join(keys, " "); got != "go gopher chan" {
+						t.Fatalf("\t%s\tShould find the most frequent words with chunk size %d : %q", failed, size, got)
+					}
+				}
+				t.Logf("\t%s\tShould find the most frequent words for every chunk size.", succeed)
+			}
+		}
+	}
+}
+
+func TestSpaceSaving(t *testing.T) {
+	t.Log("Given the need to estimate counts with a fixed number of counters.")
+	{
+		t.Logf("\tTest 0:\tWhen there are more tokens than counters.")
+		{
+			exact := make(map[int]int)
+			ss := freq.NewSpaceSaving[int](20)
+			merged := freq.NewSpaceSaving[int](20)
+			part := freq.NewSpaceSaving[int](20)
+
+			// Small numbers are much more likely than large ones.
+			rnd := rand.New(rand.NewSource(1))
+			for i := 0; i < 10000; i++ {
+				key := int(rnd.ExpFloat64() * 10)
+				exact[key]++
+				ss.Add(key, 1)
+
+				if i%2 == 0 {
+					merged.Add(key, 1)
+				} else {
+					part.Add(key, 1)
+				}
+			}
+			merged.Merge(part)
+
+			for _, s := range []*freq.SpaceSaving[int]{ss, merged} {
+				top := s.Top(100)
+				if len(top) != 20 {
+					t.Fatalf("\t%s\tTest 0:\tShould keep 20 counters : %d", failed, len(top))
+				}
+
+				for j, e := range top {
+					if j > 0 && e.Count > top[j-1].Count {
+						t.Fatalf("\t%s\tTest 0:\tShould get the counts highest first : %v", failed, top)
+					}
+					if n := exact[e.Key]; n > e.Count || n < e.Count-e.Error {
+						t.Fatalf("\t%s\tTest 0:\tShould estimate the count of %d : %d, Expected %d-%d", failed, e.Key, n, e.Count-e.Error, e.Count)
+					}
+				}
+				if top[0].Key != 0 {
+					t.Fatalf("\t%s\tTest 0:\tShould find the most frequent token : %v", failed, top[0])
+				}
+			}
+			t.Logf("\t%s\tTest 0:\tShould keep estimates that hold the true counts.", succeed)
+		}
+
+		t.Logf("\tTest 1:\tWhen there are fewer tokens than counters.")
+		{
+			ss := freq.NewSpaceSaving[string](10)
+			ss.Add("a", 3)
+			ss.Add("b", 5)
+			ss.Add("a", 4)
+
+			top := ss.Top(2)
+			exp := []freq.Estimate[string]{{Key: "a", Count: 7}, {Key: "b", Count: 5}}
+			if len(top) != 2 || top[0] != exp[0] || top[1] != exp[1] {
+				t.Fatalf("\t%s\tTest 1:\tShould get the exact counts : %v", failed, top)
+			}
+			t.Logf("\t%s\tTest 1:\tShould get the exact counts.", succeed)
+		}
+	}
+}
@@ -28,6 +28,19 @@ length quantities that they translate to:
 08000000          C0 80 80 00
 0FFFFFFF          FF FF FF 7F
 
+The 4 byte limit comes from MIDI. This package encodes any 64 bit value,
+which takes up to 10 bytes, using the same order with the most significant
+group first. Signed values use zig-zag encoding, which maps 0, -1, 1, -2, 2
+to 0, 1, 2, 3, 4 so small negative numbers stay short.
+
+Decoding reports malformed input. A value that starts with an 80 byte has
+a leading zero group and is overlong, and input that ends on a byte with
+bit #7 set is truncated. Bytes after the last byte of a value are ignored,
+so a value can be decoded from the front of a larger buffer.
+
+Note that encoding/binary.PutUvarint uses the same 7 bit groups in the
+opposite order, least significant group first.
+
 Resources:
 
 https://en.wikipedia.org/wiki/Variable-length_quantity
@@ -0,0 +1,178 @@
+package vlq
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+)
+
+// event is a single event in a MIDI track.
+type event struct {
+	time   uint64 // Ticks since the start of the track.
+	status byte
+	data   []byte
+}
+
+// parseTrack reads a MIDI track chunk. Every event starts with a delta time
+// encoded as a VLQ, which is why the track makes a good test of the Reader.
+// Running status, where the status byte is left out when it matches the
+// previous event, is supported.
+func parseTrack(r *bytes.Reader) ([]event, error) {
+	var header [8]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+	if string(header[:4]) != "MTrk" {
+		return nil, errors.New("not a track chunk")
+	}
+
+	length := binary.BigEndian.Uint32(header[4:])
+	chunk := make([]byte, length)
+	if _, err := io.ReadFull(r, chunk); err != nil {
+		return nil, err
+	}
+
+	cr := bytes.NewReader(chunk)
+	vr := NewReader(cr)
+
+	var events []event
+	var time uint64
+	var status byte
+
+	for cr.Len() > 0 {
+		delta, err := vr.ReadUint()
+		if err != nil {
+			return nil, err
+		}
+		time += delta
+
+		b, err := cr.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+
+		// A data byte means the previous status is used again.
+		switch {
+		case b&0x80 != 0:
+			status = b
+		case status == 0:
+			return nil, errors.New("running status without a status")
+		default:
+			cr.UnreadByte()
+		}
+
+		var size uint64
+		switch {
+
+		// Meta events have a type followed by the length of the data.
+		case status == 0xFF:
+			kind, err := cr.ReadByte()
+			if err != nil {
+				return nil, err
+			}
+			if size, err = vr.ReadUint(); err != nil {
+				return nil, err
+			}
+			data := make([]byte, size+1)
+			data[0] = kind
+			if _, err := io.ReadFull(cr, data[1:]); err != nil {
+				return nil, err
+			}
+			events = append(events, event{time, status, data})
+			continue
+
+		// System exclusive events are followed by the length of the data.
+		case status == 0xF0 || status == 0xF7:
+			if size, err = vr.ReadUint(); err != nil {
+				return nil, err
+			}
+
+		// Program change and channel pressure have one data byte.
+		case status&0xF0 == 0xC0 || status&0xF0 == 0xD0:
+			size = 1
+
+		default:
+			size = 2
+		}
+
+		data := make([]byte, size)
+		if _, err := io.ReadFull(cr, data); err != nil {
+			return nil, err
+		}
+		events = append(events, event{time, status, data})
+	}
+
+	return events, nil
+}
+
+func TestMIDITrack(t *testing.T) {
+	type rawEvent struct {
+		delta uint64
+		raw   []byte
+	}
+
+	// A track that plays two notes using running status for the second
+	// note, with a tempo change and an end of track meta event.
+	track := []rawEvent{
+		{0, []byte{0xFF, 0x51, 0x03, 0x07, 0xA1, 0x20}},
+		{0, []byte{0xC0, 0x05}},
+		{96, []byte{0x90, 0x3C, 0x64}},
+		{200, []byte{0x40, 0x64}},
+		{16384, []byte{0x80, 0x3C, 0x00}},
+		{0, []byte{0xF0, 0x03, 0x43, 0x12, 0xF7}},
+		{1 << 27, []byte{0xFF, 0x2F, 0x00}},
+	}
+
+	var body bytes.Buffer
+	w := NewWriter(&body)
+	for _, e := range track {
+		w.WriteUint(e.delta)
+		w.Write(e.raw)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("got %v\n", err)
+	}
+
+	var file bytes.Buffer
+	file.WriteString("MTrk")
+	binary.Write(&file, binary.BigEndian, uint32(body.Len()))
+	file.Write(body.Bytes())
+
+	events, err := parseTrack(bytes.NewReader(file.Bytes()))
+	if err != nil {
+		t.Fatalf("got %v\n", err)
+	}
+
+	exp := []event{
+		{0, 0xFF, []byte{0x51, 0x07, 0xA1, 0x20}},
+		{0, 0xC0, []byte{0x05}},
+		{96, 0x90, []byte{0x3C, 0x64}},
+		{296, 0x90, []byte{0x40, 0x64}},
+		{16680, 0x80, []byte{0x3C, 0x00}},
+		{16680, 0xF0, []byte{0x43, 0x12, 0xF7}},
+		{16680 + 1<<27, 0xFF, []byte{0x2F}},
+	}
+
+	if len(events) != len(exp) {
+		t.Fatalf("expected %d events\ngot\n%d\n", len(exp), len(events))
+	}
+	for i := range exp {
+		got := events[i]
+		if got.time != exp[i].time || got.status != exp[i].status || !bytes.Equal(got.data, exp[i].data) {
+			t.Fatalf("event %d - expected %+v\ngot\n%+v\n", i, exp[i], got)
+		}
+	}
+
+	// Cutting the track short must be reported.
+	body.Truncate(body.Len() - 4)
+	file.Reset()
+	file.WriteString("MTrk")
+	binary.Write(&file, binary.BigEndian, uint32(body.Len()))
+	file.Write(body.Bytes())
+
+	if _, err := parseTrack(bytes.NewReader(file.Bytes())); !errors.Is(err, ErrTruncated) {
+		t.Fatalf("expected %v\ngot\n%v\n", ErrTruncated, err)
+	}
+}
@@ -0,0 +1,91 @@
+package vlq
+
+import (
+	"bufio"
+	"io"
+)
+
+// Reader decodes a sequence of VLQ based integers from a stream.
+type Reader struct {
+	r io.ByteReader
+}
+
+// NewReader returns a Reader that reads from r. Since values are read one
+// byte at a time, r is usually a bufio.Reader or bytes.Reader. Bytes that
+// are not part of a value can be read from r between calls.
+func NewReader(r io.ByteReader) *Reader {
+	return &Reader{r: r}
+}
+
+// ReadUint reads the next value as a 64 bit integer. It returns io.EOF
+// when the stream ends before the value starts and ErrTruncated when it
+// ends in the middle of the value.
+func (vr *Reader) ReadUint() (uint64, error) {
+	var d uint64
+
+	for i := 0; ; i++ {
+		b, err := vr.r.ReadByte()
+		switch {
+		case err == io.EOF && i > 0:
+			return 0, ErrTruncated
+		case err != nil:
+			return 0, err
+		}
+
+		var last bool
+		if d, last, err = decodeByte(d, i, b); err != nil {
+			return 0, err
+		}
+
+		if last {
+			return d, nil
+		}
+	}
+}
+
+// ReadInt reads the next value as a zig-zag encoded signed 64 bit integer.
+func (vr *Reader) ReadInt() (int64, error) {
+	d, err := vr.ReadUint()
+	if err != nil {
+		return 0, err
+	}
+
+	return unzigzag(d), nil
+}
+
+// =============================================================================
+
+// Writer encodes a sequence of VLQ based integers to a stream. Writes are
+// buffered, so Flush must be called once all the values are written.
+type Writer struct {
+	w   *bufio.Writer
+	buf [MaxLen64]byte
+}
+
+// NewWriter returns a Writer that writes to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: bufio.NewWriter(w)}
+}
+
+// WriteUint writes the 64 bit integer.
+func (vw *Writer) WriteUint(n uint64) error {
+	_, err := vw.w.Write(AppendUint(vw.buf[:0], n))
+	return err
+}
+
+// WriteInt writes the signed 64 bit integer using zig-zag encoding.
+func (vw *Writer) WriteInt(n int64) error {
+	_, err := vw.w.Write(AppendInt(vw.buf[:0], n))
+	return err
+}
+
+// Write writes bytes that are not VLQ encoded, so values can be mixed with
+// other data in the same stream.
+func (vw *Writer) Write(p []byte) (int, error) {
+	return vw.w.Write(p)
+}
+
+// Flush writes any buffered data to the underlying writer.
+func (vw *Writer) Flush() error {
+	return vw.w.Flush()
+}
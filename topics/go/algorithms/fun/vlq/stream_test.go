@@ -0,0 +1,71 @@
+package vlq
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"testing"
+)
+
+func TestReaderWriter(t *testing.T) {
+	uints := []uint64{0, 127, 128, 16384, math.MaxUint32, math.MaxUint64}
+	ints := []int64{0, -1, 1, -64, 64, math.MinInt64, math.MaxInt64}
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	for _, v := range uints {
+		if err := w.WriteUint(v); err != nil {
+			t.Fatalf("%d - got %v\n", v, err)
+		}
+	}
+	for _, v := range ints {
+		if err := w.WriteInt(v); err != nil {
+			t.Fatalf("%d - got %v\n", v, err)
+		}
+	}
+
+	if buf.Len() != 0 {
+		t.Fatalf("expected nothing written before Flush\ngot\n%d bytes\n", buf.Len())
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatalf("got %v\n", err)
+	}
+
+	r := NewReader(bufio.NewReader(&buf))
+	for _, exp := range uints {
+		if v, err := r.ReadUint(); err != nil || v != exp {
+			t.Fatalf("expected %d\ngot\n%d, %v\n", exp, v, err)
+		}
+	}
+	for _, exp := range ints {
+		if v, err := r.ReadInt(); err != nil || v != exp {
+			t.Fatalf("expected %d\ngot\n%d, %v\n", exp, v, err)
+		}
+	}
+
+	if _, err := r.ReadUint(); err != io.EOF {
+		t.Fatalf("expected %v\ngot\n%v\n", io.EOF, err)
+	}
+}
+
+func TestReaderErrors(t *testing.T) {
+	testCases := []struct {
+		input []byte
+		err   error
+	}{
+		0: {[]byte{}, io.EOF},
+		1: {[]byte{0x81}, ErrTruncated},
+		2: {[]byte{0x80, 0x00}, ErrOverlong},
+		3: {[]byte{0x82, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x00}, ErrOverflow},
+	}
+
+	for i, tc := range testCases {
+		t.Logf("test case %d - %#v\n", i, tc.input)
+		r := NewReader(bytes.NewReader(tc.input))
+		if _, err := r.ReadUint(); !errors.Is(err, tc.err) {
+			t.Fatalf("expected %v\ngot\n%v\n", tc.err, err)
+		}
+	}
+}
@@ -1,100 +1,149 @@
 package vlq
 
 import (
+	"errors"
 	"math"
-	"math/bits"
+)
+
+// Set of error variables returned when decoding malformed input.
+var (
+	ErrTruncated = errors.New("vlq: input ends before the last byte")
+	ErrOverlong  = errors.New("vlq: value encoded with leading zero bytes")
+	ErrOverflow  = errors.New("vlq: value overflows the integer size")
+)
+
+// Maximum number of bytes needed to encode a value of the integer size.
+const (
+	MaxLen32 = 5
+	MaxLen64 = 10
+)
+
+const (
+	continueBit = 0x80 // 1000 0000
+	valueBits   = 0x7F // 0111 1111
 )
 
 // DecodeVarint takes a variable length VLQ based integer and
-// decodes it into a 32 bit integer.
+// decodes it into a 32 bit integer. Bytes after the last byte of the
+// value are ignored. Use DecodeUint to know how many bytes were read.
 func DecodeVarint(input []byte) (uint32, error) {
-	const lastBitSet = 0x80 // 1000 0000
-
-	var d uint32
-	var bitPos int
-
-	for i := len(input) - 1; i >= 0; i-- {
-		n := uint8(input[i])
-
-		// Process the first 7 bits and ignore the 8th.
-		for checkBit := 0; checkBit < 7; checkBit++ {
-
-			// Rotate the last bit off and move it to the back.
-			// Before: 0000 0001
-			// After:  1000 0000
-			n = bits.RotateLeft8(n, -1)
-
-			// Calculate based on only those 1 bits that were rotated.
-			// Convert the bitPos to base 10.
-			if n >= lastBitSet {
-				switch {
-				case bitPos == 0:
-					d++
-				default:
-					base10 := math.Pow(2, float64(bitPos))
-					d += uint32(base10)
-				}
-			}
-
-			// Move the bit position.
-			bitPos++
-		}
+	d, _, err := DecodeUint(input)
+	if err != nil {
+		return 0, err
 	}
 
-	return d, nil
+	if d > math.MaxUint32 {
+		return 0, ErrOverflow
+	}
+
+	return uint32(d), nil
 }
 
 // EncodeVarint takes a 32 bit integer and encodes it into
 // a variable length VLQ based integer.
 func EncodeVarint(n uint32) []byte {
-	const maxBytes = 4
-	const eightBitSet = 0x80      // 1000 0000
-	const lastBitSet = 0x80000000 // 1000 0000 0000 0000
-
-	encoded := make([]byte, maxBytes)
-
-	for bytePos := maxBytes - 1; bytePos >= 0; bytePos-- {
-		var d uint8
-
-		// Process the next 7 bits.
-		for checkBit := 0; checkBit < 7; checkBit++ {
-
-			// Rotate the last bit off and move it to the back.
-			// Before: 0000 0000 0000 0001
-			// After:  1000 0000 0000 0000
-			n = bits.RotateLeft32(n, -1)
-
-			// Calculate based on only those 1 bits that were
-			// rotated. Convert the bit position to base 10.
-			if n >= lastBitSet {
-				switch {
-				case checkBit == 0:
-					d++
-				default:
-					base10 := math.Pow(2, float64(checkBit))
-					d += uint8(base10)
-				}
-			}
+	return AppendUint(make([]byte, 0, MaxLen32), uint64(n))
+}
+
+// =============================================================================
+
+// EncodeUint takes a 64 bit integer and encodes it into a variable length
+// VLQ based integer.
+func EncodeUint(n uint64) []byte {
+	return AppendUint(make([]byte, 0, MaxLen64), n)
+}
+
+// AppendUint appends the VLQ encoding of the 64 bit integer to dst and
+// returns the extended slice.
+func AppendUint(dst []byte, n uint64) []byte {
+
+	// Count the number of 7 bit groups needed. Zero still needs one.
+	groups := 1
+	for v := n >> 7; v != 0; v >>= 7 {
+		groups++
+	}
+
+	// Write the groups with the most significant first. Every byte
+	// except the last has the 8th bit set.
+	for shift := 7 * (groups - 1); shift > 0; shift -= 7 {
+		dst = append(dst, byte(n>>shift)&valueBits|continueBit)
+	}
+
+	return append(dst, byte(n)&valueBits)
+}
+
+// DecodeUint decodes the VLQ based integer at the front of the input into
+// a 64 bit integer. It returns the value and the number of bytes read.
+func DecodeUint(input []byte) (uint64, int, error) {
+	var d uint64
+
+	for i, b := range input {
+		var last bool
+		var err error
+		if d, last, err = decodeByte(d, i, b); err != nil {
+			return 0, 0, err
 		}
 
-		// These values need the 8th bit to be set as 1.
-		if bytePos < 3 {
-			d += eightBitSet
+		if last {
+			return d, i + 1, nil
 		}
+	}
+
+	return 0, 0, ErrTruncated
+}
 
-		// Store the value in reserve order.
-		encoded[bytePos] = d
+// decodeByte adds the byte at the index position of an encoded value to
+// the value decoded so far. It reports whether it was the last byte.
+func decodeByte(d uint64, i int, b byte) (uint64, bool, error) {
+
+	// A leading byte with no value bits adds nothing except length.
+	if i == 0 && b == continueBit {
+		return 0, false, ErrOverlong
 	}
 
-	// Remove leading zero values by finding values that only
-	// have their eight bit set.
-	for bytePos, b := range encoded {
-		if b == eightBitSet {
-			continue
-		}
-		encoded = encoded[bytePos:]
-		break
+	// Make sure the 7 bits being shifted in have room.
+	if d > math.MaxUint64>>7 {
+		return 0, false, ErrOverflow
+	}
+	d = d<<7 | uint64(b&valueBits)
+
+	// The byte without the 8th bit set is the last one.
+	return d, b&continueBit == 0, nil
+}
+
+// =============================================================================
+
+// EncodeInt takes a signed 64 bit integer and encodes it into a variable
+// length VLQ based integer using zig-zag encoding.
+func EncodeInt(n int64) []byte {
+	return AppendInt(make([]byte, 0, MaxLen64), n)
+}
+
+// AppendInt appends the zig-zag VLQ encoding of the signed 64 bit integer
+// to dst and returns the extended slice.
+func AppendInt(dst []byte, n int64) []byte {
+	return AppendUint(dst, zigzag(n))
+}
+
+// DecodeInt decodes the zig-zag VLQ based integer at the front of the input
+// into a signed 64 bit integer. It returns the value and the number of bytes
+// read.
+func DecodeInt(input []byte) (int64, int, error) {
+	d, n, err := DecodeUint(input)
+	if err != nil {
+		return 0, 0, err
 	}
 
-	return encoded
+	return unzigzag(d), n, nil
+}
+
+// zigzag maps signed integers to unsigned integers so values close to zero
+// stay small: 0, -1, 1, -2, 2 become 0, 1, 2, 3, 4.
+func zigzag(n int64) uint64 {
+	return uint64(n<<1) ^ uint64(n>>63)
+}
+
+// unzigzag reverses the mapping of zigzag.
+func unzigzag(n uint64) int64 {
+	return int64(n>>1) ^ -int64(n&1)
 }
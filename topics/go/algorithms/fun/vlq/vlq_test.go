@@ -11,12 +11,27 @@
 	// EncodeVarint takes a 32 bit integer and encodes it into
 	// a variable length VLQ based integer.
 	func EncodeVarint(n uint32) []byte
+
+	// EncodeUint, AppendUint and DecodeUint work with 64 bit integers.
+	func EncodeUint(n uint64) []byte
+	func AppendUint(dst []byte, n uint64) []byte
+	func DecodeUint(input []byte) (uint64, int, error)
+
+	// EncodeInt, AppendInt and DecodeInt work with zig-zag encoded
+	// signed 64 bit integers.
+	func EncodeInt(n int64) []byte
+	func AppendInt(dst []byte, n int64) []byte
+	func DecodeInt(input []byte) (int64, int, error)
 */
 
 package vlq
 
 import (
 	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/rand"
 	"testing"
 )
 
@@ -48,3 +63,150 @@ func TestEncodeDecodeVarint(t *testing.T) {
 		}
 	}
 }
+
+func TestDecodeVarintErrors(t *testing.T) {
+	testCases := []struct {
+		input []byte
+		err   error
+	}{
+		0: {nil, ErrTruncated},
+		1: {[]byte{0x81}, ErrTruncated},
+		2: {[]byte{0xFF, 0xFF, 0xFF}, ErrTruncated},
+		3: {[]byte{0x80, 0x01}, ErrOverlong},
+		4: {[]byte{0x90, 0x80, 0x80, 0x80, 0x00}, ErrOverflow},
+	}
+
+	for i, tc := range testCases {
+		t.Logf("test case %d - %#v\n", i, tc.input)
+		if _, err := DecodeVarint(tc.input); !errors.Is(err, tc.err) {
+			t.Fatalf("expected %v\ngot\n%v\n", tc.err, err)
+		}
+	}
+}
+
+func TestDecodeVarintPrefix(t *testing.T) {
+	testCases := []struct {
+		input  []byte
+		output uint32
+	}{
+		0: {[]byte{0x7F, 0x00}, 127},
+		1: {[]byte{0x81, 0x00, 0x7F}, 128},
+		2: {[]byte{0xFF, 0xFF, 0xFF, 0x7F, 0x81, 0x00}, 268435455},
+	}
+
+	for i, tc := range testCases {
+		t.Logf("test case %d - %#v\n", i, tc.input)
+		if o, err := DecodeVarint(tc.input); err != nil || o != tc.output {
+			t.Fatalf("expected %d\ngot\n%d, %v\n", tc.output, o, err)
+		}
+	}
+}
+
+func TestEncodeDecodeUint(t *testing.T) {
+	testCases := []struct {
+		input  []byte
+		output uint64
+	}{
+		0: {[]byte{0x00}, 0},
+		1: {[]byte{0x8F, 0xFF, 0xFF, 0xFF, 0x7F}, math.MaxUint32},
+		2: {[]byte{0x81, 0x80, 0x80, 0x80, 0x00}, 1 << 28},
+		3: {[]byte{0x81, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x7F}, math.MaxUint64},
+		4: {[]byte{0x81, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x00}, 1 << 63},
+	}
+
+	for i, tc := range testCases {
+		t.Logf("test case %d - %#v\n", i, tc.input)
+		o, n, err := DecodeUint(tc.input)
+		if err != nil || o != tc.output || n != len(tc.input) {
+			t.Fatalf("expected %d, %d\ngot\n%d, %d, %v\n", tc.output, len(tc.input), o, n, err)
+		}
+		if encoded := EncodeUint(tc.output); !bytes.Equal(encoded, tc.input) {
+			t.Fatalf("%d - expected %#v\ngot\n%#v\n", tc.output, tc.input, encoded)
+		}
+	}
+
+	// One more group than math.MaxUint64 can hold.
+	overflow := []byte{0x82, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x00}
+	if _, _, err := DecodeUint(overflow); !errors.Is(err, ErrOverflow) {
+		t.Fatalf("expected %v\ngot\n%v\n", ErrOverflow, err)
+	}
+}
+
+func TestEncodeDecodeInt(t *testing.T) {
+	testCases := []struct {
+		input  []byte
+		output int64
+	}{
+		0: {[]byte{0x00}, 0},
+		1: {[]byte{0x01}, -1},
+		2: {[]byte{0x02}, 1},
+		3: {[]byte{0x7F}, -64},
+		4: {[]byte{0x81, 0x00}, 64},
+		5: {[]byte{0x81, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x7F}, math.MinInt64},
+		6: {[]byte{0x81, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x7E}, math.MaxInt64},
+	}
+
+	for i, tc := range testCases {
+		t.Logf("test case %d - %#v\n", i, tc.input)
+		o, n, err := DecodeInt(tc.input)
+		if err != nil || o != tc.output || n != len(tc.input) {
+			t.Fatalf("expected %d, %d\ngot\n%d, %d, %v\n", tc.output, len(tc.input), o, n, err)
+		}
+		if encoded := EncodeInt(tc.output); !bytes.Equal(encoded, tc.input) {
+			t.Fatalf("%d - expected %#v\ngot\n%#v\n", tc.output, tc.input, encoded)
+		}
+	}
+}
+
+// leb128 converts a VLQ encoding into the encoding used by
+// encoding/binary, where the least significant group comes first.
+func leb128(input []byte) []byte {
+	out := make([]byte, len(input))
+	for i := range input {
+		out[i] = input[len(input)-1-i]&0x7F | 0x80
+	}
+	out[len(out)-1] &= 0x7F
+	return out
+}
+
+func TestBinaryCompat(t *testing.T) {
+	values := []uint64{0, 1, 127, 128, 300, math.MaxUint32, 1 << 62, math.MaxUint64}
+	for i := 0; i < 1000; i++ {
+		values = append(values, rand.Uint64()>>rand.Intn(64))
+	}
+
+	for _, v := range values {
+		encoded := EncodeUint(v)
+		if exp := binary.AppendUvarint(nil, v); !bytes.Equal(leb128(encoded), exp) {
+			t.Fatalf("%d - expected groups of %#v\ngot\n%#v\n", v, exp, encoded)
+		}
+
+		// binary.PutVarint uses the same zig-zag encoding.
+		s := int64(v)
+		if exp := binary.AppendVarint(nil, s); !bytes.Equal(leb128(EncodeInt(s)), exp) {
+			t.Fatalf("%d - expected groups of %#v\ngot\n%#v\n", s, exp, EncodeInt(s))
+		}
+
+		if d, n, err := DecodeUint(append(encoded, 0xFF)); err != nil || d != v || n != len(encoded) {
+			t.Fatalf("%d - got %d, %d, %v\n", v, d, n, err)
+		}
+	}
+}
+
+func FuzzDecodeUint(f *testing.F) {
+	f.Add([]byte{0x81, 0x00})
+	f.Add([]byte{0xFF, 0xFF, 0xFF, 0x7F})
+	f.Add([]byte{0x80})
+
+	f.Fuzz(func(t *testing.T, input []byte) {
+		d, n, err := DecodeUint(input)
+		if err != nil {
+			return
+		}
+
+		// Every value that decodes must encode back to the same bytes.
+		if encoded := EncodeUint(d); !bytes.Equal(encoded, input[:n]) {
+			t.Fatalf("%d - expected %#v\ngot\n%#v\n", d, input[:n], encoded)
+		}
+	})
+}
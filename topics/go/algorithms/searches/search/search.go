@@ -0,0 +1,240 @@
+// Package search provides the searching algorithms found under the searches
+// folder behind a common generic signature. The searches over sorted slices
+// return the index of the first value equal to the target and whether it was
+// found. When the target is not found, the index is where the target would
+// need to be inserted to keep the slice sorted, the same as sort.SearchInts.
+package search
+
+import (
+	"math"
+)
+
+// Ordered describes the types that can be compared with the < operator.
+type Ordered interface {
+	Number | ~string
+}
+
+// Number describes the types the distance between two values can be
+// calculated for, which interpolation search needs.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Func is the common signature shared by the searches over sorted slices.
+type Func[T Ordered] func(s []T, target T) (int, bool)
+
+// found reports whether the index returned by a lower bound search holds
+// the target.
+func found[T Ordered](s []T, idx int, target T) (int, bool) {
+	return idx, idx < len(s) && s[idx] == target
+}
+
+// =============================================================================
+
+// LowerBound returns the index of the first value in the sorted slice that
+// is not less than the target.
+func LowerBound[T Ordered](s []T, target T) int {
+	leftIdx, rightIdx := 0, len(s)
+
+	// The answer is always within [leftIdx, rightIdx].
+	for leftIdx < rightIdx {
+		mid := int(uint(leftIdx+rightIdx) >> 1)
+
+		switch {
+		case s[mid] < target:
+			leftIdx = mid + 1
+		default:
+			rightIdx = mid
+		}
+	}
+
+	return leftIdx
+}
+
+// UpperBound returns the index of the first value in the sorted slice that
+// is greater than the target. The values equal to the target are found at
+// s[LowerBound(s, target):UpperBound(s, target)].
+func UpperBound[T Ordered](s []T, target T) int {
+	leftIdx, rightIdx := 0, len(s)
+
+	// The answer is always within [leftIdx, rightIdx].
+	for leftIdx < rightIdx {
+		mid := int(uint(leftIdx+rightIdx) >> 1)
+
+		switch {
+		case target < s[mid]:
+			rightIdx = mid
+		default:
+			leftIdx = mid + 1
+		}
+	}
+
+	return leftIdx
+}
+
+// Binary searches the sorted slice by checking the middle value and
+// cutting the slice in half every time.
+// - the worst case of this algorithm is O(log n)
+func Binary[T Ordered](s []T, target T) (int, bool) {
+	return found(s, LowerBound(s, target), target)
+}
+
+// BinaryFunc searches a slice sorted by the cmp function, which returns a
+// negative number when the value sorts before the target, zero if they
+// match and a positive number when it sorts after the target.
+// - the worst case of this algorithm is O(log n)
+func BinaryFunc[T, K any](s []T, target K, cmp func(value T, target K) int) (int, bool) {
+	leftIdx, rightIdx := 0, len(s)
+
+	for leftIdx < rightIdx {
+		mid := int(uint(leftIdx+rightIdx) >> 1)
+
+		switch {
+		case cmp(s[mid], target) < 0:
+			leftIdx = mid + 1
+		default:
+			rightIdx = mid
+		}
+	}
+
+	return leftIdx, leftIdx < len(s) && cmp(s[leftIdx], target) == 0
+}
+
+// Interpolation searches the sorted slice by guessing where the target is
+// from its distance to the values at both ends of the range being searched.
+// - the average case for evenly distributed values is O(log log n)
+// - the worst case of this algorithm is O(n)
+func Interpolation[T Number](s []T, target T) (int, bool) {
+	leftIdx, rightIdx := 0, len(s)
+
+	// The answer is always within [leftIdx, rightIdx].
+	for leftIdx < rightIdx {
+		switch {
+		case s[leftIdx] >= target:
+			return found(s, leftIdx, target)
+		case s[rightIdx-1] < target:
+			return found(s, rightIdx, target)
+		}
+
+		// Now s[leftIdx] < target <= s[rightIdx-1], so the distance
+		// between them is not zero. Floats are used so the calculation
+		// can't overflow.
+		low, high := float64(s[leftIdx]), float64(s[rightIdx-1])
+		pos := leftIdx + int(float64(rightIdx-1-leftIdx)*(float64(target)-low)/(high-low))
+
+		// Rounding can move the guess outside the range.
+		switch {
+		case pos < leftIdx:
+			pos = leftIdx
+		case pos > rightIdx-1:
+			pos = rightIdx - 1
+		}
+
+		switch {
+		case s[pos] < target:
+			leftIdx = pos + 1
+		default:
+			rightIdx = pos
+		}
+	}
+
+	return found(s, leftIdx, target)
+}
+
+// Jump searches the sorted slice by jumping ahead in blocks of √n values
+// until a block ends with a value that is not less than the target, and
+// then checking that block one value at a time.
+// - the worst case of this algorithm is O(√n)
+func Jump[T Ordered](s []T, target T) (int, bool) {
+	jump := int(math.Sqrt(float64(len(s))))
+	if jump < 1 {
+		jump = 1
+	}
+
+	// Find the block the target belongs in.
+	var start int
+	for start < len(s) {
+		end := start + jump
+		if end > len(s) {
+			end = len(s)
+		}
+		if s[end-1] >= target {
+			break
+		}
+		start = end
+	}
+
+	// Check the block one value at a time.
+	for start < len(s) && s[start] < target {
+		start++
+	}
+
+	return found(s, start, target)
+}
+
+// Exponential searches the sorted slice by doubling the range being
+// searched until its last value is not less than the target, then using
+// binary search within that range. It is faster than binary search when
+// the target is near the front of the slice.
+// - the worst case of this algorithm is O(log i) where i is the index
+func Exponential[T Ordered](s []T, target T) (int, bool) {
+	return Unbounded(func(idx int) (T, bool) {
+		if idx >= len(s) {
+			var zero T
+			return zero, false
+		}
+		return s[idx], true
+	}, target)
+}
+
+// Unbounded performs an exponential search over a sorted sequence whose
+// length is not known, such as a stream. The at function returns the value
+// at the index and false once the index is past the end of the sequence.
+// The index returned when the target is not found is where it would need
+// to be inserted, which can be the length of the sequence.
+func Unbounded[T Ordered](at func(idx int) (T, bool), target T) (int, bool) {
+
+	// Double the bound until the value there is not less than the
+	// target or the bound is past the end of the sequence.
+	leftIdx, rightIdx := 0, 1
+	for {
+		value, ok := at(rightIdx - 1)
+		if !ok || value >= target {
+			break
+		}
+		leftIdx = rightIdx
+		rightIdx *= 2
+	}
+
+	// The answer is within [leftIdx, rightIdx]. Indexes past the end of
+	// the sequence are treated as values greater than the target.
+	for leftIdx < rightIdx {
+		mid := int(uint(leftIdx+rightIdx) >> 1)
+
+		switch value, ok := at(mid); {
+		case ok && value < target:
+			leftIdx = mid + 1
+		default:
+			rightIdx = mid
+		}
+	}
+
+	value, ok := at(leftIdx)
+	return leftIdx, ok && value == target
+}
+
+// Linear searches the slice one value at a time from the front, so it
+// works on slices that are not sorted. Since there is no insertion point
+// in an unsorted slice, the index is -1 when the target is not found.
+// - the worst case of this algorithm is O(n)
+func Linear[T comparable](s []T, target T) (int, bool) {
+	for idx, value := range s {
+		if value == target {
+			return idx, true
+		}
+	}
+
+	return -1, false
+}
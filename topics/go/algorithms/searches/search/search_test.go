@@ -0,0 +1,240 @@
+package search_test
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ardanlabs/gotraining/topics/go/algorithms/searches/search"
+)
+
+const succeed = "\u2713"
+const failed = "\u2717"
+
+var sidx int
+
+// searches are the algorithms for sorted slices checked by every test.
+var searches = []struct {
+	name   string
+	search search.Func[int]
+}{
+	{"binary", search.Binary[int]},
+	{"interpolation", search.Interpolation[int]},
+	{"jump", search.Jump[int]},
+	{"exponential", search.Exponential[int]},
+}
+
+// TestSearch checks every search finds the first of the duplicate values
+// and the insertion point of values that are missing.
+func TestSearch(t *testing.T) {
+	list := []int{-10, 1, 3, 3, 3, 7, 9, 9, 100}
+
+	tt := []struct {
+		list   []int
+		find   int
+		expect int
+		found  bool
+	}{
+		{nil, 5, 0, false},
+		{[]int{2}, 2, 0, true},
+		{[]int{2}, 3, 1, false},
+		{list, -10, 0, true},
+		{list, -11, 0, false},
+		{list, 3, 2, true},
+		{list, 4, 5, false},
+		{list, 9, 6, true},
+		{list, 100, 8, true},
+		{list, 101, 9, false},
+		{[]int{5, 5, 5, 5}, 5, 0, true},
+	}
+
+	t.Log("Given the need to test searching sorted lists.")
+	{
+		for _, s := range searches {
+			t.Logf("\tWhen searching with %s.", s.name)
+			{
+				for testID, test := range tt {
+					idx, found := s.search(test.list, test.find)
+					if idx != test.expect || found != test.found {
+						t.Fatalf("\t%s\tTest %d:\tShould find %d at %d, %v : %d, %v", failed, testID, test.find, test.expect, test.found, idx, found)
+					}
+					t.Logf("\t%s\tTest %d:\tShould find %d at %d, %v.", succeed, testID, test.find, test.expect, test.found)
+				}
+			}
+		}
+	}
+}
+
+// TestBounds checks the range of duplicate values is found.
+func TestBounds(t *testing.T) {
+	list := []string{"a", "b", "b", "b", "c", "e"}
+
+	tt := []struct {
+		find  string
+		lower int
+		upper int
+	}{
+		{"", 0, 0},
+		{"a", 0, 1},
+		{"b", 1, 4},
+		{"d", 5, 5},
+		{"f", 6, 6},
+	}
+
+	t.Log("Given the need to find the range of duplicate values.")
+	{
+		for testID, test := range tt {
+			t.Logf("\tTest %d:\tWhen searching for %q.", testID, test.find)
+			{
+				lower, upper := search.LowerBound(list, test.find), search.UpperBound(list, test.find)
+				if lower != test.lower || upper != test.upper {
+					t.Fatalf("\t%s\tTest %d:\tShould get the range [%d, %d) : [%d, %d)", failed, testID, test.lower, test.upper, lower, upper)
+				}
+				t.Logf("\t%s\tTest %d:\tShould get the range [%d, %d).", succeed, testID, test.lower, test.upper)
+			}
+		}
+	}
+}
+
+// TestBinaryFunc checks searching a list of structs by a key.
+func TestBinaryFunc(t *testing.T) {
+	type user struct {
+		id   int
+		name string
+	}
+	users := []user{{1, "bill"}, {4, "ale"}, {9, "jack"}}
+	cmp := func(u user, id int) int { return u.id - id }
+
+	t.Log("Given the need to search a list of structs by a key.")
+	{
+		idx, found := search.BinaryFunc(users, 4, cmp)
+		if !found || users[idx].name != "ale" {
+			t.Fatalf("\t%s\tShould find the user with id 4 : %d, %v", failed, idx, found)
+		}
+		t.Logf("\t%s\tShould find the user with id 4.", succeed)
+
+		idx, found = search.BinaryFunc(users, 5, cmp)
+		if found || idx != 2 {
+			t.Fatalf("\t%s\tShould not find the user with id 5 : %d, %v", failed, idx, found)
+		}
+		t.Logf("\t%s\tShould not find the user with id 5.", succeed)
+	}
+}
+
+// TestUnbounded checks searching a sequence without a known length.
+func TestUnbounded(t *testing.T) {
+	const size = 1000
+	var calls int
+
+	// The sequence holds the even numbers below 2*size.
+	at := func(idx int) (int, bool) {
+		calls++
+		if idx >= size {
+			return 0, false
+		}
+		return idx * 2, true
+	}
+
+	t.Log("Given the need to search a sequence without a known length.")
+	{
+		for testID, find := range []int{0, 2, 3, 500, 1998, 1999, 5000} {
+			t.Logf("\tTest %d:\tWhen searching for %d.", testID, find)
+			{
+				calls = 0
+				idx, found := search.Unbounded(at, find)
+
+				exp := (find + 1) / 2
+				if exp > size {
+					exp = size
+				}
+				if idx != exp || found != (find%2 == 0 && find < 2*size) {
+					t.Fatalf("\t%s\tTest %d:\tShould find the index %d : %d, %v", failed, testID, exp, idx, found)
+				}
+				t.Logf("\t%s\tTest %d:\tShould find the index %d.", succeed, testID, exp)
+
+				if calls > 45 {
+					t.Fatalf("\t%s\tTest %d:\tShould read a logarithmic number of values : %d", failed, testID, calls)
+				}
+				t.Logf("\t%s\tTest %d:\tShould read a logarithmic number of values.", succeed, testID)
+			}
+		}
+	}
+}
+
+// TestLinear checks searching a list that is not sorted.
+func TestLinear(t *testing.T) {
+	list := strings.Fields("go is fun and go is fast")
+
+	t.Log("Given the need to search a list that is not sorted.")
+	{
+		if idx, found := search.Linear(list, "is"); idx != 1 || !found {
+			t.Fatalf("\t%s\tShould find the first match : %d, %v", failed, idx, found)
+		}
+		t.Logf("\t%s\tShould find the first match.", succeed)
+
+		if idx, found := search.Linear(list, "slow"); idx != -1 || found {
+			t.Fatalf("\t%s\tShould not find a missing value : %d, %v", failed, idx, found)
+		}
+		t.Logf("\t%s\tShould not find a missing value.", succeed)
+	}
+}
+
+// FuzzSearch cross checks every search against sort.SearchInts. The bytes
+// are turned into a sorted list with many duplicate values.
+func FuzzSearch(f *testing.F) {
+	f.Add([]byte{}, 0)
+	f.Add([]byte{1, 1, 1, 1}, 1)
+	f.Add([]byte{0, 255, 3, 3, 9}, 3)
+	f.Add([]byte{10, 20, 30, 40}, 25)
+	f.Add([]byte{1, 2, 127}, math.MaxInt)
+	f.Add([]byte{128, 0, 1}, math.MinInt)
+
+	f.Fuzz(func(t *testing.T, data []byte, target int) {
+		list := make([]int, len(data))
+		for i, b := range data {
+			list[i] = int(int8(b))
+		}
+		sort.Ints(list)
+
+		exp := sort.SearchInts(list, target)
+		expFound := exp < len(list) && list[exp] == target
+
+		for _, s := range searches {
+			idx, found := s.search(list, target)
+			if idx != exp || found != expFound {
+				t.Fatalf("%s(%v, %d) = %d, %v, Expected %d, %v", s.name, list, target, idx, found, exp, expFound)
+			}
+		}
+
+		// Searching for target+1 would overflow for the largest int.
+		expUpper := sort.Search(len(list), func(i int) bool { return list[i] > target })
+		if upper := search.UpperBound(list, target); upper != expUpper {
+			t.Fatalf("UpperBound(%v, %d) = %d, Expected %d", list, target, upper, expUpper)
+		}
+
+		idx, found := search.Linear(list, target)
+		if found != expFound || (found && idx != exp) {
+			t.Fatalf("Linear(%v, %d) = %d, %v, Expected %d, %v", list, target, idx, found, exp, expFound)
+		}
+	})
+}
+
+// BenchmarkSearch runs every search over evenly distributed values.
+func BenchmarkSearch(b *testing.B) {
+	for _, size := range []int{100, 10000, 1000000} {
+		list := make([]int, size)
+		for i := range list {
+			list[i] = i * 3
+		}
+
+		for _, s := range searches {
+			b.Run(fmt.Sprintf("%s/%d", s.name, size), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					sidx, _ = s.search(list, (i*7919)%(size*3))
+				}
+			})
+		}
+	}
+}
@@ -5,17 +5,19 @@ package main
 import (
 	"fmt"
 	"math/rand"
+
+	"github.com/ardanlabs/gotraining/topics/go/algorithms/simulations/montecarlo"
 )
 
 // simulateBirthdayMatches returns true if the same number is selected
 // twice by the random number generator selecting a number between
 // 0 and 365 for a specified group of people.
-func simulateBirthdayMatches(numOfPeople int) bool {
+func simulateBirthdayMatches(rnd *rand.Rand, numOfPeople int) bool {
 	const daysInYear = 365
 
 	seen := make(map[int]bool)
 	for i := 0; i < numOfPeople; i++ {
-		day := rand.Intn(daysInYear)
+		day := rnd.Intn(daysInYear)
 		if seen[day] {
 			return true
 		}
@@ -25,19 +27,25 @@ func simulateBirthdayMatches(numOfPeople int) bool {
 	return false
 }
 
-// simulateBirthdays returns the fraction of groups
-// that have two people with the same birthday.
-func simulateBirthdays(numOfPeople, runs int) float64 {
-	same := 0
-	for i := 0; i < runs; i++ {
-		if simulateBirthdayMatches(numOfPeople) {
-			same++
-		}
-	}
-
-	return float64(same) / float64(runs)
+// simulateBirthdays returns the histogram of groups that have two
+// people with the same birthday.
+func simulateBirthdays(cfg montecarlo.Config, numOfPeople int) (*montecarlo.Histogram[bool], error) {
+	return montecarlo.Run(cfg, func(rnd *rand.Rand) bool {
+		return simulateBirthdayMatches(rnd, numOfPeople)
+	})
 }
 
 func main() {
-	fmt.Println(simulateBirthdays(23, 1_000_00))
+	h, err := simulateBirthdays(montecarlo.Config{Trials: 1_000_00, Seed: 1}, 23)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	ci, err := h.Interval(true, 0.95)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("%.4f (95%% interval %.4f - %.4f)\n", ci.Estimate, ci.Low, ci.High)
 }
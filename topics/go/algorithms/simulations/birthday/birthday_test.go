@@ -0,0 +1,47 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/ardanlabs/gotraining/topics/go/algorithms/simulations/montecarlo"
+)
+
+const succeed = "\u2713"
+const failed = "\u2717"
+
+func TestSimulateBirthdays(t *testing.T) {
+	tt := []struct {
+		people   int
+		expected float64
+	}{
+		{1, 0},
+		{10, 0.1169},
+		{23, 0.5073},
+		{50, 0.9704},
+	}
+
+	t.Log("Given the need to simulate the birthday problem.")
+	{
+		for testID, test := range tt {
+			t.Logf("\tTest %d:\tWhen checking groups of %d people.", testID, test.people)
+			{
+				cfg := montecarlo.Config{Trials: 100_000, Seed: 1}
+				h, err := simulateBirthdays(cfg, test.people)
+				if err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to run the simulation : %v", failed, testID, err)
+				}
+
+				ci, err := h.Interval(true, 0.999)
+				if err != nil || !ci.Contains(test.expected) {
+					t.Fatalf("\t%s\tTest %d:\tShould find %.4f in the interval : %+v %v", failed, testID, test.expected, ci, err)
+				}
+				t.Logf("\t%s\tTest %d:\tShould find %.4f in the interval.", succeed, testID, test.expected)
+
+				if again, _ := simulateBirthdays(cfg, test.people); again.Counts[true] != h.Counts[true] {
+					t.Fatalf("\t%s\tTest %d:\tShould get the same result for the same seed : %d, Expected %d", failed, testID, again.Counts[true], h.Counts[true])
+				}
+				t.Logf("\t%s\tTest %d:\tShould get the same result for the same seed.", succeed, testID)
+			}
+		}
+	}
+}
@@ -4,36 +4,37 @@ package main
 import (
 	"fmt"
 	"math/rand"
+
+	"github.com/ardanlabs/gotraining/topics/go/algorithms/simulations/montecarlo"
 )
 
 // diceRoll simulate a dice roll.
-func diceRoll() int {
+func diceRoll(rnd *rand.Rand) int {
 	// Intn(6) returns values in the range 0-5 (inclusive), we want 1-6.
-	return rand.Intn(6) + 1
+	return rnd.Intn(6) + 1
 }
 
 // simulate runs n simulation of two game cube rolls.
-// It returns the percentage for each total of first and second roll.
-func simulate(runs int) map[int]float64 {
-	counts := make(map[int]int)
-	for i := 0; i < runs; i++ {
-		val := diceRoll() + diceRoll()
-		counts[val]++
-	}
-
-	// Convert from counts to fractions.
-	fracs := make(map[int]float64)
-	for val, count := range counts {
-		frac := float64(count) / float64(runs)
-		fracs[val] = frac
-	}
-
-	return fracs
+// It returns the histogram for each total of first and second roll.
+func simulate(cfg montecarlo.Config) (*montecarlo.Histogram[int], error) {
+	return montecarlo.Run(cfg, func(rnd *rand.Rand) int {
+		return diceRoll(rnd) + diceRoll(rnd)
+	})
 }
 
 func main() {
-	fracs := simulate(1_000_000)
+	h, err := simulate(montecarlo.Config{Trials: 1_000_000, Seed: 1})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
 	for i := 2; i <= 12; i++ {
-		fmt.Printf("%2d -> %.2f\n", i, fracs[i])
+		ci, err := h.Interval(i, 0.95)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Printf("%2d -> %.2f (%.3f - %.3f)\n", i, ci.Estimate, ci.Low, ci.High)
 	}
 }
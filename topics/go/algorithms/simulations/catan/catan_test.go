@@ -0,0 +1,44 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/ardanlabs/gotraining/topics/go/algorithms/simulations/montecarlo"
+)
+
+const succeed = "\u2713"
+const failed = "\u2717"
+
+func TestSimulate(t *testing.T) {
+	t.Log("Given the need to simulate rolling two dice.")
+	{
+		t.Logf("\tTest 0:\tWhen rolling the dice many times.")
+		{
+			h, err := simulate(montecarlo.Config{Trials: 200_000, Seed: 1})
+			if err != nil {
+				t.Fatalf("\t%s\tTest 0:\tShould be able to run the simulation : %v", failed, err)
+			}
+
+			// There are 36 combinations and n-1 of them add up to n up to
+			// 7, then 13-n of them after.
+			for total := 2; total <= 12; total++ {
+				ways := total - 1
+				if total > 7 {
+					ways = 13 - total
+				}
+				expected := float64(ways) / 36
+
+				ci, err := h.Interval(total, 0.999)
+				if err != nil || !ci.Contains(expected) {
+					t.Fatalf("\t%s\tTest 0:\tShould find %.4f for %d in the interval : %+v", failed, expected, total, ci)
+				}
+			}
+			t.Logf("\t%s\tTest 0:\tShould find the expected fraction of every total.", succeed)
+
+			if len(h.Counts) != 11 {
+				t.Fatalf("\t%s\tTest 0:\tShould only see totals from 2 to 12 : %v", failed, h.Counts)
+			}
+			t.Logf("\t%s\tTest 0:\tShould only see totals from 2 to 12.", succeed)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+// Package montecarlo runs simulations that answer a question by repeating a
+// random trial many times and counting the outcomes. The trials are split
+// into batches that are run by a pool of goroutines.
+//
+// Every batch gets its own rand.Rand seeded from the simulation seed and the
+// batch number. Since a batch always produces the same outcomes no matter
+// which goroutine runs it, a simulation gives the same histogram for the
+// same seed on any number of workers.
+package montecarlo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// batchSize is the number of trials run with the same rand.Rand.
+const batchSize = 4096
+
+// Config describes how a simulation is run.
+type Config struct {
+
+	// Trials is the number of times the trial is run.
+	Trials int
+
+	// Workers is the number of goroutines running trials. It is
+	// runtime.GOMAXPROCS when it is zero.
+	Workers int
+
+	// Seed makes the outcomes of the simulation reproducible.
+	Seed int64
+}
+
+// Trial runs a single trial using the random source and returns its
+// outcome. The source must not be used after the trial returns.
+type Trial[K comparable] func(rnd *rand.Rand) K
+
+// Run runs the trial the number of times in the config and returns the
+// histogram of the outcomes. The number of trials must be positive.
+func Run[K comparable](cfg Config, trial Trial[K]) (*Histogram[K], error) {
+	if cfg.Trials <= 0 {
+		return nil, fmt.Errorf("number of trials %d must be positive", cfg.Trials)
+	}
+
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	batches := (cfg.Trials + batchSize - 1) / batchSize
+	if workers > batches {
+		workers = batches
+	}
+
+	h := Histogram[K]{
+		Counts: make(map[K]int),
+		Trials: cfg.Trials,
+	}
+
+	var next int64
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	wg.Add(workers)
+
+	for w := 0; w < workers; w++ {
+		go func() {
+			counts := make(map[K]int)
+			defer func() {
+				mu.Lock()
+				defer mu.Unlock()
+				for k, v := range counts {
+					h.Counts[k] += v
+				}
+				wg.Done()
+			}()
+
+			// Take the next batch until they are all done.
+			for {
+				batch := int(atomic.AddInt64(&next, 1) - 1)
+				if batch >= batches {
+					return
+				}
+
+				n := batchSize
+				if batch == batches-1 {
+					n = cfg.Trials - batch*batchSize
+				}
+
+				rnd := rand.New(rand.NewSource(seedFor(cfg.Seed, batch)))
+				for i := 0; i < n; i++ {
+					counts[trial(rnd)]++
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	return &h, nil
+}
+
+// seedFor returns the seed of the batch. The splitmix64 mixing function is
+// used so the seeds of batches next to each other are not related.
+func seedFor(seed int64, batch int) int64 {
+	z := uint64(seed) + uint64(batch+1)*0x9E3779B97F4A7C15
+	z = (z ^ (z >> 30)) * 0xBF58476D1CE4E5B9
+	z = (z ^ (z >> 27)) * 0x94D049BB133111EB
+	return int64(z ^ (z >> 31))
+}
+
+// =============================================================================
+
+// Histogram holds the number of times every outcome of a simulation was
+// seen.
+type Histogram[K comparable] struct {
+	Counts map[K]int
+	Trials int
+}
+
+// Fraction returns the fraction of the trials with the outcome.
+func (h *Histogram[K]) Fraction(outcome K) float64 {
+	if h.Trials == 0 {
+		return 0
+	}
+
+	return float64(h.Counts[outcome]) / float64(h.Trials)
+}
+
+// Interval returns the confidence interval of the probability of the
+// outcome at the confidence level, like 0.95 for 95%.
+func (h *Histogram[K]) Interval(outcome K, confidence float64) (Interval, error) {
+	return Wilson(h.Counts[outcome], h.Trials, confidence)
+}
+
+// =============================================================================
+
+// Interval is a range of values that holds the true value of an estimate
+// with a level of confidence.
+type Interval struct {
+	Estimate float64
+	Low      float64
+	High     float64
+}
+
+// Contains reports whether the value is within the interval.
+func (i Interval) Contains(v float64) bool {
+	return i.Low <= v && v <= i.High
+}
+
+// Scale returns the interval multiplied by the factor, which is used when
+// the value of interest is a multiple of a probability.
+func (i Interval) Scale(factor float64) Interval {
+	i = Interval{
+		Estimate: i.Estimate * factor,
+		Low:      i.Low * factor,
+		High:     i.High * factor,
+	}
+	if i.Low > i.High {
+		i.Low, i.High = i.High, i.Low
+	}
+
+	return i
+}
+
+// Wilson returns the Wilson score interval of the probability of success
+// given the number of successes in the trials, at the confidence level.
+// Unlike the normal approximation, it stays within [0, 1] and works when
+// the number of successes is close to 0 or the number of trials. The
+// confidence level must be between 0 and 1, exclusive.
+//
+// https://en.wikipedia.org/wiki/Binomial_proportion_confidence_interval
+func Wilson(successes, trials int, confidence float64) (Interval, error) {
+	switch {
+	case !(confidence > 0 && confidence < 1):
+		return Interval{}, fmt.Errorf("confidence level %v must be between 0 and 1", confidence)
+	case trials < 0 || successes < 0 || successes > trials:
+		return Interval{}, errors.New("successes must be between 0 and the number of trials")
+	case trials == 0:
+		return Interval{Low: 0, High: 1}, nil
+	}
+
+	n := float64(trials)
+	p := float64(successes) / n
+
+	// z is how many standard deviations of the normal distribution hold
+	// the confidence level, 1.96 for 95%.
+	z := math.Sqrt2 * math.Erfinv(confidence)
+	z2 := z * z
+
+	center := (p + z2/(2*n)) / (1 + z2/n)
+	margin := z / (1 + z2/n) * math.Sqrt(p*(1-p)/n+z2/(4*n*n))
+
+	return Interval{
+		Estimate: p,
+		Low:      math.Max(0, center-margin),
+		High:     math.Min(1, center+margin),
+	}, nil
+}
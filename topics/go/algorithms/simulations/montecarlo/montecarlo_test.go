@@ -0,0 +1,136 @@
+package montecarlo_test
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/ardanlabs/gotraining/topics/go/algorithms/simulations/montecarlo"
+)
+
+const succeed = "\u2713"
+const failed = "\u2717"
+
+// coin is a trial that flips a biased coin.
+func coin(rnd *rand.Rand) bool {
+	return rnd.Float64() < 0.3
+}
+
+func TestRun(t *testing.T) {
+	t.Log("Given the need to run reproducible simulations.")
+	{
+		t.Logf("\tTest 0:\tWhen running the same seed on different workers.")
+		{
+			var first *montecarlo.Histogram[bool]
+			for _, workers := range []int{1, 2, 3, 8, 0} {
+				cfg := montecarlo.Config{Trials: 100_001, Workers: workers, Seed: 42}
+				h, err := montecarlo.Run(cfg, coin)
+				if err != nil {
+					t.Fatalf("\t%s\tTest 0:\tShould be able to run the simulation : %v", failed, err)
+				}
+
+				if h.Counts[true]+h.Counts[false] != cfg.Trials {
+					t.Fatalf("\t%s\tTest 0:\tShould run %d trials : %d", failed, cfg.Trials, h.Counts[true]+h.Counts[false])
+				}
+				if first != nil && !reflect.DeepEqual(h, first) {
+					t.Fatalf("\t%s\tTest 0:\tShould get the same histogram with %d workers : %v, Expected %v", failed, workers, h.Counts, first.Counts)
+				}
+				first = h
+			}
+			t.Logf("\t%s\tTest 0:\tShould get the same histogram on any number of workers.", succeed)
+
+			other, _ := montecarlo.Run(montecarlo.Config{Trials: 100_001, Seed: 43}, coin)
+			if reflect.DeepEqual(other, first) {
+				t.Fatalf("\t%s\tTest 0:\tShould get a different histogram with a different seed.", failed)
+			}
+			t.Logf("\t%s\tTest 0:\tShould get a different histogram with a different seed.", succeed)
+		}
+
+		t.Logf("\tTest 1:\tWhen estimating a probability.")
+		{
+			h, _ := montecarlo.Run(montecarlo.Config{Trials: 100_000, Seed: 1}, coin)
+
+			ci, err := h.Interval(true, 0.999)
+			if err != nil || !ci.Contains(0.3) || ci.Estimate != h.Fraction(true) {
+				t.Fatalf("\t%s\tTest 1:\tShould find 0.3 in the interval : %+v %v", failed, ci, err)
+			}
+			if width := ci.High - ci.Low; width > 0.01 {
+				t.Fatalf("\t%s\tTest 1:\tShould get a narrow interval : %f", failed, width)
+			}
+			t.Logf("\t%s\tTest 1:\tShould find 0.3 in the interval : %+v", succeed, ci)
+		}
+
+		t.Logf("\tTest 2:\tWhen the number of trials is not positive.")
+		{
+			for _, trials := range []int{0, -1} {
+				if _, err := montecarlo.Run(montecarlo.Config{Trials: trials}, coin); err == nil {
+					t.Fatalf("\t%s\tTest 2:\tShould get an error for %d trials.", failed, trials)
+				}
+			}
+			t.Logf("\t%s\tTest 2:\tShould get an error.", succeed)
+		}
+	}
+}
+
+func TestWilson(t *testing.T) {
+	tt := []struct {
+		successes  int
+		trials     int
+		confidence float64
+		low        float64
+		high       float64
+	}{
+		{50, 100, 0.95, 0.4038, 0.5962},
+		{0, 10, 0.95, 0, 0.2775},
+		{10, 10, 0.95, 0.7225, 1},
+		{1, 1000, 0.99, 0.0001, 0.0085},
+	}
+
+	t.Log("Given the need to calculate confidence intervals.")
+	{
+		for testID, test := range tt {
+			t.Logf("\tTest %d:\tWhen %d of %d trials succeed at %.2f.", testID, test.successes, test.trials, test.confidence)
+			{
+				ci, err := montecarlo.Wilson(test.successes, test.trials, test.confidence)
+				if err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to calculate the interval : %v", failed, testID, err)
+				}
+				if math.Abs(ci.Low-test.low) > 1e-4 || math.Abs(ci.High-test.high) > 1e-4 {
+					t.Fatalf("\t%s\tTest %d:\tShould get the interval [%.4f, %.4f] : [%.4f, %.4f]", failed, testID, test.low, test.high, ci.Low, ci.High)
+				}
+				t.Logf("\t%s\tTest %d:\tShould get the interval [%.4f, %.4f].", succeed, testID, test.low, test.high)
+			}
+		}
+
+		testID := len(tt)
+		t.Logf("\tTest %d:\tWhen the arguments are invalid.", testID)
+		{
+			bad := []struct {
+				successes  int
+				trials     int
+				confidence float64
+			}{
+				{5, 10, 0},
+				{5, 10, 1},
+				{5, 10, -0.5},
+				{5, 10, 1.5},
+				{5, 10, math.NaN()},
+				{11, 10, 0.95},
+				{-1, 10, 0.95},
+			}
+			for _, b := range bad {
+				if ci, err := montecarlo.Wilson(b.successes, b.trials, b.confidence); err == nil {
+					t.Fatalf("\t%s\tTest %d:\tShould get an error for %d of %d at %v : %+v", failed, testID, b.successes, b.trials, b.confidence, ci)
+				}
+			}
+			t.Logf("\t%s\tTest %d:\tShould get an error.", succeed, testID)
+		}
+	}
+}
+
+func BenchmarkRun(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		montecarlo.Run(montecarlo.Config{Trials: 1_000_000, Seed: int64(i)}, coin)
+	}
+}
@@ -5,38 +5,31 @@ package main
 import (
 	"fmt"
 	"math/rand"
+
+	"github.com/ardanlabs/gotraining/topics/go/algorithms/simulations/montecarlo"
 )
 
 // stayWinsGame is a single simulation of game. It return true if "stay" strategy wins.
-func stayWinsGame() bool {
-	carDoor := rand.Intn(3)
-	playerDoor := rand.Intn(3)
+func stayWinsGame(rnd *rand.Rand) bool {
+	carDoor := rnd.Intn(3)
+	playerDoor := rnd.Intn(3)
 
 	return carDoor == playerDoor
 }
 
-// fraction return a/b as float
-func fraction(a, b int) float64 {
-	return float64(a) / float64(b)
+// simulation runs n games and return the histogram of games where "stay"
+// strategy won. The "switch" strategy won every other game.
+func simulation(cfg montecarlo.Config) (*montecarlo.Histogram[bool], error) {
+	return montecarlo.Run(cfg, stayWinsGame)
 }
 
-// simulation runs n games and return the fraction of games where "stay"
-// strategy won and fraction where "switch" strategy won
-func simulation(n int) (float64, float64) {
-	stayWin, switchWin := 0, 0
-	for i := 0; i < n; i++ {
-		if stayWinsGame() {
-			stayWin++
-		} else {
-			switchWin++
-		}
+func main() {
+	h, err := simulation(montecarlo.Config{Trials: 1_000_000, Seed: 1})
+	if err != nil {
+		fmt.Println(err)
+		return
 	}
 
-	return fraction(stayWin, n), fraction(switchWin, n)
-}
-
-func main() {
-	stayFrac, switchFrac := simulation(1_000_000)
-	fmt.Printf("stay: %%%.2f\n", stayFrac*100)
-	fmt.Printf("switch: %%%.2f\n", switchFrac*100)
+	fmt.Printf("stay: %%%.2f\n", h.Fraction(true)*100)
+	fmt.Printf("switch: %%%.2f\n", h.Fraction(false)*100)
 }
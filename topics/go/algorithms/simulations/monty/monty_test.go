@@ -0,0 +1,33 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/ardanlabs/gotraining/topics/go/algorithms/simulations/montecarlo"
+)
+
+const succeed = "\u2713"
+const failed = "\u2717"
+
+func TestSimulation(t *testing.T) {
+	t.Log("Given the need to simulate the Monty Hall problem.")
+	{
+		t.Logf("\tTest 0:\tWhen playing many games.")
+		{
+			h, err := simulation(montecarlo.Config{Trials: 100_000, Seed: 1})
+			if err != nil {
+				t.Fatalf("\t%s\tTest 0:\tShould be able to run the simulation : %v", failed, err)
+			}
+
+			if ci, err := h.Interval(true, 0.999); err != nil || !ci.Contains(1.0/3) {
+				t.Fatalf("\t%s\tTest 0:\tShould win a third of the games by staying : %+v %v", failed, ci, err)
+			}
+			t.Logf("\t%s\tTest 0:\tShould win a third of the games by staying.", succeed)
+
+			if ci, err := h.Interval(false, 0.999); err != nil || !ci.Contains(2.0/3) {
+				t.Fatalf("\t%s\tTest 0:\tShould win two thirds of the games by switching : %+v %v", failed, ci, err)
+			}
+			t.Logf("\t%s\tTest 0:\tShould win two thirds of the games by switching.", succeed)
+		}
+	}
+}
@@ -6,26 +6,42 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+
+	"github.com/ardanlabs/gotraining/topics/go/algorithms/simulations/montecarlo"
 )
 
-// calculatePi calculates the value of π using n points.
-func calculatePi(n int) float64 {
+// insideCircle returns true if a random point in the square (0, 0) <-> (1, 1)
+// falls inside the circle.
+func insideCircle(rnd *rand.Rand) bool {
 	const radius = 1
-	var innerPoints int
 
-	for i := 0; i < n; i++ {
-		x, y := rand.Float64(), rand.Float64()
-		if math.Sqrt(x*x+y*y) < radius {
-			innerPoints++
-		}
+	x, y := rnd.Float64(), rnd.Float64()
+	return math.Sqrt(x*x+y*y) < radius
+}
+
+// calculatePi calculates the value of π using a random point for every
+// trial in cfg, and the interval holding π at the confidence level.
+func calculatePi(cfg montecarlo.Config, confidence float64) (montecarlo.Interval, error) {
+	h, err := montecarlo.Run(cfg, insideCircle)
+	if err != nil {
+		return montecarlo.Interval{}, err
+	}
+
+	ci, err := h.Interval(true, confidence)
+	if err != nil {
+		return montecarlo.Interval{}, err
 	}
 
-	ratio := float64(innerPoints) / float64(n)
 	// Since radius = 1, then circle area is π. We calculated points in range
 	// (0, 0) <-> (1, 1) which is 1/4 of the circle.
-	return 4 * ratio
+	return ci.Scale(4), nil
 }
 
 func main() {
-	fmt.Println("π =", calculatePi(100_000_000))
+	ci, err := calculatePi(montecarlo.Config{Trials: 100_000_000, Seed: 1}, 0.95)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("π = %f (95%% interval %f - %f)\n", ci.Estimate, ci.Low, ci.High)
 }
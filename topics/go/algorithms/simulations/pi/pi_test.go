@@ -0,0 +1,27 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ardanlabs/gotraining/topics/go/algorithms/simulations/montecarlo"
+)
+
+const succeed = "\u2713"
+const failed = "\u2717"
+
+func TestCalculatePi(t *testing.T) {
+	t.Log("Given the need to calculate π using simulation.")
+	{
+		for testID, trials := range []int{10_000, 1_000_000} {
+			t.Logf("\tTest %d:\tWhen using %d points.", testID, trials)
+			{
+				ci, err := calculatePi(montecarlo.Config{Trials: trials, Seed: 1}, 0.999)
+				if err != nil || !ci.Contains(math.Pi) {
+					t.Fatalf("\t%s\tTest %d:\tShould find π in the interval : %+v %v", failed, testID, ci, err)
+				}
+				t.Logf("\t%s\tTest %d:\tShould find π in the interval : %+v", succeed, testID, ci)
+			}
+		}
+	}
+}
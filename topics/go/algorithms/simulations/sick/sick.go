@@ -6,49 +6,62 @@ package main
 import (
 	"fmt"
 	"math/rand"
+
+	"github.com/ardanlabs/gotraining/topics/go/algorithms/simulations/montecarlo"
 )
 
+// outcome is the result of testing a single person.
+type outcome struct {
+	sick      bool
+	diagnosed bool
+}
+
 // oneChanceIn returns true one in n times.
-func oneChanceIn(n int) bool {
-	return rand.Intn(n) == 1
+func oneChanceIn(rnd *rand.Rand, n int) bool {
+	return rnd.Intn(n) == 1
 }
 
 // isSick returns true if a randomly sampled person is sick.
-func isSick() bool {
+func isSick(rnd *rand.Rand) bool {
 	// The disease strikes 1/1000 of the population.
-	return oneChanceIn(1000)
+	return oneChanceIn(rnd, 1000)
 }
 
 // diagnosed returns true if a person is sick or misdiagnosed as sick.
-func diagnosed(sick bool) bool {
+func diagnosed(rnd *rand.Rand, sick bool) bool {
 	if sick {
 		return true // We're 100% correct in sick people.
 	}
 
 	// The test of a disease presents a rate of 5% (1 in 20) false positives.
 	// (false positive = healthy diagnosed as sick)
-	return oneChanceIn(20)
+	return oneChanceIn(rnd, 20)
 }
 
 // simulate run selects sampleSize random people and return the fraction of people
-// actually sick from the total number of people diagnosed as sick.
-func simulate(sampleSize int) float64 {
-	var numSick, numDiagnosed int
-
-	for i := 0; i < sampleSize; i++ {
-		sick := isSick()
-		if sick {
-			numSick++
-		}
-
-		if diagnosed(sick) {
-			numDiagnosed++
-		}
+// actually sick from the total number of people diagnosed as sick, with the
+// interval holding it at the confidence level.
+func simulate(cfg montecarlo.Config, confidence float64) (montecarlo.Interval, error) {
+	h, err := montecarlo.Run(cfg, func(rnd *rand.Rand) outcome {
+		sick := isSick(rnd)
+		return outcome{sick: sick, diagnosed: diagnosed(rnd, sick)}
+	})
+	if err != nil {
+		return montecarlo.Interval{}, err
 	}
 
-	return float64(numSick) / float64(numDiagnosed)
+	// Only the people diagnosed as sick count.
+	numSick := h.Counts[outcome{sick: true, diagnosed: true}]
+	numDiagnosed := numSick + h.Counts[outcome{sick: false, diagnosed: true}]
+
+	return montecarlo.Wilson(numSick, numDiagnosed, confidence)
 }
 
 func main() {
-	fmt.Println(simulate(1_000_000))
+	ci, err := simulate(montecarlo.Config{Trials: 1_000_000, Seed: 1}, 0.95)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("%.4f (95%% interval %.4f - %.4f)\n", ci.Estimate, ci.Low, ci.High)
 }
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/ardanlabs/gotraining/topics/go/algorithms/simulations/montecarlo"
+)
+
+const succeed = "\u2713"
+const failed = "\u2717"
+
+func TestSimulate(t *testing.T) {
+	t.Log("Given the need to simulate the precision of a medical test.")
+	{
+		t.Logf("\tTest 0:\tWhen testing many people.")
+		{
+			// Out of 1000 people, 1 is sick and about 50 of the 999
+			// healthy people are diagnosed as sick.
+			expected := 0.001 / (0.001 + 0.999*0.05)
+
+			ci, err := simulate(montecarlo.Config{Trials: 1_000_000, Seed: 1}, 0.999)
+			if err != nil || !ci.Contains(expected) {
+				t.Fatalf("\t%s\tTest 0:\tShould find %.4f in the interval : %+v %v", failed, expected, ci, err)
+			}
+			t.Logf("\t%s\tTest 0:\tShould find %.4f in the interval : %+v", succeed, expected, ci)
+		}
+	}
+}
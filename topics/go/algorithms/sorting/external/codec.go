@@ -0,0 +1,86 @@
+package external
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"strings"
+)
+
+// Codec reads and writes records of type T. The same codec is used for the
+// input, the temporary runs and the output.
+type Codec[T any] interface {
+
+	// Decode reads the next record. It returns io.EOF when there are no
+	// more records and io.ErrUnexpectedEOF if the input ends in the
+	// middle of a record.
+	Decode(r *bufio.Reader) (T, error)
+
+	// Encode writes the record.
+	Encode(w *bufio.Writer, record T) error
+
+	// Size returns the number of bytes of memory the record uses. It is
+	// counted against the memory budget of the sort.
+	Size(record T) int
+}
+
+// =============================================================================
+
+// Lines is a codec for text where every line is a record. The newline is not
+// part of the record and a final line without a newline is still a record.
+type Lines struct{}
+
+// Decode reads the next line.
+func (Lines) Decode(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	switch {
+	case err == io.EOF && line == "":
+		return "", io.EOF
+	case err != nil && err != io.EOF:
+		return "", err
+	}
+
+	return strings.TrimSuffix(line, "\n"), nil
+}
+
+// Encode writes the line followed by a newline.
+func (Lines) Encode(w *bufio.Writer, record string) error {
+	if _, err := w.WriteString(record); err != nil {
+		return err
+	}
+	return w.WriteByte('\n')
+}
+
+// Size returns the length of the line plus the size of the string header.
+func (Lines) Size(record string) int {
+	return len(record) + 16
+}
+
+// =============================================================================
+
+// Int64s is a codec for records that are 8 byte big endian integers.
+type Int64s struct{}
+
+// Decode reads the next integer.
+func (Int64s) Decode(r *bufio.Reader) (int64, error) {
+	var buf [8]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return 0, err
+	}
+
+	return int64(binary.BigEndian.Uint64(buf[:])), nil
+}
+
+// Encode writes the integer.
+func (Int64s) Encode(w *bufio.Writer, record int64) error {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(record))
+
+	_, err := w.Write(buf[:])
+	return err
+}
+
+// Size returns the 8 bytes used by the integer.
+func (Int64s) Size(record int64) int {
+	return 8
+}
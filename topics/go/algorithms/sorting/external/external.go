@@ -0,0 +1,312 @@
+// Package external implements an external merge sort for data sets that do
+// not fit in memory. Records are read from an io.Reader until half of the
+// memory budget is used up. That batch is sorted in memory, using the other
+// half as scratch space, and written to a temporary file called a run. Once the input is consumed, the runs are
+// merged into the io.Writer using a heap that always holds the smallest
+// record left in every run.
+//
+// If there are more runs than can be merged at once, groups of runs are
+// merged into larger runs first, so the number of open files stays bounded.
+package external
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+
+	heapsort "github.com/ardanlabs/gotraining/topics/go/algorithms/sorting/heap"
+	"github.com/ardanlabs/gotraining/topics/go/algorithms/sorting/sorts"
+)
+
+// Default values used when the Config leaves them at zero.
+const (
+	DefaultMemoryLimit = 64 << 20
+	DefaultFanIn       = 64
+)
+
+// Config describes how records are read, ordered and spilled.
+type Config[T any] struct {
+
+	// Codec reads and writes the records.
+	Codec Codec[T]
+
+	// Less reports whether record a must sort before record b.
+	Less func(a, b T) bool
+
+	// MemoryLimit is the number of bytes, as reported by the codec, that
+	// the sort holds in memory at once. A batch holds up to half of it,
+	// since the stable sort of a batch needs scratch space for a copy of
+	// the records. The slice holding the batch may have up to twice the
+	// capacity it needs, and every run being merged has a read buffer.
+	MemoryLimit int
+
+	// FanIn is the maximum number of runs merged at once.
+	FanIn int
+
+	// TempDir is the directory for the runs. The default directory for
+	// temporary files is used when it is empty.
+	TempDir string
+}
+
+// Sort reads every record from src and writes them to dst in sorted order.
+// Records that are equal keep the order they had in src.
+func Sort[T any](dst io.Writer, src io.Reader, cfg Config[T]) error {
+	if cfg.Codec == nil {
+		return errors.New("codec required")
+	}
+	if cfg.Less == nil {
+		return errors.New("less function required")
+	}
+	if cfg.MemoryLimit <= 0 {
+		cfg.MemoryLimit = DefaultMemoryLimit
+	}
+	if cfg.FanIn <= 0 {
+		cfg.FanIn = DefaultFanIn
+	}
+	if cfg.FanIn < 2 {
+		return errors.New("fan in must be at least 2")
+	}
+
+	dir, err := os.MkdirTemp(cfg.TempDir, "external-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	s := sorter[T]{
+		cfg: cfg,
+		dir: dir,
+	}
+	return s.sort(dst, src)
+}
+
+// =============================================================================
+
+// sorter holds the state of a single call to Sort.
+type sorter[T any] struct {
+	cfg Config[T]
+	dir string
+}
+
+// sort splits the input into runs and merges them into dst.
+func (s *sorter[T]) sort(dst io.Writer, src io.Reader) error {
+	w := bufio.NewWriter(dst)
+
+	runs, err := s.split(w, bufio.NewReader(src))
+	if err != nil {
+		return err
+	}
+
+	// Keep merging groups of runs until they can all be merged at once.
+	for len(runs) > s.cfg.FanIn {
+		var merged []string
+		for len(runs) > 0 {
+			n := s.cfg.FanIn
+			if n > len(runs) {
+				n = len(runs)
+			}
+
+			run, err := s.mergeToRun(runs[:n])
+			if err != nil {
+				return err
+			}
+			merged = append(merged, run)
+			runs = runs[n:]
+		}
+		runs = merged
+	}
+
+	if len(runs) > 0 {
+		if err := s.merge(w, runs); err != nil {
+			return err
+		}
+	}
+
+	return w.Flush()
+}
+
+// split reads the input in batches that fit in the memory budget and writes
+// every batch as a sorted run. When the whole input fits in a single batch,
+// it is written straight to w and no runs are returned.
+func (s *sorter[T]) split(w *bufio.Writer, r *bufio.Reader) ([]string, error) {
+	var runs []string
+	var batch []T
+	var size int
+
+	// Leave half of the budget for the scratch space used by the sort.
+	limit := s.cfg.MemoryLimit / 2
+
+	for {
+		record, err := s.cfg.Codec.Decode(r)
+		if err != nil {
+			if err != io.EOF {
+				return nil, err
+			}
+			break
+		}
+
+		batch = append(batch, record)
+		size += s.cfg.Codec.Size(record)
+
+		if size >= limit {
+			run, err := s.writeRun(batch)
+			if err != nil {
+				return nil, err
+			}
+			runs = append(runs, run)
+
+			// Reuse the memory of the batch, clearing the records so
+			// they can be garbage collected.
+			var zero T
+			for i := range batch {
+				batch[i] = zero
+			}
+			batch = batch[:0]
+			size = 0
+		}
+	}
+
+	// Nothing was spilled, so there is no need for temporary files.
+	if len(runs) == 0 {
+		sorts.ParallelMerge(batch, s.cfg.Less)
+		return nil, s.encode(w, batch)
+	}
+
+	if len(batch) > 0 {
+		run, err := s.writeRun(batch)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+
+	return runs, nil
+}
+
+// writeRun sorts the batch and writes it to a new run.
+func (s *sorter[T]) writeRun(batch []T) (string, error) {
+
+	// A stable sort is used so equal records within a run keep their
+	// input order. The merge keeps the order between runs.
+	sorts.ParallelMerge(batch, s.cfg.Less)
+
+	return s.createRun(func(w *bufio.Writer) error {
+		return s.encode(w, batch)
+	})
+}
+
+// mergeToRun merges the runs into a new run and removes them.
+func (s *sorter[T]) mergeToRun(runs []string) (string, error) {
+	run, err := s.createRun(func(w *bufio.Writer) error {
+		return s.merge(w, runs)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	for _, name := range runs {
+		os.Remove(name)
+	}
+
+	return run, nil
+}
+
+// createRun creates a temporary file and calls fn to write its records.
+func (s *sorter[T]) createRun(fn func(w *bufio.Writer) error) (string, error) {
+	f, err := os.CreateTemp(s.dir, "run-")
+	if err != nil {
+		return "", err
+	}
+
+	w := bufio.NewWriter(f)
+	if err := fn(w); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	return f.Name(), nil
+}
+
+// encode writes every record in the batch.
+func (s *sorter[T]) encode(w *bufio.Writer, batch []T) error {
+	for _, record := range batch {
+		if err := s.cfg.Codec.Encode(w, record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// =============================================================================
+
+// cursor is the next record of a run being merged.
+type cursor[T any] struct {
+	record T
+	run    int
+	r      *bufio.Reader
+}
+
+// merge performs a k-way merge of the runs into w. The heap holds a cursor
+// for every run that still has records, with the smallest record on top.
+func (s *sorter[T]) merge(w *bufio.Writer, runs []string) error {
+
+	// When records are equal, the one from the earlier run comes first,
+	// which keeps the sort stable.
+	pq := heapsort.NewPriorityQueue(func(a, b *cursor[T]) bool {
+		if s.cfg.Less(a.record, b.record) {
+			return true
+		}
+		if s.cfg.Less(b.record, a.record) {
+			return false
+		}
+		return a.run < b.run
+	})
+
+	for i, name := range runs {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		c := cursor[T]{run: i, r: bufio.NewReader(f)}
+		c.record, err = s.cfg.Codec.Decode(c.r)
+		switch {
+		case err == io.EOF:
+			continue
+		case err != nil:
+			return err
+		}
+		pq.Push(&c)
+	}
+
+	for pq.Len() > 0 {
+		c, _ := pq.Peek()
+		if err := s.cfg.Codec.Encode(w, c.record); err != nil {
+			return err
+		}
+
+		// Replace the record on top with the next one from the same run
+		// or drop the run once it is empty.
+		record, err := s.cfg.Codec.Decode(c.r)
+		switch {
+		case err == io.EOF:
+			pq.Pop()
+		case err != nil:
+			return err
+		default:
+			c.record = record
+			pq.Update(0, c)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,281 @@
+package external_test
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ardanlabs/gotraining/topics/go/algorithms/sorting/external"
+)
+
+const succeed = "\u2713"
+const failed = "\u2717"
+
+// TestSortLines sorts text with a tiny memory budget and fan in, which
+// forces many runs and several merge passes.
+func TestSortLines(t *testing.T) {
+	tt := []struct {
+		name        string
+		records     int
+		memoryLimit int
+		fanIn       int
+	}{
+		{"empty", 0, 64, 2},
+		{"single-batch", 10, 1 << 20, 2},
+		{"two-runs", 10, 150, 2},
+		{"many-runs", 1000, 64, 3},
+		{"single-pass", 1000, 256, 100},
+	}
+
+	t.Log("Given the need to sort lines of text larger than memory.")
+	{
+		for testID, test := range tt {
+			t.Logf("\tTest %d:\tWhen sorting %d lines for %s.", testID, test.records, test.name)
+			{
+				lines := make([]string, test.records)
+				for i := range lines {
+					lines[i] = fmt.Sprintf("line-%d", rand.Intn(test.records))
+				}
+
+				dir := t.TempDir()
+				cfg := external.Config[string]{
+					Codec:       external.Lines{},
+					Less:        func(a, b string) bool { return a < b },
+					MemoryLimit: test.memoryLimit,
+					FanIn:       test.fanIn,
+					TempDir:     dir,
+				}
+
+				var src strings.Builder
+				for _, line := range lines {
+					src.WriteString(line + "\n")
+				}
+
+				var dst bytes.Buffer
+				if err := external.Sort(&dst, strings.NewReader(src.String()), cfg); err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to sort the lines : %v", failed, testID, err)
+				}
+				t.Logf("\t%s\tTest %d:\tShould be able to sort the lines.", succeed, testID)
+
+				sort.Strings(lines)
+				var exp strings.Builder
+				for _, line := range lines {
+					exp.WriteString(line + "\n")
+				}
+
+				if dst.String() != exp.String() {
+					t.Fatalf("\t%s\tTest %d:\tShould get the lines in sorted order.", failed, testID)
+				}
+				t.Logf("\t%s\tTest %d:\tShould get the lines in sorted order.", succeed, testID)
+
+				entries, err := os.ReadDir(dir)
+				if err != nil || len(entries) != 0 {
+					t.Fatalf("\t%s\tTest %d:\tShould remove the temporary files : %d, %v", failed, testID, len(entries), err)
+				}
+				t.Logf("\t%s\tTest %d:\tShould remove the temporary files.", succeed, testID)
+			}
+		}
+	}
+}
+
+// TestSortStable checks records with equal keys keep their input order
+// across runs.
+func TestSortStable(t *testing.T) {
+	t.Log("Given the need to keep equal records in input order.")
+	{
+		t.Logf("\tTest 0:\tWhen sorting records on a key with duplicates.")
+		{
+			const records = 500
+
+			var src strings.Builder
+			for i := 0; i < records; i++ {
+				fmt.Fprintf(&src, "%d:%04d\n", rand.Intn(5), i)
+			}
+
+			// Only the key before the colon is compared.
+			cfg := external.Config[string]{
+				Codec:       external.Lines{},
+				Less:        func(a, b string) bool { return a[0] < b[0] },
+				MemoryLimit: 100,
+				FanIn:       2,
+				TempDir:     t.TempDir(),
+			}
+
+			var dst bytes.Buffer
+			if err := external.Sort(&dst, strings.NewReader(src.String()), cfg); err != nil {
+				t.Fatalf("\t%s\tTest 0:\tShould be able to sort the records : %v", failed, err)
+			}
+
+			lines := strings.Split(strings.TrimSuffix(dst.String(), "\n"), "\n")
+			if len(lines) != records {
+				t.Fatalf("\t%s\tTest 0:\tShould get every record : %d, Expected %d", failed, len(lines), records)
+			}
+
+			for i := 1; i < len(lines); i++ {
+				if lines[i][0] == lines[i-1][0] && lines[i] < lines[i-1] {
+					t.Fatalf("\t%s\tTest 0:\tShould keep equal records in order : %s before %s", failed, lines[i-1], lines[i])
+				}
+			}
+			t.Logf("\t%s\tTest 0:\tShould keep equal records in order.", succeed)
+		}
+	}
+}
+
+// TestSortInt64s sorts binary records and checks input that ends in the
+// middle of a record is reported.
+func TestSortInt64s(t *testing.T) {
+	codec := external.Int64s{}
+
+	t.Log("Given the need to sort binary records.")
+	{
+		t.Logf("\tTest 0:\tWhen sorting 8 byte integers.")
+		{
+			list := make([]int64, 2000)
+			var src bytes.Buffer
+			w := bufio.NewWriter(&src)
+			for i := range list {
+				list[i] = rand.Int63() - rand.Int63()
+				codec.Encode(w, list[i])
+			}
+			w.Flush()
+
+			cfg := external.Config[int64]{
+				Codec:       codec,
+				Less:        func(a, b int64) bool { return a < b },
+				MemoryLimit: 8 * 50,
+				FanIn:       4,
+				TempDir:     t.TempDir(),
+			}
+
+			var dst bytes.Buffer
+			if err := external.Sort(&dst, &src, cfg); err != nil {
+				t.Fatalf("\t%s\tTest 0:\tShould be able to sort the integers : %v", failed, err)
+			}
+
+			sort.Slice(list, func(i, j int) bool { return list[i] < list[j] })
+			r := bufio.NewReader(&dst)
+			for _, exp := range list {
+				got, err := codec.Decode(r)
+				if err != nil || got != exp {
+					t.Fatalf("\t%s\tTest 0:\tShould get the integers in sorted order : %d, %v, Expected %d", failed, got, err, exp)
+				}
+			}
+			if _, err := codec.Decode(r); err != io.EOF {
+				t.Fatalf("\t%s\tTest 0:\tShould not get extra integers : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 0:\tShould get the integers in sorted order.", succeed)
+		}
+
+		t.Logf("\tTest 1:\tWhen the input ends in the middle of a record.")
+		{
+			cfg := external.Config[int64]{
+				Codec:   codec,
+				Less:    func(a, b int64) bool { return a < b },
+				TempDir: t.TempDir(),
+			}
+
+			src := bytes.NewReader(make([]byte, 8*3+5))
+			err := external.Sort(io.Discard, src, cfg)
+			if !errors.Is(err, io.ErrUnexpectedEOF) {
+				t.Fatalf("\t%s\tTest 1:\tShould get an unexpected EOF : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 1:\tShould get an unexpected EOF.", succeed)
+		}
+	}
+}
+
+// TestSortMemory checks the records held in memory, along with the scratch
+// space used to sort them, stay close to the memory budget.
+func TestSortMemory(t *testing.T) {
+	const memoryLimit = 1 << 20
+	codec := external.Int64s{}
+
+	t.Log("Given the need to sort with a memory budget.")
+	{
+		t.Logf("\tTest 0:\tWhen sorting 4 times the budget of 8 byte integers.")
+		{
+			var src bytes.Buffer
+			w := bufio.NewWriter(&src)
+			for i := 0; i < 4*memoryLimit/8; i++ {
+				codec.Encode(w, rand.Int63())
+			}
+			w.Flush()
+
+			// measure collects the garbage and returns the bytes still
+			// in use.
+			measure := func() int {
+				var ms runtime.MemStats
+				runtime.GC()
+				runtime.ReadMemStats(&ms)
+				return int(ms.HeapAlloc)
+			}
+
+			// Measure the heap every so often while records are being
+			// compared, which is when a batch is being sorted.
+			var mu sync.Mutex
+			var calls, peak int
+			less := func(a, b int64) bool {
+				mu.Lock()
+				defer mu.Unlock()
+				if calls++; calls%(1<<16) == 0 {
+					if n := measure(); n > peak {
+						peak = n
+					}
+				}
+				return a < b
+			}
+
+			cfg := external.Config[int64]{
+				Codec:       codec,
+				Less:        less,
+				MemoryLimit: memoryLimit,
+				TempDir:     t.TempDir(),
+			}
+
+			base := measure()
+			if err := external.Sort(io.Discard, bytes.NewReader(src.Bytes()), cfg); err != nil {
+				t.Fatalf("\t%s\tTest 0:\tShould be able to sort the integers : %v", failed, err)
+			}
+
+			// The slice holding a batch may have more capacity than it
+			// needs, which the budget allows for.
+			if used := peak - base; used > memoryLimit*3/2 {
+				t.Fatalf("\t%s\tTest 0:\tShould use at most %d bytes : %d", failed, memoryLimit*3/2, used)
+			}
+			t.Logf("\t%s\tTest 0:\tShould use at most %d bytes : %d", succeed, memoryLimit*3/2, peak-base)
+		}
+	}
+}
+
+// BenchmarkSort sorts one million lines with different memory budgets.
+func BenchmarkSort(b *testing.B) {
+	var src strings.Builder
+	for i := 0; i < 1000000; i++ {
+		fmt.Fprintf(&src, "%d\n", rand.Int())
+	}
+	data := src.String()
+
+	for _, limit := range []int{1 << 20, 8 << 20, 256 << 20} {
+		b.Run(fmt.Sprintf("%dMB", limit>>20), func(b *testing.B) {
+			cfg := external.Config[string]{
+				Codec:       external.Lines{},
+				Less:        func(a, b string) bool { return a < b },
+				MemoryLimit: limit,
+				TempDir:     b.TempDir(),
+			}
+
+			for i := 0; i < b.N; i++ {
+				external.Sort(io.Discard, strings.NewReader(data), cfg)
+			}
+		})
+	}
+}
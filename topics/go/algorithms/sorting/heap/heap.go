@@ -6,6 +6,9 @@
 // with a linear-time scan of the unsorted region; rather, heap sort maintains
 // the unsorted region in a heap data structure to more quickly find the largest
 // element in each step.
+//
+// The heap logic is also available as a PriorityQueue which can be used to
+// build schedulers and top-K queries.
 package heap
 
 // HeapSort takes a random list of numbers and returns the sorted list.
@@ -49,43 +52,73 @@ func HeapSort(list []int) []int {
 	return list
 }
 
+// SortFunc sorts the list in place using heapsort, ordering the values
+// with the less function. The sort is not stable.
+func SortFunc[T any](list []T, less func(a, b T) bool) {
+
+	// Build a heap where the value that sorts last is at the front
+	// of the list.
+	greater := func(a, b T) bool { return less(b, a) }
+	for index := (len(list) / 2) - 1; index >= 0; index-- {
+		siftDown(list, index, 2, greater, nil)
+	}
+
+	// Move the value from the front of the list to the end and shrink
+	// the heap, then restore the heap for the values that are left.
+	for index := len(list) - 1; index >= 1; index-- {
+		list[0], list[index] = list[index], list[0]
+		siftDown(list[:index], 0, 2, greater, nil)
+	}
+}
+
 // moveLargest starts at the index positions specified in the list and attempts
 // to move the largest number it can find to that position in the list.
 func moveLargest(list []int, size int, index int) []int {
+	siftDown(list[:size], index, 2, func(a, b int) bool { return a > b }, nil)
+	return list
+}
 
-	// Calculate the index deviation so numbers in the list can be
-	// compared and swapped if needed.
-	// index 0: cmpIdx1: 1 cmpIdx2:  2   index 5: cmpIdx1: 11 cmpIdx2: 12
-	// index 1: cmpIdx1: 3 cmpIdx2:  4   index 6: cmpIdx1: 13 cmpIdx2: 14
-	// index 2: cmpIdx1: 5 cmpIdx2:  6   index 7: cmpIdx1: 15 cmpIdx2: 16
-	// index 3: cmpIdx1: 7 cmpIdx2:  8   index 8: cmpIdx1: 17 cmpIdx2: 19
-	// index 4: cmpIdx1: 9 cmpIdx2: 10   index 9: cmpIdx1: 19 cmpIdx2: 20
-	cmpIdx1, cmpIdx2 := 2*index+1, 2*index+2
+// siftDown starts at the index position specified in the list and attempts
+// to move the value that comes first based on the less function to that
+// position in the list. Each index position has arity children, so a value
+// of 2 works with a binary heap. If moved is not nil, it is called with the
+// index position of every value that is moved up. The final index position
+// of the value is returned.
+func siftDown[T any](list []T, index int, arity int, less func(a, b T) bool, moved func(index int)) int {
+	for {
 
-	// Save the specified index as the index with the current largest value.
-	largestValueIdx := index
+		// Calculate the index deviation so values in the list can be
+		// compared and swapped if needed. With an arity of 2:
+		// index 0: cmpIdx: 1 - 2   index 5: cmpIdx: 11 - 12
+		// index 1: cmpIdx: 3 - 4   index 6: cmpIdx: 13 - 14
+		// index 2: cmpIdx: 5 - 6   index 7: cmpIdx: 15 - 16
+		// index 3: cmpIdx: 7 - 8   index 8: cmpIdx: 17 - 18
+		// index 4: cmpIdx: 9 - 10  index 9: cmpIdx: 19 - 20
+		firstIdx := arity*index + 1
 
-	// Check if the value at the first deviation index is greater than
-	// the value at the current largest index. If so, save that
-	// index position.
-	if cmpIdx1 < size && list[cmpIdx1] > list[largestValueIdx] {
-		largestValueIdx = cmpIdx1
-	}
+		// Save the specified index as the index with the current first value.
+		firstValueIdx := index
 
-	// Check the second deviation index is within bounds and is greater
-	// than the value at the current largest index. If so, save that
-	// index position.
-	if cmpIdx2 < size && list[cmpIdx2] > list[largestValueIdx] {
-		largestValueIdx = cmpIdx2
-	}
+		// Check if the value at each deviation index is within bounds and
+		// comes before the value at the current first index. If so, save
+		// that index position.
+		for cmpIdx := firstIdx; cmpIdx < firstIdx+arity && cmpIdx < len(list); cmpIdx++ {
+			if less(list[cmpIdx], list[firstValueIdx]) {
+				firstValueIdx = cmpIdx
+			}
+		}
 
-	// If we found a larger value than the value at the specified index, swap
-	// those numbers and then recurse to find more numbers to swap from that
-	// point in the list.
-	if largestValueIdx != index {
-		list[index], list[largestValueIdx] = list[largestValueIdx], list[index]
-		list = moveLargest(list, size, largestValueIdx)
-	}
+		// If we didn't find a value that comes first, we are done.
+		if firstValueIdx == index {
+			return index
+		}
 
-	return list
+		// Swap those values and then continue to find more values to
+		// swap from that point in the list.
+		list[index], list[firstValueIdx] = list[firstValueIdx], list[index]
+		if moved != nil {
+			moved(index)
+		}
+		index = firstValueIdx
+	}
 }
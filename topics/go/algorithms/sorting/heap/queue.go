@@ -0,0 +1,161 @@
+package heap
+
+import (
+	"errors"
+)
+
+// PriorityQueue is a heap of values ordered by a less function. The less
+// function reports whether a must be popped before b, so a < b creates a
+// min queue and a > b creates a max queue.
+//
+// The heap is stored in a slice where the children of the value at index i
+// are found at index arity*i+1 through arity*i+arity. A binary heap has an
+// arity of 2. A higher arity makes the heap shallower, so pushes and updates
+// are faster while pops need more comparisons.
+type PriorityQueue[T any] struct {
+	list   []T
+	arity  int
+	less   func(a, b T) bool
+	onMove func(value T, index int)
+}
+
+// NewPriorityQueue returns a binary heap priority queue.
+func NewPriorityQueue[T any](less func(a, b T) bool) *PriorityQueue[T] {
+	return &PriorityQueue[T]{
+		arity: 2,
+		less:  less,
+	}
+}
+
+// NewDaryPriorityQueue returns a priority queue where every value in the
+// heap has the specified number of children.
+func NewDaryPriorityQueue[T any](arity int, less func(a, b T) bool) (*PriorityQueue[T], error) {
+	if arity < 2 {
+		return nil, errors.New("invalid arity")
+	}
+
+	pq := PriorityQueue[T]{
+		arity: arity,
+		less:  less,
+	}
+	return &pq, nil
+}
+
+// OnMove registers a function that is called every time a value is placed
+// at a new index position in the heap. This allows the caller to keep
+// track of the index needed by Update and Remove.
+func (pq *PriorityQueue[T]) OnMove(fn func(value T, index int)) {
+	pq.onMove = fn
+}
+
+// Len returns the number of values in the queue.
+func (pq *PriorityQueue[T]) Len() int {
+	return len(pq.list)
+}
+
+// Push adds a value to the queue.
+func (pq *PriorityQueue[T]) Push(value T) {
+	pq.list = append(pq.list, value)
+	pq.fix(len(pq.list) - 1)
+}
+
+// Pop removes and returns the value that comes first in the queue.
+func (pq *PriorityQueue[T]) Pop() (T, error) {
+	if len(pq.list) == 0 {
+		var zero T
+		return zero, errors.New("queue is empty")
+	}
+
+	return pq.remove(0), nil
+}
+
+// Peek returns the value that comes first in the queue without
+// removing it.
+func (pq *PriorityQueue[T]) Peek() (T, error) {
+	if len(pq.list) == 0 {
+		var zero T
+		return zero, errors.New("queue is empty")
+	}
+
+	return pq.list[0], nil
+}
+
+// Update replaces the value at the specified index position with a value
+// that has a new priority and moves it to its new place in the heap.
+func (pq *PriorityQueue[T]) Update(index int, value T) error {
+	if index < 0 || index >= len(pq.list) {
+		return errors.New("invalid index position")
+	}
+
+	pq.list[index] = value
+	pq.fix(index)
+	return nil
+}
+
+// Remove removes and returns the value at the specified index position.
+func (pq *PriorityQueue[T]) Remove(index int) (T, error) {
+	if index < 0 || index >= len(pq.list) {
+		var zero T
+		return zero, errors.New("invalid index position")
+	}
+
+	return pq.remove(index), nil
+}
+
+// remove takes the value out of the heap by swapping it with the last value
+// in the list and then fixing the place of the value that took its place.
+func (pq *PriorityQueue[T]) remove(index int) T {
+	last := len(pq.list) - 1
+	value := pq.list[index]
+
+	pq.list[index] = pq.list[last]
+	var zero T
+	pq.list[last] = zero
+	pq.list = pq.list[:last]
+
+	if index < last {
+		pq.fix(index)
+	}
+	return value
+}
+
+// fix moves the value at the specified index position up or down the heap
+// until it is in its proper place.
+func (pq *PriorityQueue[T]) fix(index int) {
+	var moved func(index int)
+	if pq.onMove != nil {
+		moved = func(index int) {
+			pq.onMove(pq.list[index], index)
+		}
+	}
+
+	// If the value did not move up, it may need to move down. The
+	// same code used by HeapSort moves it down.
+	index = pq.siftUp(index, moved)
+	index = siftDown(pq.list, index, pq.arity, pq.less, moved)
+
+	if moved != nil {
+		moved(index)
+	}
+}
+
+// siftUp moves the value at the specified index position towards the top
+// of the heap while it comes before its parent. If moved is not nil, it is
+// called with the index position of every value that is moved down. The
+// final index position of the value is returned.
+func (pq *PriorityQueue[T]) siftUp(index int, moved func(index int)) int {
+	for index > 0 {
+		parent := (index - 1) / pq.arity
+		if !pq.less(pq.list[index], pq.list[parent]) {
+			break
+		}
+
+		pq.list[index], pq.list[parent] = pq.list[parent], pq.list[index]
+		if moved != nil {
+			moved(index)
+		}
+		index = parent
+	}
+
+	return index
+}
@@ -0,0 +1,156 @@
+package heap_test
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	heapsort "github.com/ardanlabs/gotraining/topics/go/algorithms/sorting/heap"
+)
+
+// TestPriorityQueue to test our priority queue with different arities
+// and orderings.
+func TestPriorityQueue(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+	greater := func(a, b int) bool { return a > b }
+
+	for arity := 2; arity <= 5; arity++ {
+		for _, order := range []struct {
+			name string
+			less func(a, b int) bool
+		}{{"min", less}, {"max", greater}} {
+			list := generateList(1000)
+
+			pq, err := heapsort.NewDaryPriorityQueue(arity, order.less)
+			if err != nil {
+				t.Fatalf("\t%s\tShould be able to create a queue with arity %d : %v", failed, arity, err)
+			}
+			for _, v := range list {
+				pq.Push(v)
+			}
+
+			if pq.Len() != len(list) {
+				t.Fatalf("\t%s\tShould have %d values : %d", failed, len(list), pq.Len())
+			}
+
+			sort.Slice(list, func(i, j int) bool { return order.less(list[i], list[j]) })
+			for _, exp := range list {
+				if v, err := pq.Peek(); err != nil || v != exp {
+					t.Fatalf("\t%s\tShould peek the %s value : %d, %v, Expected %d", failed, order.name, v, err, exp)
+				}
+				if v, err := pq.Pop(); err != nil || v != exp {
+					t.Fatalf("\t%s\tShould pop the %s value : %d, %v, Expected %d", failed, order.name, v, err, exp)
+				}
+			}
+
+			if _, err := pq.Pop(); err == nil {
+				t.Fatalf("\t%s\tShould not be able to pop from an empty queue.", failed)
+			}
+			t.Logf("\t%s\tArity %d pops every value in %s order.", succeed, arity, order.name)
+		}
+	}
+
+	if _, err := heapsort.NewDaryPriorityQueue(1, less); err == nil {
+		t.Fatalf("\t%s\tShould not be able to create a queue with arity 1.", failed)
+	}
+	t.Logf("\t%s\tShould not be able to create a queue with arity 1.", succeed)
+}
+
+// task is a value in a scheduler that keeps track of its index position.
+type task struct {
+	name     string
+	priority int
+	index    int
+}
+
+// TestPriorityQueueUpdate to test changing the priority of values.
+func TestPriorityQueueUpdate(t *testing.T) {
+	pq := heapsort.NewPriorityQueue(func(a, b *task) bool {
+		return a.priority > b.priority
+	})
+	pq.OnMove(func(tk *task, index int) {
+		tk.index = index
+	})
+
+	tasks := make([]*task, 100)
+	for i := range tasks {
+		tasks[i] = &task{name: string(rune('a' + i%26)), priority: rand.Intn(1000)}
+		pq.Push(tasks[i])
+	}
+
+	// Change the priority of every task and remove a few tasks.
+	for _, tk := range tasks {
+		tk.priority = rand.Intn(1000)
+		if err := pq.Update(tk.index, tk); err != nil {
+			t.Fatalf("\t%s\tShould be able to update a task : %v", failed, err)
+		}
+	}
+	for _, tk := range tasks[:10] {
+		removed, err := pq.Remove(tk.index)
+		if err != nil || removed != tk {
+			t.Fatalf("\t%s\tShould be able to remove a task : %v", failed, err)
+		}
+	}
+	t.Logf("\t%s\tShould be able to update and remove tasks.", succeed)
+
+	remaining := append([]*task(nil), tasks[10:]...)
+	sort.Slice(remaining, func(i, j int) bool { return remaining[i].priority > remaining[j].priority })
+	for _, exp := range remaining {
+		tk, err := pq.Pop()
+		if err != nil || tk.priority != exp.priority {
+			t.Fatalf("\t%s\tShould pop tasks by priority : %d, %v, Expected %d", failed, tk.priority, err, exp.priority)
+		}
+	}
+	t.Logf("\t%s\tShould pop tasks by priority.", succeed)
+}
+
+// TestTopK to test using a min queue to keep the largest values.
+func TestTopK(t *testing.T) {
+	const k = 10
+	list := generateList(10000)
+
+	// Keep the k largest values. The smallest of them is at the top of
+	// the queue so it can be replaced by a larger value.
+	pq := heapsort.NewPriorityQueue(func(a, b int) bool { return a < b })
+	for _, v := range list {
+		switch {
+		case pq.Len() < k:
+			pq.Push(v)
+		default:
+			if min, _ := pq.Peek(); v > min {
+				pq.Update(0, v)
+			}
+		}
+	}
+
+	sort.Sort(sort.Reverse(sort.IntSlice(list)))
+	for i := k - 1; i >= 0; i-- {
+		v, _ := pq.Pop()
+		if v != list[i] {
+			t.Fatalf("\t%s\tShould find the top %d values : %d, Expected %d", failed, k, v, list[i])
+		}
+	}
+	t.Logf("\t%s\tShould find the top %d values.", succeed, k)
+}
+
+// BenchmarkPriorityQueue a simple benchmark for pushing and popping
+// values with different arities.
+func BenchmarkPriorityQueue(b *testing.B) {
+	list := generateList(1000)
+	less := func(a, b int) bool { return a < b }
+
+	for _, arity := range []int{2, 4, 8} {
+		b.Run(fmt.Sprintf("arity%d", arity), func(b *testing.B) {
+			pq, _ := heapsort.NewDaryPriorityQueue(arity, less)
+			for i := 0; i < b.N; i++ {
+				for _, v := range list {
+					pq.Push(v)
+				}
+				for pq.Len() > 0 {
+					pq.Pop()
+				}
+			}
+		})
+	}
+}
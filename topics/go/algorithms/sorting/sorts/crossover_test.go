@@ -0,0 +1,112 @@
+package sorts_test
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/ardanlabs/gotraining/topics/go/algorithms/sorting/sorts"
+)
+
+// TestLarge checks the O(n log n) algorithms on inputs large enough to use
+// the goroutines in ParallelMerge. TestIntroFallback checks the heap sort
+// fallback in Intro.
+func TestLarge(t *testing.T) {
+	t.Log("Given the need to sort large inputs.")
+	{
+		const size = 100000
+
+		algos := []sorts.Algorithm[item]{
+			{Name: "intro", Stable: false, Sort: sorts.Intro[item]},
+			{Name: "parallelmerge", Stable: true, Sort: func(s []item, less func(a, b item) bool) {
+				sorts.ParallelMergeThreshold(s, less, 1000)
+			}},
+		}
+
+		for testID, algo := range algos {
+			t.Logf("\tTest %d:\tWhen sorting %d values with %s.", testID, size, algo.Name)
+			{
+				for _, in := range inputs {
+					keys := in.generate(size)
+					list := make([]item, size)
+					for i, key := range keys {
+						list[i] = item{key: key, pos: i}
+					}
+
+					algo.Sort(list, func(a, b item) bool { return a.key < b.key })
+
+					if !sort.SliceIsSorted(list, func(i, j int) bool { return list[i].key < list[j].key }) {
+						t.Fatalf("\t%s\tTest %d:\tShould sort %s values.", failed, testID, in.name)
+					}
+
+					if algo.Stable {
+						for i := 1; i < len(list); i++ {
+							if list[i].key == list[i-1].key && list[i].pos < list[i-1].pos {
+								t.Fatalf("\t%s\tTest %d:\tShould keep equal %s values in order.", failed, testID, in.name)
+							}
+						}
+					}
+					t.Logf("\t%s\tTest %d:\tShould sort %s values.", succeed, testID, in.name)
+				}
+			}
+		}
+	}
+}
+
+// BenchmarkCrossover compares quick, heap and intro sort with the standard
+// library as the input grows, showing where the median-of-three pivot and
+// the heap sort fallback start to pay off.
+func BenchmarkCrossover(b *testing.B) {
+	algos := []sorts.Algorithm[int]{
+		{Name: "quick", Sort: sorts.Quick[int]},
+		{Name: "heap", Sort: sorts.Heap[int]},
+		{Name: "intro", Sort: sorts.Intro[int]},
+		{Name: "stdlib", Sort: func(s []int, less func(a, b int) bool) {
+			sort.Slice(s, func(i, j int) bool { return less(s[i], s[j]) })
+		}},
+	}
+
+	for _, in := range inputs[:3] {
+		for _, size := range []int{16, 256, 4096, 65536} {
+			keys := in.generate(size)
+			list := make([]int, size)
+
+			for _, algo := range algos {
+
+				// Quick sort is quadratic on sorted input, so skip the
+				// sizes that would take too long.
+				if algo.Name == "quick" && in.name != "random" && size > 4096 {
+					continue
+				}
+
+				b.Run(fmt.Sprintf("%s/%d/%s", in.name, size, algo.Name), func(b *testing.B) {
+					for i := 0; i < b.N; i++ {
+						copy(list, keys)
+						algo.Sort(list, func(a, b int) bool { return a < b })
+					}
+					snum = list
+				})
+			}
+		}
+	}
+}
+
+// BenchmarkParallelThreshold runs the parallel merge sort with different
+// thresholds to find where starting goroutines beats sorting in the
+// current one. A threshold as large as the input never starts one.
+func BenchmarkParallelThreshold(b *testing.B) {
+	for _, size := range []int{1 << 10, 1 << 14, 1 << 18} {
+		keys := inputs[2].generate(size)
+		list := make([]int, size)
+
+		for _, threshold := range []int{256, 1024, 4096, 16384, size} {
+			b.Run(fmt.Sprintf("%d/%d", size, threshold), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					copy(list, keys)
+					sorts.ParallelMergeThreshold(list, func(a, b int) bool { return a < b }, threshold)
+				}
+				snum = list
+			})
+		}
+	}
+}
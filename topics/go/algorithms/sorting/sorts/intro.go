@@ -0,0 +1,78 @@
+package sorts
+
+import (
+	"math/bits"
+)
+
+// insertionThreshold is the size of a partition at or below which
+// Intro switches to insertion sort.
+const insertionThreshold = 12
+
+// onFallback is called when Intro switches to heap sort, so the tests can
+// check the fallback runs.
+var onFallback func()
+
+// Intro sorts the slice using introsort. It starts as a quick sort using a
+// median-of-three pivot, switches to heap sort when the recursion gets too
+// deep and finishes small partitions with insertion sort. This keeps the
+// speed of quick sort on average without its quadratic worst case.
+// - Time Complexity O(n log n)
+// - Auxiliary Space: O(log n)
+// - Not stable
+func Intro[T any](s []T, less func(a, b T) bool) {
+
+	// Quick sort is given 2*log2(n) levels of recursion before heap
+	// sort takes over.
+	depth := 2 * bits.Len(uint(len(s)))
+	introSort(s, less, depth)
+}
+
+// introSort sorts the slice with the remaining recursion depth.
+func introSort[T any](s []T, less func(a, b T) bool, depth int) {
+	for len(s) > insertionThreshold {
+
+		// The partitions are not getting smaller fast enough, so
+		// use heap sort which is O(n log n) for any input.
+		if depth == 0 {
+			if onFallback != nil {
+				onFallback()
+			}
+			Heap(s, less)
+			return
+		}
+		depth--
+
+		// Use the median of the first, middle and last values as the
+		// pivot. It is moved to the end where partition expects it.
+		medianOfThree(s, 0, len(s)/2, len(s)-1, less)
+		s[len(s)/2], s[len(s)-1] = s[len(s)-1], s[len(s)/2]
+		pivotIdx := partition(s, less)
+
+		// Recurse into the smaller partition and loop on the larger one,
+		// so the stack never grows beyond O(log n).
+		left, right := s[:pivotIdx], s[pivotIdx+1:]
+		if len(left) < len(right) {
+			introSort(left, less, depth)
+			s = right
+			continue
+		}
+		introSort(right, less, depth)
+		s = left
+	}
+
+	Insertion(s, less)
+}
+
+// medianOfThree orders the values at the three index positions so the
+// median of them ends up at index b.
+func medianOfThree[T any](s []T, a, b, c int, less func(a, b T) bool) {
+	if less(s[b], s[a]) {
+		s[a], s[b] = s[b], s[a]
+	}
+	if less(s[c], s[b]) {
+		s[b], s[c] = s[c], s[b]
+		if less(s[b], s[a]) {
+			s[a], s[b] = s[b], s[a]
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package sorts
+
+import (
+	"sort"
+	"testing"
+)
+
+const succeed = "\u2713"
+const failed = "\u2717"
+
+// killer returns a list of keys that makes Intro pick a bad pivot at every
+// level. It uses the adversary from "A Killer Adversary for Quicksort" by
+// McIlroy: every key starts out undecided and a key is only given a value
+// when a comparison needs it, always making the pivot candidate as small
+// as possible.
+func killer(size int) []int {
+	gas := size
+	keys := make([]int, size)
+	for i := range keys {
+		keys[i] = gas
+	}
+
+	var solid, candidate int
+	less := func(a, b int) bool {
+		if keys[a] == gas && keys[b] == gas {
+			if a == candidate {
+				keys[a] = solid
+			} else {
+				keys[b] = solid
+			}
+			solid++
+		}
+
+		switch {
+		case keys[a] == gas:
+			candidate = a
+		case keys[b] == gas:
+			candidate = b
+		}
+
+		return keys[a] < keys[b]
+	}
+
+	// Sort the index positions, so the keys end up holding the values
+	// that were decided for every position.
+	list := make([]int, size)
+	for i := range list {
+		list[i] = i
+	}
+	Intro(list, less)
+
+	return keys
+}
+
+func TestIntroFallback(t *testing.T) {
+	const size = 10000
+
+	t.Log("Given the need to avoid the quadratic worst case of quick sort.")
+	{
+		defer func() { onFallback = nil }()
+
+		var fallbacks int
+		onFallback = func() {
+			fallbacks++
+		}
+
+		for testID, in := range []struct {
+			name      string
+			keys      []int
+			fallsBack bool
+		}{
+			{"sorted", sortedKeys(size), false},
+			{"killer", killer(size), true},
+		} {
+			t.Logf("\tTest %d:\tWhen sorting %d %s values.", testID, size, in.name)
+			{
+				fallbacks = 0
+				list := append([]int(nil), in.keys...)
+				Intro(list, func(a, b int) bool { return a < b })
+
+				if !sort.IntsAreSorted(list) {
+					t.Fatalf("\t%s\tTest %d:\tShould sort the values.", failed, testID)
+				}
+				t.Logf("\t%s\tTest %d:\tShould sort the values.", succeed, testID)
+
+				should := "Should fall back to heap sort"
+				if !in.fallsBack {
+					should = "Should not fall back to heap sort"
+				}
+				if got := fallbacks > 0; got != in.fallsBack {
+					t.Fatalf("\t%s\tTest %d:\t%s : %d times", failed, testID, should, fallbacks)
+				}
+				t.Logf("\t%s\tTest %d:\t%s.", succeed, testID, should)
+			}
+		}
+	}
+}
+
+// sortedKeys returns the keys 0 to size-1 in order.
+func sortedKeys(size int) []int {
+	keys := make([]int, size)
+	for i := range keys {
+		keys[i] = i
+	}
+	return keys
+}
@@ -0,0 +1,95 @@
+package sorts
+
+import (
+	"sync"
+)
+
+// DefaultParallelThreshold is the size of a slice at or below which
+// ParallelMerge sorts the slice in the current goroutine.
+const DefaultParallelThreshold = 4096
+
+// ParallelMerge sorts the slice using a merge sort that sorts both halves
+// of the slice in separate goroutines while the halves are larger than
+// DefaultParallelThreshold.
+// - Time Complexity O(n log n)
+// - Auxiliary Space: O(n)
+// - Stable
+func ParallelMerge[T any](s []T, less func(a, b T) bool) {
+	ParallelMergeThreshold(s, less, DefaultParallelThreshold)
+}
+
+// ParallelMergeThreshold sorts the slice like ParallelMerge using the
+// specified threshold. Unlike the concurrent bubble sort, which has to
+// sort the whole slice again after every goroutine is done, the sorted
+// halves only need to be merged.
+func ParallelMergeThreshold[T any](s []T, less func(a, b T) bool, threshold int) {
+	if threshold < insertionThreshold {
+		threshold = insertionThreshold
+	}
+
+	// A single buffer is shared by every merge. Each goroutine works on
+	// a part of the buffer that matches its part of the slice.
+	buf := make([]T, len(s))
+	mergeSort(s, buf, less, threshold)
+}
+
+// mergeSort sorts the slice using buf, which has the same length, as
+// the destination of the merge.
+func mergeSort[T any](s, buf []T, less func(a, b T) bool, threshold int) {
+	if len(s) <= insertionThreshold {
+		Insertion(s, less)
+		return
+	}
+
+	mid := len(s) / 2
+
+	switch {
+	case len(s) > threshold:
+
+		// Sort the left half in a new goroutine while this goroutine
+		// sorts the right half.
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			mergeSort(s[:mid], buf[:mid], less, threshold)
+		}()
+		mergeSort(s[mid:], buf[mid:], less, threshold)
+		wg.Wait()
+
+	default:
+		mergeSort(s[:mid], buf[:mid], less, threshold)
+		mergeSort(s[mid:], buf[mid:], less, threshold)
+	}
+
+	// If the halves are already in order there is nothing to merge.
+	if !less(s[mid], s[mid-1]) {
+		return
+	}
+
+	merge(s, buf, mid, less)
+}
+
+// merge combines the two sorted halves of the slice split at mid. Values
+// from the left half are taken first when they are equal, which keeps the
+// sort stable.
+func merge[T any](s, buf []T, mid int, less func(a, b T) bool) {
+	leftIdx, rightIdx, idx := 0, mid, 0
+
+	for leftIdx < mid && rightIdx < len(s) {
+		if less(s[rightIdx], s[leftIdx]) {
+			buf[idx] = s[rightIdx]
+			rightIdx++
+		} else {
+			buf[idx] = s[leftIdx]
+			leftIdx++
+		}
+		idx++
+	}
+
+	// Copy what is left over from either half.
+	idx += copy(buf[idx:], s[leftIdx:mid])
+	copy(buf[idx:], s[rightIdx:])
+
+	copy(s, buf)
+}
@@ -0,0 +1,146 @@
+// Package sorts provides the sorting algorithms found under the sorting
+// folder behind a common generic signature. Every algorithm sorts the slice
+// in place, ordering the values with a less function that reports whether
+// a must sort before b.
+package sorts
+
+import (
+	heapsort "github.com/ardanlabs/gotraining/topics/go/algorithms/sorting/heap"
+)
+
+// Func is the common signature shared by every sorting algorithm.
+type Func[T any] func(s []T, less func(a, b T) bool)
+
+// Algorithm describes a sorting algorithm.
+type Algorithm[T any] struct {
+	Name string
+
+	// Stable reports whether values that are equal keep their
+	// original order.
+	Stable bool
+
+	Sort Func[T]
+}
+
+// All returns every sorting algorithm in the package.
+func All[T any]() []Algorithm[T] {
+	return []Algorithm[T]{
+		{Name: "bubble", Stable: true, Sort: Bubble[T]},
+		{Name: "insertion", Stable: true, Sort: Insertion[T]},
+		{Name: "selection", Stable: false, Sort: Selection[T]},
+		{Name: "quick", Stable: false, Sort: Quick[T]},
+		{Name: "heap", Stable: false, Sort: Heap[T]},
+		{Name: "intro", Stable: false, Sort: Intro[T]},
+		{Name: "parallelmerge", Stable: true, Sort: ParallelMerge[T]},
+	}
+}
+
+// Bubble sorts the slice by repeatedly sweeping through it and swapping
+// adjacent values that are out of order.
+// - Time Complexity O(n^2)
+// - Auxiliary Space: O(1)
+// - Stable
+func Bubble[T any](s []T, less func(a, b T) bool) {
+	for pass := 0; pass < len(s); pass++ {
+
+		// After every sweep the largest value left is in its final
+		// position, so the next sweep can stop one value earlier.
+		var swap bool
+		for idx := 1; idx < len(s)-pass; idx++ {
+			if less(s[idx], s[idx-1]) {
+				s[idx], s[idx-1] = s[idx-1], s[idx]
+				swap = true
+			}
+		}
+
+		// If nothing was swapped, the slice is sorted.
+		if !swap {
+			return
+		}
+	}
+}
+
+// Insertion sorts the slice by moving every value to the left until the
+// value before it does not sort after it.
+// - Time Complexity O(n^2)
+// - Auxiliary Space: O(1)
+// - Stable
+func Insertion[T any](s []T, less func(a, b T) bool) {
+	for leftIdx := 1; leftIdx < len(s); leftIdx++ {
+		checkValue := s[leftIdx]
+		rightIdx := leftIdx - 1
+
+		// Look to check the value with the previous one. If the previous
+		// value sorts after it, it will be shifted until the value gets
+		// the correct position.
+		for rightIdx >= 0 && less(checkValue, s[rightIdx]) {
+			s[rightIdx+1] = s[rightIdx]
+			rightIdx--
+		}
+
+		s[rightIdx+1] = checkValue
+	}
+}
+
+// Selection sorts the slice by finding the smallest value left and
+// swapping it into the next position.
+// - Time Complexity O(n^2)
+// - Auxiliary Space: O(1)
+// - Not stable
+func Selection[T any](s []T, less func(a, b T) bool) {
+	for leftIdx := range s {
+		index := leftIdx
+
+		// Look for the smallest value in the slice starting from leftIdx.
+		for smallestIdx := leftIdx + 1; smallestIdx < len(s); smallestIdx++ {
+			if less(s[smallestIdx], s[index]) {
+				index = smallestIdx
+			}
+		}
+
+		// Swap the value from the leftIdx with the smallest value found.
+		s[leftIdx], s[index] = s[index], s[leftIdx]
+	}
+}
+
+// Quick sorts the slice by partitioning it around the last value and then
+// sorting both partitions recursively.
+// - Time Complexity O(n log n), O(n^2) for sorted input
+// - Auxiliary Space: O(log n), O(n) for sorted input
+// - Not stable
+func Quick[T any](s []T, less func(a, b T) bool) {
+	if len(s) < 2 {
+		return
+	}
+
+	pivotIdx := partition(s, less)
+	Quick(s[:pivotIdx], less)
+	Quick(s[pivotIdx+1:], less)
+}
+
+// partition moves every value that sorts before the last value of the
+// slice to the front and returns the final index of the last value.
+func partition[T any](s []T, less func(a, b T) bool) int {
+	rightIdx := len(s) - 1
+	pivot := s[rightIdx]
+
+	leftIdx := 0
+	for smallest := 0; smallest < rightIdx; smallest++ {
+		if less(s[smallest], pivot) {
+			s[smallest], s[leftIdx] = s[leftIdx], s[smallest]
+			leftIdx++
+		}
+	}
+
+	s[leftIdx], s[rightIdx] = s[rightIdx], s[leftIdx]
+	return leftIdx
+}
+
+// Heap sorts the slice using the heapsort implementation from the
+// heap package.
+// - Time Complexity O(n log n)
+// - Auxiliary Space: O(1)
+// - Not stable
+func Heap[T any](s []T, less func(a, b T) bool) {
+	heapsort.SortFunc(s, less)
+}
@@ -0,0 +1,117 @@
+package sorts_test
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/ardanlabs/gotraining/topics/go/algorithms/sorting/sorts"
+)
+
+const succeed = "\u2713"
+const failed = "\u2717"
+
+var snum []int
+
+// item is a value with a key to sort on and its original position, so
+// the stability of an algorithm can be checked.
+type item struct {
+	key int
+	pos int
+}
+
+// input generates a list of keys of the specified size.
+type input struct {
+	name     string
+	generate func(size int) []int
+}
+
+// inputs are the shapes of data every algorithm is checked against.
+var inputs = []input{
+	{"sorted", func(size int) []int {
+		list := make([]int, size)
+		for i := range list {
+			list[i] = i
+		}
+		return list
+	}},
+	{"reversed", func(size int) []int {
+		list := make([]int, size)
+		for i := range list {
+			list[i] = size - i
+		}
+		return list
+	}},
+	{"random", func(size int) []int {
+		list := make([]int, size)
+		for i := range list {
+			list[i] = rand.Intn(size)
+		}
+		return list
+	}},
+	{"duplicates", func(size int) []int {
+		list := make([]int, size)
+		for i := range list {
+			list[i] = rand.Intn(4)
+		}
+		return list
+	}},
+}
+
+// TestConformance runs every algorithm over every input shape and checks
+// the result is sorted, and stable when the algorithm claims it is.
+func TestConformance(t *testing.T) {
+	t.Log("Given the need to test every sorting algorithm.")
+	{
+		for testID, algo := range sorts.All[item]() {
+			t.Logf("\tTest %d:\tWhen sorting with %s.", testID, algo.Name)
+			{
+				for _, in := range inputs {
+					for _, size := range []int{0, 1, 2, 3, 17, 100, 1000} {
+						keys := in.generate(size)
+						list := make([]item, size)
+						for i, key := range keys {
+							list[i] = item{key: key, pos: i}
+						}
+
+						algo.Sort(list, func(a, b item) bool { return a.key < b.key })
+
+						if !sort.SliceIsSorted(list, func(i, j int) bool { return list[i].key < list[j].key }) {
+							t.Fatalf("\t%s\tTest %d:\tShould sort %d %s values.", failed, testID, size, in.name)
+						}
+
+						if algo.Stable {
+							for i := 1; i < len(list); i++ {
+								if list[i].key == list[i-1].key && list[i].pos < list[i-1].pos {
+									t.Fatalf("\t%s\tTest %d:\tShould keep equal %s values in order.", failed, testID, in.name)
+								}
+							}
+						}
+					}
+					t.Logf("\t%s\tTest %d:\tShould sort %s values.", succeed, testID, in.name)
+				}
+			}
+		}
+	}
+}
+
+// BenchmarkSorts runs every algorithm over every input shape and size.
+func BenchmarkSorts(b *testing.B) {
+	for _, algo := range sorts.All[int]() {
+		for _, in := range inputs {
+			for _, size := range []int{100, 1000} {
+				keys := in.generate(size)
+				list := make([]int, size)
+
+				b.Run(fmt.Sprintf("%s/%s/%d", algo.Name, in.name, size), func(b *testing.B) {
+					for i := 0; i < b.N; i++ {
+						copy(list, keys)
+						algo.Sort(list, func(a, b int) bool { return a < b })
+					}
+					snum = list
+				})
+			}
+		}
+	}
+}
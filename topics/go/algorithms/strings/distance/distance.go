@@ -0,0 +1,100 @@
+// Package distance provides algorithms that measure how alike two strings
+// are. Both work on runes, so a character encoded with several bytes counts
+// as a single edit.
+package distance
+
+// Levenshtein returns the minimum number of single rune insertions,
+// deletions and substitutions needed to change a into b. Only the previous
+// row of the table of distances between every pair of prefixes is kept.
+// - Time Complexity O(n * m)
+// - Auxiliary Space: O(min(n, m))
+func Levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	// Keep the row for the shorter string.
+	if len(ra) < len(rb) {
+		ra, rb = rb, ra
+	}
+
+	// row[j] is the distance between the prefix of ra processed so far
+	// and rb[:j]. Changing an empty prefix to rb[:j] takes j insertions.
+	row := make([]int, len(rb)+1)
+	for j := range row {
+		row[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+
+		// diag holds the distance between ra[:i-1] and rb[:j-1].
+		diag := row[0]
+		row[0] = i
+
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			best := diag + cost // Substitute, or keep a match.
+			if row[j]+1 < best {
+				best = row[j] + 1 // Delete from ra.
+			}
+			if row[j-1]+1 < best {
+				best = row[j-1] + 1 // Insert into ra.
+			}
+
+			diag, row[j] = row[j], best
+		}
+	}
+
+	return row[len(rb)]
+}
+
+// LCS returns the longest common subsequence of a and b, which is the
+// longest string whose runes are found in both a and b in the same order,
+// but not necessarily next to each other. When there are several of the same
+// length, only one of them is returned.
+// - Time Complexity O(n * m)
+// - Auxiliary Space: O(n * m)
+func LCS(a, b string) string {
+	ra, rb := []rune(a), []rune(b)
+
+	// length[i][j] is the length of the longest common subsequence of
+	// ra[i:] and rb[j:]. Working from the end allows walking the table
+	// forwards to build the result.
+	length := make([][]int, len(ra)+1)
+	for i := range length {
+		length[i] = make([]int, len(rb)+1)
+	}
+
+	for i := len(ra) - 1; i >= 0; i-- {
+		for j := len(rb) - 1; j >= 0; j-- {
+			switch {
+			case ra[i] == rb[j]:
+				length[i][j] = length[i+1][j+1] + 1
+			case length[i+1][j] >= length[i][j+1]:
+				length[i][j] = length[i+1][j]
+			default:
+				length[i][j] = length[i][j+1]
+			}
+		}
+	}
+
+	// Follow the table, taking a rune whenever both strings share it
+	// and it is part of a longest subsequence.
+	lcs := make([]rune, 0, length[0][0])
+	for i, j := 0, 0; i < len(ra) && j < len(rb); {
+		switch {
+		case ra[i] == rb[j] && length[i][j] == length[i+1][j+1]+1:
+			lcs = append(lcs, ra[i])
+			i++
+			j++
+		case length[i+1][j] >= length[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+
+	return string(lcs)
+}
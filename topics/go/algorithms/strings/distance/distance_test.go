@@ -0,0 +1,142 @@
+package distance_test
+
+import (
+	"testing"
+	"unicode/utf8"
+
+	"github.com/ardanlabs/gotraining/topics/go/algorithms/strings/distance"
+)
+
+const succeed = "\u2713"
+const failed = "\u2717"
+
+func TestLevenshtein(t *testing.T) {
+	tt := []struct {
+		name     string
+		a        string
+		b        string
+		expected int
+	}{
+		{"empty", "", "", 0},
+		{"insert", "", "go", 2},
+		{"delete", "go", "", 2},
+		{"same", "gopher", "gopher", 0},
+		{"kitten", "kitten", "sitting", 3},
+		{"flaw", "flaw", "lawn", 2},
+		{"unicode", "Noël", "Noel", 1},
+		{"chinese", "汉字", "字", 1},
+	}
+
+	t.Log("Given the need to find the edit distance between strings.")
+	{
+		for testID, test := range tt {
+			tf := func(t *testing.T) {
+				t.Logf("\tTest %d:\tWhen comparing %q and %q.", testID, test.a, test.b)
+				{
+					got := distance.Levenshtein(test.a, test.b)
+					if got != test.expected {
+						t.Logf("\t%s\tTest %d:\tShould get back the distance.", failed, testID)
+						t.Fatalf("\t\tTest %d:\tGot %d, Expected %d", testID, got, test.expected)
+					}
+					t.Logf("\t%s\tTest %d:\tShould get back the distance.", succeed, testID)
+				}
+			}
+			t.Run(test.name, tf)
+		}
+	}
+}
+
+func TestLCS(t *testing.T) {
+	tt := []struct {
+		name     string
+		a        string
+		b        string
+		expected string
+	}{
+		{"empty", "", "go", ""},
+		{"none", "abc", "xyz", ""},
+		{"same", "gopher", "gopher", "gopher"},
+		{"classic", "ABCBDAB", "BDCABA", "BDAB"},
+		{"gaps", "AGGTAB", "GXTXAYB", "GTAB"},
+		{"unicode", "Noël Café", "Nël Cfé", "Nël Cfé"},
+	}
+
+	t.Log("Given the need to find the longest common subsequence of strings.")
+	{
+		for testID, test := range tt {
+			tf := func(t *testing.T) {
+				t.Logf("\tTest %d:\tWhen comparing %q and %q.", testID, test.a, test.b)
+				{
+					got := distance.LCS(test.a, test.b)
+					if got != test.expected {
+						t.Logf("\t%s\tTest %d:\tShould get back the subsequence.", failed, testID)
+						t.Fatalf("\t\tTest %d:\tGot %q, Expected %q", testID, got, test.expected)
+					}
+					t.Logf("\t%s\tTest %d:\tShould get back the subsequence.", succeed, testID)
+				}
+			}
+			t.Run(test.name, tf)
+		}
+	}
+}
+
+// isSubsequence reports whether the runes of sub are found in s in order.
+func isSubsequence(sub, s string) bool {
+	rs := []rune(s)
+	for _, r := range sub {
+		for len(rs) > 0 && rs[0] != r {
+			rs = rs[1:]
+		}
+		if len(rs) == 0 {
+			return false
+		}
+		rs = rs[1:]
+	}
+	return true
+}
+
+func FuzzDistance(f *testing.F) {
+	f.Add("kitten", "sitting")
+	f.Add("ABCBDAB", "BDCABA")
+	f.Add("Noël", "Leon")
+
+	f.Fuzz(func(t *testing.T, a, b string) {
+		if !utf8.ValidString(a) || !utf8.ValidString(b) {
+			t.Skip()
+		}
+		na, nb := utf8.RuneCountInString(a), utf8.RuneCountInString(b)
+
+		d := distance.Levenshtein(a, b)
+		if d != distance.Levenshtein(b, a) {
+			t.Fatalf("Levenshtein(%q, %q) is not symmetric", a, b)
+		}
+		if (d == 0) != (a == b) {
+			t.Fatalf("Levenshtein(%q, %q) = %d", a, b, d)
+		}
+
+		// The distance is at least the difference in length and at
+		// most the length of the longer string.
+		lo, hi := na-nb, na
+		if nb > na {
+			lo, hi = nb-na, nb
+		}
+		if d < lo || d > hi {
+			t.Fatalf("Levenshtein(%q, %q) = %d, Expected between %d and %d", a, b, d, lo, hi)
+		}
+
+		lcs := distance.LCS(a, b)
+		if !isSubsequence(lcs, a) || !isSubsequence(lcs, b) {
+			t.Fatalf("LCS(%q, %q) = %q, is not a subsequence of both", a, b, lcs)
+		}
+		n := utf8.RuneCountInString(lcs)
+		if n != utf8.RuneCountInString(distance.LCS(b, a)) {
+			t.Fatalf("LCS(%q, %q) = %q, has a different length the other way", a, b, lcs)
+		}
+
+		// Deleting everything except the subsequence and inserting the
+		// rest is always an option, so it bounds the edit distance.
+		if d > na+nb-2*n {
+			t.Fatalf("Levenshtein(%q, %q) = %d, more than the %d edits around the LCS", a, b, d, na+nb-2*n)
+		}
+	})
+}
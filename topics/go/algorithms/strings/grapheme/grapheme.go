@@ -0,0 +1,193 @@
+// Package grapheme splits strings into grapheme clusters, which are what a
+// reader sees as a single character. A cluster can be made of several
+// runes, like an "e" followed by a combining accent, a flag made of two
+// regional indicators or an emoji family joined with zero width joiners.
+//
+// The rules follow the extended grapheme cluster boundaries from Unicode
+// Standard Annex #29, using the unicode package tables to classify runes.
+// Since the standard library does not have the grapheme break property,
+// some rare cases like prepended concatenation marks are not handled.
+//
+// https://unicode.org/reports/tr29/#Grapheme_Cluster_Boundaries
+package grapheme
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// class is the grapheme break property of a rune.
+type class int
+
+const (
+	other class = iota
+	cr
+	lf
+	control
+	extend
+	zwj
+	spacingMark
+	regionalIndicator
+	pictographic
+	hangulL
+	hangulV
+	hangulT
+	hangulLV
+	hangulLVT
+)
+
+// classify returns the grapheme break property of the rune.
+func classify(r rune) class {
+	switch {
+	case r == '\r':
+		return cr
+	case r == '\n':
+		return lf
+	case r == 0x200D:
+		return zwj
+
+	// Zero width non-joiner, emoji skin tone modifiers and tags extend
+	// the cluster before them even though they are not marks.
+	case r == 0x200C, r >= 0x1F3FB && r <= 0x1F3FF, r >= 0xE0020 && r <= 0xE007F:
+		return extend
+
+	case unicode.In(r, unicode.Mn, unicode.Me):
+		return extend
+	case unicode.Is(unicode.Mc, r):
+		return spacingMark
+	case unicode.In(r, unicode.Cc, unicode.Cf, unicode.Zl, unicode.Zp):
+		return control
+	case r >= 0x1F1E6 && r <= 0x1F1FF:
+		return regionalIndicator
+
+	// Hangul syllables are built from leading consonants, vowels and
+	// trailing consonants, or precomposed into LV and LVT syllables.
+	case r >= 0x1100 && r <= 0x115F, r >= 0xA960 && r <= 0xA97C:
+		return hangulL
+	case r >= 0x1160 && r <= 0x11A7, r >= 0xD7B0 && r <= 0xD7C6:
+		return hangulV
+	case r >= 0x11A8 && r <= 0x11FF, r >= 0xD7CB && r <= 0xD7FB:
+		return hangulT
+	case r >= 0xAC00 && r <= 0xD7A3:
+		if (r-0xAC00)%28 == 0 {
+			return hangulLV
+		}
+		return hangulLVT
+
+	// Most emoji are found in these blocks or are other symbols.
+	case r >= 0x1F000 && r <= 0x1FAFF, r >= 0x2600 && r <= 0x27BF, unicode.Is(unicode.So, r):
+		return pictographic
+	}
+
+	return other
+}
+
+// Next returns the first grapheme cluster in the string and the rest of
+// the string that follows it.
+func Next(s string) (cluster string, rest string) {
+	if s == "" {
+		return "", ""
+	}
+
+	r, size := utf8.DecodeRuneInString(s)
+	prev := classify(r)
+
+	// Track the state needed by the rules that look further back than
+	// the previous rune.
+	emoji := prev == pictographic
+	regional := 0
+	if prev == regionalIndicator {
+		regional = 1
+	}
+
+	end := size
+	for end < len(s) {
+		r, size := utf8.DecodeRuneInString(s[end:])
+		cur := classify(r)
+
+		if isBreak(prev, cur, emoji, regional) {
+			break
+		}
+
+		// An emoji followed by extending runes can still be joined.
+		switch cur {
+		case pictographic:
+			emoji = true
+		case extend, zwj:
+		default:
+			emoji = false
+		}
+
+		if cur == regionalIndicator {
+			regional++
+		}
+
+		prev = cur
+		end += size
+	}
+
+	return s[:end], s[end:]
+}
+
+// isBreak reports whether there is a cluster boundary between runes of
+// the prev and cur classes.
+func isBreak(prev, cur class, emoji bool, regional int) bool {
+	switch {
+
+	// GB3: Never break between a CR and LF.
+	case prev == cr && cur == lf:
+		return false
+
+	// GB4, GB5: Always break around controls.
+	case prev == cr, prev == lf, prev == control:
+		return true
+	case cur == cr, cur == lf, cur == control:
+		return true
+
+	// GB6, GB7, GB8: Do not break Hangul syllable sequences.
+	case prev == hangulL && (cur == hangulL || cur == hangulV || cur == hangulLV || cur == hangulLVT):
+		return false
+	case (prev == hangulLV || prev == hangulV) && (cur == hangulV || cur == hangulT):
+		return false
+	case (prev == hangulLVT || prev == hangulT) && cur == hangulT:
+		return false
+
+	// GB9, GB9a: Do not break before extending runes and spacing marks.
+	case cur == extend, cur == zwj, cur == spacingMark:
+		return false
+
+	// GB11: Do not break within emoji joined by zero width joiners.
+	case prev == zwj && cur == pictographic && emoji:
+		return false
+
+	// GB12, GB13: Regional indicators are paired into flags.
+	case prev == regionalIndicator && cur == regionalIndicator:
+		return regional%2 == 0
+	}
+
+	// GB999: Otherwise, break everywhere.
+	return true
+}
+
+// Split returns the grapheme clusters of the string.
+func Split(s string) []string {
+	var clusters []string
+	for s != "" {
+		var cluster string
+		cluster, s = Next(s)
+		clusters = append(clusters, cluster)
+	}
+
+	return clusters
+}
+
+// Count returns the number of grapheme clusters in the string.
+func Count(s string) int {
+	var n int
+	for s != "" {
+		_, s = Next(s)
+		n++
+	}
+
+	return n
+}
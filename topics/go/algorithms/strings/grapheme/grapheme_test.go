@@ -0,0 +1,82 @@
+package grapheme_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/ardanlabs/gotraining/topics/go/algorithms/strings/grapheme"
+)
+
+const succeed = "\u2713"
+const failed = "\u2717"
+
+func TestSplit(t *testing.T) {
+	tt := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{"empty", "", nil},
+		{"ascii", "go", []string{"g", "o"}},
+		{"composed", "No\u00ebl", []string{"N", "o", "\u00eb", "l"}},
+		{"combining", "Noe\u0308l", []string{"N", "o", "e\u0308", "l"}},
+		{"stacked", "e\u0301\u0301\u0301x", []string{"e\u0301\u0301\u0301", "x"}},
+		{"crlf", "a\r\nb", []string{"a", "\r\n", "b"}},
+		{"flags", "\U0001F1EF\U0001F1F5\U0001F1FA\U0001F1F8\U0001F1EB", []string{"\U0001F1EF\U0001F1F5", "\U0001F1FA\U0001F1F8", "\U0001F1EB"}},
+		{"family", "\U0001F468\u200D\U0001F469\u200D\U0001F467!", []string{"\U0001F468\u200D\U0001F469\u200D\U0001F467", "!"}},
+		{"skintone", "\U0001F44D\U0001F3FD\U0001F44D", []string{"\U0001F44D\U0001F3FD", "\U0001F44D"}},
+		{"hangul", "\u1100\u1161\u11A8\uAC00", []string{"\u1100\u1161\u11A8", "\uAC00"}},
+		{"devanagari", "\u0915\u093F", []string{"\u0915\u093F"}},
+		{"chinese", "汉字", []string{"汉", "字"}},
+	}
+
+	t.Log("Given the need to split strings into grapheme clusters.")
+	{
+		for testID, test := range tt {
+			tf := func(t *testing.T) {
+				t.Logf("\tTest %d:\tWhen splitting %q.", testID, test.input)
+				{
+					got := grapheme.Split(test.input)
+					if !reflect.DeepEqual(got, test.expected) {
+						t.Logf("\t%s\tTest %d:\tShould get back the clusters.", failed, testID)
+						t.Fatalf("\t\tTest %d:\tGot %q, Expected %q", testID, got, test.expected)
+					}
+					t.Logf("\t%s\tTest %d:\tShould get back the clusters.", succeed, testID)
+
+					if n := grapheme.Count(test.input); n != len(test.expected) {
+						t.Fatalf("\t%s\tTest %d:\tShould count the clusters : %d, Expected %d", failed, testID, n, len(test.expected))
+					}
+					t.Logf("\t%s\tTest %d:\tShould count the clusters.", succeed, testID)
+				}
+			}
+			t.Run(test.name, tf)
+		}
+	}
+}
+
+func FuzzSplit(f *testing.F) {
+	f.Add("Noe\u0308l")
+	f.Add("\U0001F468\u200D\U0001F469\u200D\U0001F467")
+	f.Add("\U0001F1EF\U0001F1F5\U0001F1FA")
+	f.Add("\xff\u0301")
+
+	f.Fuzz(func(t *testing.T, s string) {
+		clusters := grapheme.Split(s)
+
+		// The clusters must rebuild the string and never be empty.
+		if joined := strings.Join(clusters, ""); joined != s {
+			t.Fatalf("Split(%q) = %q, joins to %q", s, clusters, joined)
+		}
+		for _, cluster := range clusters {
+			if cluster == "" {
+				t.Fatalf("Split(%q) = %q, has an empty cluster", s, clusters)
+			}
+		}
+
+		if n := utf8.RuneCountInString(s); len(clusters) > n {
+			t.Fatalf("Split(%q) has %d clusters for %d runes", s, len(clusters), n)
+		}
+	})
+}
@@ -0,0 +1,80 @@
+// Package normalize prepares strings to be compared the way a reader would
+// compare them. The same text can be written with different runes: "ë" can
+// be a single rune or an "e" followed by a combining diaeresis, and "Straße"
+// matches "STRASSE" when case is ignored.
+package normalize
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Options selects the normalizations applied to a string.
+type Options struct {
+
+	// Compose converts the string to Unicode Normalization Form C, so
+	// composed and decomposed forms of a character use the same runes.
+	Compose bool
+
+	// FoldCase maps every rune to its case folded form, so strings that
+	// only differ in case are equal.
+	FoldCase bool
+
+	// StripMarks removes accents and other combining marks, so "Noël"
+	// and "Noel" are equal.
+	StripMarks bool
+
+	// LettersOnly removes everything that is not a letter or digit, like
+	// white space and punctuation.
+	LettersOnly bool
+}
+
+// Text returns the options used for comparing text the way people usually
+// do, ignoring case, accents, white space and punctuation.
+func Text() Options {
+	return Options{
+		Compose:     true,
+		FoldCase:    true,
+		StripMarks:  true,
+		LettersOnly: true,
+	}
+}
+
+// String applies the normalizations selected by the options to the string.
+func String(s string, opts Options) string {
+
+	// Decompose the string so the marks are separate runes that can be
+	// removed.
+	if opts.StripMarks {
+		s = norm.NFD.String(s)
+		s = strings.Map(func(r rune) rune {
+			if unicode.Is(unicode.Mn, r) {
+				return -1
+			}
+			return r
+		}, s)
+	}
+
+	if opts.FoldCase {
+		s = cases.Fold().String(s)
+	}
+
+	if opts.LettersOnly {
+		s = strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.In(r, unicode.Mc, unicode.Mn, unicode.Me) {
+				return r
+			}
+			return -1
+		}, s)
+	}
+
+	// Folding can decompose runes, so the string is composed last.
+	if opts.Compose || opts.StripMarks {
+		s = norm.NFC.String(s)
+	}
+
+	return s
+}
@@ -0,0 +1,45 @@
+package normalize_test
+
+import (
+	"testing"
+
+	"github.com/ardanlabs/gotraining/topics/go/algorithms/strings/normalize"
+)
+
+const succeed = "\u2713"
+const failed = "\u2717"
+
+func TestString(t *testing.T) {
+	tt := []struct {
+		name     string
+		input    string
+		opts     normalize.Options
+		expected string
+	}{
+		{"none", "Noe\u0308l", normalize.Options{}, "Noe\u0308l"},
+		{"compose", "Noe\u0308l", normalize.Options{Compose: true}, "No\u00ebl"},
+		{"foldcase", "Straße", normalize.Options{FoldCase: true}, "strasse"},
+		{"stripmarks", "Noe\u0308l Café", normalize.Options{StripMarks: true}, "Noel Cafe"},
+		{"lettersonly", "A man, a plan!", normalize.Options{LettersOnly: true}, "Amanaplan"},
+		{"keepmarks", "No\u00ebl, 1!", normalize.Options{Compose: true, FoldCase: true, LettersOnly: true}, "no\u00ebl1"},
+		{"text", "Noe\u0308l, NOËL!", normalize.Text(), "noelnoel"},
+	}
+
+	t.Log("Given the need to normalize strings for comparison.")
+	{
+		for testID, test := range tt {
+			tf := func(t *testing.T) {
+				t.Logf("\tTest %d:\tWhen normalizing %q.", testID, test.input)
+				{
+					got := normalize.String(test.input, test.opts)
+					if got != test.expected {
+						t.Logf("\t%s\tTest %d:\tShould get back the normalized string.", failed, testID)
+						t.Fatalf("\t\tTest %d:\tGot %q, Expected %q", testID, got, test.expected)
+					}
+					t.Logf("\t%s\tTest %d:\tShould get back the normalized string.", succeed, testID)
+				}
+			}
+			t.Run(test.name, tf)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package palindrome
 
-import "github.com/ardanlabs/gotraining/topics/go/algorithms/strings/reverse"
+import (
+	"github.com/ardanlabs/gotraining/topics/go/algorithms/strings/normalize"
+	"github.com/ardanlabs/gotraining/topics/go/algorithms/strings/reverse"
+)
 
 // Is checks if a string is a Palindrome.
 func Is(input string) bool {
@@ -20,3 +23,10 @@ func Is(input string) bool {
 
 	return false
 }
+
+// IsWith checks if a string is a Palindrome after applying the specified
+// normalizations. With normalize.Text, "A man, a plan, a canal: Panama"
+// is a palindrome.
+func IsWith(input string, opts normalize.Options) bool {
+	return Is(normalize.String(input, opts))
+}
@@ -6,6 +6,10 @@
 
 	// Is checks if a string is a Palindrome.
 	func Is(input string) bool
+
+	// IsWith checks if a string is a Palindrome after applying the specified
+	// normalizations.
+	func IsWith(input string, opts normalize.Options) bool
 */
 
 package palindrome_test
@@ -13,6 +17,7 @@ package palindrome_test
 import (
 	"testing"
 
+	"github.com/ardanlabs/gotraining/topics/go/algorithms/strings/normalize"
 	"github.com/ardanlabs/gotraining/topics/go/algorithms/strings/palindrome"
 )
 
@@ -31,6 +36,8 @@ func TestIsPalindrome(t *testing.T) {
 		{"even", "otto", true},
 		{"chinese", "汉字汉", true},
 		{"not", "test", false},
+		{"combining", "e\u0301ae\u0301", true},
+		{"sentence", "A man, a plan, a canal: Panama", false},
 	}
 
 	t.Log("Given the need to test palindrome functionality.")
@@ -58,3 +65,36 @@ func TestIsPalindrome(t *testing.T) {
 		}
 	}
 }
+
+func TestIsPalindromeWith(t *testing.T) {
+	tt := []struct {
+		name    string
+		input   string
+		opts    normalize.Options
+		success bool
+	}{
+		{"sentence", "A man, a plan, a canal: Panama", normalize.Text(), true},
+		{"case", "Racecar", normalize.Options{FoldCase: true}, true},
+		{"nocase", "Racecar", normalize.Options{}, false},
+		{"accents", "Ésope reste ici et se repose", normalize.Text(), true},
+		{"composed", "e\u0301a\u00e9", normalize.Options{Compose: true}, true},
+		{"not", "A man, a plan", normalize.Text(), false},
+	}
+
+	t.Log("Given the need to test palindrome functionality with normalization.")
+	{
+		for testID, test := range tt {
+			tf := func(t *testing.T) {
+				t.Logf("\tTest %d:\tWhen checking the sentence %q.", testID, test.input)
+				{
+					got := palindrome.IsWith(test.input, test.opts)
+					if got != test.success {
+						t.Fatalf("\t%s\tTest %d:\tShould have seen the palindrome was %v.", failed, testID, test.success)
+					}
+					t.Logf("\t%s\tTest %d:\tShould have seen the palindrome was %v.", succeed, testID, test.success)
+				}
+			}
+			t.Run(test.name, tf)
+		}
+	}
+}
@@ -2,6 +2,9 @@ package permutation
 
 import (
 	"sort"
+
+	"github.com/ardanlabs/gotraining/topics/go/algorithms/strings/grapheme"
+	"github.com/ardanlabs/gotraining/topics/go/algorithms/strings/normalize"
 )
 
 // RuneSlice a custom type of a slice of runes.
@@ -32,3 +35,35 @@ func Is(str1, str2 string) bool {
 	// and compare.
 	return string(s1) == string(s2)
 }
+
+// IsWith check if two strings are permutations after applying the specified
+// normalizations. The grapheme clusters are compared instead of the runes,
+// so an accent has to stay on the same letter. With normalize.Text,
+// "Dormitory" and "dirty room!" are permutations.
+func IsWith(str1, str2 string, opts normalize.Options) bool {
+	str1 = normalize.String(str1, opts)
+	str2 = normalize.String(str2, opts)
+
+	// If the length are not equal they cannot be permutation.
+	if len(str1) != len(str2) {
+		return false
+	}
+
+	// Convert each string into a collection of clusters.
+	c1 := grapheme.Split(str1)
+	c2 := grapheme.Split(str2)
+	if len(c1) != len(c2) {
+		return false
+	}
+
+	// Sort each collection of clusters and compare them.
+	sort.Strings(c1)
+	sort.Strings(c2)
+	for i := range c1 {
+		if c1[i] != c2[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -6,6 +6,10 @@
 
 	// Is check if two strings are permutations.
 	func Is(str1, str2 string) bool
+
+	// IsWith check if two strings are permutations after applying the
+	// specified normalizations.
+	func IsWith(str1, str2 string, opts normalize.Options) bool
 */
 
 package permutation_test
@@ -13,6 +17,7 @@ package permutation_test
 import (
 	"testing"
 
+	"github.com/ardanlabs/gotraining/topics/go/algorithms/strings/normalize"
 	"github.com/ardanlabs/gotraining/topics/go/algorithms/strings/permutation"
 )
 
@@ -57,3 +62,37 @@ func TestIsPermutation(t *testing.T) {
 		}
 	}
 }
+
+func TestIsPermutationWith(t *testing.T) {
+	tt := []struct {
+		name    string
+		input   string
+		input2  string
+		opts    normalize.Options
+		success bool
+	}{
+		{"anagram", "Dormitory", "dirty room!", normalize.Text(), true},
+		{"case", "Listen", "Silent", normalize.Options{FoldCase: true}, true},
+		{"nocase", "Listen", "Silent", normalize.Options{}, false},
+		{"composed", "Noe\u0308l", "l\u00ebNo", normalize.Options{Compose: true}, true},
+		{"movedmark", "e\u0301a", "a\u0301e", normalize.Options{Compose: true}, false},
+		{"stripmarks", "Noël", "Leon", normalize.Text(), true},
+	}
+
+	t.Log("Given the need to test permutation functionality with normalization.")
+	{
+		for testID, test := range tt {
+			tf := func(t *testing.T) {
+				t.Logf("\tTest %d:\tWhen checking the words %q and %q.", testID, test.input, test.input2)
+				{
+					got := permutation.IsWith(test.input, test.input2, test.opts)
+					if got != test.success {
+						t.Fatalf("\t%s\tTest %d:\tShould have seen the permutation was %v.", failed, testID, test.success)
+					}
+					t.Logf("\t%s\tTest %d:\tShould have seen the permutation was %v.", succeed, testID, test.success)
+				}
+			}
+			t.Run(test.name, tf)
+		}
+	}
+}
@@ -1,27 +1,32 @@
 package reverse
 
+import "github.com/ardanlabs/gotraining/topics/go/algorithms/strings/grapheme"
+
 // String takes the specified string and reverses it.
 func String(str string) string {
 
-	// Convert the input string into slice of runes for processing.
-	// A rune represent a code point in the UTF-8 character set.
-	runes := []rune(str)
+	// Convert the input string into slice of grapheme clusters for
+	// processing. A cluster is what a reader sees as a single character
+	// and can be made of several runes, like an "e" followed by a
+	// combining accent. Reversing the runes would move the accent to
+	// the character that came before it.
+	clusters := grapheme.Split(str)
 
 	// Create an index that will traverse the collection of
-	// runes from the beginning to the end.
+	// clusters from the beginning to the end.
 	var beg int
 
 	// Create an index that will traverse the collection of
-	// runes from the end to the beginning.
-	end := len(runes) - 1
+	// clusters from the end to the beginning.
+	end := len(clusters) - 1
 
-	// Keep swapping runes until the two indexes meet in the middle.
+	// Keep swapping clusters until the two indexes meet in the middle.
 	for beg < end {
 
-		// Swap the position of these two rune’s.
-		r := runes[beg]
-		runes[beg] = runes[end]
-		runes[end] = r
+		// Swap the position of these two clusters.
+		c := clusters[beg]
+		clusters[beg] = clusters[end]
+		clusters[end] = c
 
 		// Move the indexes closer to each other
 		// working towards the middle of the collection.
@@ -29,5 +34,15 @@ func String(str string) string {
 		end = end - 1
 	}
 
-	return string(runes)
+	// Join the clusters back into a string.
+	var n int
+	for _, c := range clusters {
+		n += len(c)
+	}
+	b := make([]byte, 0, n)
+	for _, c := range clusters {
+		b = append(b, c...)
+	}
+
+	return string(b)
 }
@@ -12,7 +12,9 @@ package reverse_test
 
 import (
 	"testing"
+	"unicode/utf8"
 
+	"github.com/ardanlabs/gotraining/topics/go/algorithms/strings/grapheme"
 	"github.com/ardanlabs/gotraining/topics/go/algorithms/strings/reverse"
 )
 
@@ -50,3 +52,45 @@ func TestReverseString(t *testing.T) {
 		}
 	}
 }
+
+func FuzzReverseString(f *testing.F) {
+	f.Add("Hello World")
+	f.Add("Noe\u0308l")
+	f.Add("\U0001F468\u200D\U0001F469\u200D\U0001F467")
+
+	f.Fuzz(func(t *testing.T, s string) {
+		if !utf8.ValidString(s) {
+			t.Skip()
+		}
+
+		got := reverse.String(s)
+		if len(got) != len(s) {
+			t.Fatalf("String(%q) = %q, has a different length", s, got)
+		}
+
+		// The same runes must be found in the reversed string.
+		count := make(map[rune]int)
+		for _, r := range s {
+			count[r]++
+		}
+		for _, r := range got {
+			count[r]--
+		}
+		for r, n := range count {
+			if n != 0 {
+				t.Fatalf("String(%q) = %q, has %d extra %q runes", s, got, -n, r)
+			}
+		}
+
+		// When every cluster is a single rune, the runes are reversed.
+		if grapheme.Count(s) == utf8.RuneCountInString(s) {
+			runes := []rune(s)
+			for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+				runes[i], runes[j] = runes[j], runes[i]
+			}
+			if got != string(runes) {
+				t.Fatalf("String(%q) = %q, Expected %q", s, got, string(runes))
+			}
+		}
+	})
+}
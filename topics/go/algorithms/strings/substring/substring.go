@@ -0,0 +1,163 @@
+// Package substring provides algorithms that find a pattern inside a text.
+// Both work on the bytes of the strings. Since no UTF-8 encoded rune is
+// found inside the encoding of another rune, a match always starts and
+// ends on a rune boundary of valid UTF-8 text.
+package substring
+
+// KMP returns the byte index of the first match of the pattern in the text
+// using the Knuth-Morris-Pratt algorithm, or -1 if there is no match. After
+// a mismatch, a table built from the pattern tells how much of the pattern
+// is already matched, so no byte of the text is checked twice.
+// - Time Complexity O(n + m)
+// - Auxiliary Space: O(m)
+func KMP(text, pattern string) int {
+	if pattern == "" {
+		return 0
+	}
+
+	// prefix[i] is the length of the longest proper prefix of
+	// pattern[:i+1] that is also a suffix of it.
+	prefix := make([]int, len(pattern))
+	for i, matched := 1, 0; i < len(pattern); i++ {
+		for matched > 0 && pattern[i] != pattern[matched] {
+			matched = prefix[matched-1]
+		}
+		if pattern[i] == pattern[matched] {
+			matched++
+		}
+		prefix[i] = matched
+	}
+
+	// Walk the text once, falling back in the pattern on a mismatch.
+	matched := 0
+	for i := 0; i < len(text); i++ {
+		for matched > 0 && text[i] != pattern[matched] {
+			matched = prefix[matched-1]
+		}
+		if text[i] == pattern[matched] {
+			matched++
+		}
+		if matched == len(pattern) {
+			return i - len(pattern) + 1
+		}
+	}
+
+	return -1
+}
+
+// BoyerMoore returns the byte index of the first match of the pattern in
+// the text using the Boyer-Moore algorithm, or -1 if there is no match. The
+// pattern is compared from its last byte, and on a mismatch the pattern is
+// shifted by the larger of the bad character and the good suffix rules,
+// which often skips large parts of the text. Without the Galil rule, the
+// bytes matched before a shift are compared again, so a periodic pattern
+// in a periodic text, like "aaa" in "aaaa...", is slow.
+// - Time Complexity O(n * m) worst case, O(n/m) for a typical text
+// - Auxiliary Space: O(m + 256)
+func BoyerMoore(text, pattern string) int {
+	m := len(pattern)
+	if m == 0 {
+		return 0
+	}
+
+	// Bad character rule: the last position of every byte in the pattern.
+	var last [256]int
+	for i := range last {
+		last[i] = -1
+	}
+	for i := 0; i < m; i++ {
+		last[pattern[i]] = i
+	}
+
+	good := goodSuffix(pattern)
+
+	for pos := 0; pos <= len(text)-m; {
+
+		// Compare the pattern from the end.
+		j := m - 1
+		for j >= 0 && pattern[j] == text[pos+j] {
+			j--
+		}
+		if j < 0 {
+			return pos
+		}
+
+		// Shift so the mismatched byte of the text lines up with its last
+		// position in the pattern, or by the good suffix rule, whichever
+		// moves further.
+		shift := j - last[text[pos+j]]
+		if good[j] > shift {
+			shift = good[j]
+		}
+		pos += shift
+	}
+
+	return -1
+}
+
+// goodSuffix returns the shift for a mismatch at every position of the
+// pattern. The suffix after the mismatch was matched, so the pattern can
+// move to the next place where that suffix is found again, or to where a
+// prefix of the pattern matches the end of the suffix.
+func goodSuffix(pattern string) []int {
+	m := len(pattern)
+	shift := make([]int, m)
+
+	suffix := suffixes(pattern)
+
+	// By default, shift past any prefix of the pattern that is also a
+	// suffix of it.
+	j := 0
+	for i := m - 1; i >= 0; i-- {
+		if suffix[i] == i+1 {
+			for ; j < m-1-i; j++ {
+				shift[j] = m - 1 - i
+			}
+		}
+	}
+	for ; j < m; j++ {
+		shift[j] = m
+	}
+
+	// Where the matched suffix is found again in the pattern, shift to it.
+	for i := 0; i < m-1; i++ {
+		shift[m-1-suffix[i]] = m - 1 - i
+	}
+
+	return shift
+}
+
+// suffixes returns, for every position i of the pattern, the length of the
+// longest substring ending at i that is also a suffix of the pattern. The
+// window pattern[g+1:f+1] is the last substring found to match a suffix.
+// Inside it, the length at i is known from the length at the matching
+// position of the suffix, so every byte is compared a constant number of
+// times and the table is built in O(m).
+func suffixes(pattern string) []int {
+	m := len(pattern)
+	suffix := make([]int, m)
+	suffix[m-1] = m
+
+	f, g := m-1, m-1
+	for i := m - 2; i >= 0; i-- {
+
+		// Use the length at the matching position when it ends inside
+		// the window.
+		if i > g && suffix[i+m-1-f] < i-g {
+			suffix[i] = suffix[i+m-1-f]
+			continue
+		}
+
+		// Extend the match past the window one byte at a time.
+		if i < g {
+			g = i
+		}
+		f = i
+		for g >= 0 && pattern[g] == pattern[g+m-1-f] {
+			g--
+		}
+		suffix[i] = f - g
+	}
+
+	return suffix
+}
@@ -0,0 +1,118 @@
+package substring_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ardanlabs/gotraining/topics/go/algorithms/strings/substring"
+)
+
+const succeed = "\u2713"
+const failed = "\u2717"
+
+// searches are the algorithms checked by every test.
+var searches = []struct {
+	name  string
+	index func(text, pattern string) int
+}{
+	{"KMP", substring.KMP},
+	{"BoyerMoore", substring.BoyerMoore},
+}
+
+func TestIndex(t *testing.T) {
+	tt := []struct {
+		name     string
+		text     string
+		pattern  string
+		expected int
+	}{
+		{"empty", "", "", 0},
+		{"emptypattern", "go", "", 0},
+		{"emptytext", "", "go", -1},
+		{"start", "gopher", "go", 0},
+		{"end", "gopher", "her", 3},
+		{"missing", "gopher", "cat", -1},
+		{"longer", "go", "gopher", -1},
+		{"repeats", "aaaaab", "aab", 3},
+		{"overlap", "abababca", "ababca", 2},
+		{"suffix", "abcxxxabcabd", "abcabd", 6},
+		{"first", "abcabcabc", "cab", 2},
+		{"unicode", "汉字 Noël 汉字", "Noël", 7},
+	}
+
+	t.Log("Given the need to find a pattern in a text.")
+	{
+		for _, s := range searches {
+			for testID, test := range tt {
+				tf := func(t *testing.T) {
+					t.Logf("\tTest %d:\tWhen searching %q for %q.", testID, test.text, test.pattern)
+					{
+						got := s.index(test.text, test.pattern)
+						if got != test.expected {
+							t.Logf("\t%s\tTest %d:\tShould find the index of the first match.", failed, testID)
+							t.Fatalf("\t\tTest %d:\tGot %d, Expected %d", testID, got, test.expected)
+						}
+						t.Logf("\t%s\tTest %d:\tShould find the index of the first match.", succeed, testID)
+					}
+				}
+				t.Run(s.name+"/"+test.name, tf)
+			}
+		}
+	}
+}
+
+// TestIndexAll checks every text and pattern made of two letters, which
+// is where the shift rules are most likely to go wrong.
+func TestIndexAll(t *testing.T) {
+	var words []string
+	words = append(words, "")
+	for i := 0; len(words[i]) < 8; i++ {
+		words = append(words, words[i]+"a", words[i]+"b")
+	}
+
+	t.Log("Given the need to find every pattern in every text.")
+	{
+		for _, s := range searches {
+			for _, text := range words {
+				for _, pattern := range words {
+					if len(pattern) > 4 {
+						break
+					}
+					if got, exp := s.index(text, pattern), strings.Index(text, pattern); got != exp {
+						t.Fatalf("\t%s\tShould find %q in %q with %s : %d, Expected %d", failed, pattern, text, s.name, got, exp)
+					}
+				}
+			}
+			t.Logf("\t%s\tShould find every pattern in every text with %s.", succeed, s.name)
+		}
+	}
+}
+
+func FuzzIndex(f *testing.F) {
+	f.Add("abababca", "ababca")
+	f.Add("aaaaab", "aab")
+	f.Add("汉字 Noël", "ël")
+
+	f.Fuzz(func(t *testing.T, text, pattern string) {
+		exp := strings.Index(text, pattern)
+		for _, s := range searches {
+			if got := s.index(text, pattern); got != exp {
+				t.Fatalf("%s(%q, %q) = %d, Expected %d", s.name, text, pattern, got, exp)
+			}
+		}
+	})
+}
+
+var idx int
+
+func BenchmarkIndex(b *testing.B) {
+	text := strings.Repeat("the quick brown fox jumps over the lazy dog ", 1000) + "gopher"
+
+	for _, s := range searches {
+		b.Run(s.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				idx = s.index(text, "gopher")
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package substring
+
+import (
+	"math/rand"
+	"testing"
+)
+
+const succeed = "\u2713"
+const failed = "\u2717"
+
+// TestSuffixes validates the linear suffix table against comparing every
+// position byte by byte.
+func TestSuffixes(t *testing.T) {
+	t.Log("Given the need to build the suffix table in linear time.")
+	{
+		rnd := rand.New(rand.NewSource(1))
+		for testID, alphabet := range []string{"a", "ab", "abc"} {
+			t.Logf("\tTest %d:\tWhen the pattern uses the bytes %q.", testID, alphabet)
+			{
+				for n := 0; n < 1000; n++ {
+					pattern := make([]byte, 1+rnd.Intn(20))
+					for i := range pattern {
+						pattern[i] = alphabet[rnd.Intn(len(alphabet))]
+					}
+
+					got := suffixes(string(pattern))
+					for i := range pattern {
+						exp := 0
+						for exp <= i && pattern[i-exp] == pattern[len(pattern)-1-exp] {
+							exp++
+						}
+						if got[i] != exp {
+							t.Fatalf("\t%s\tTest %d:\tShould get suffix length %d at %d of %q : %d", failed, testID, exp, i, pattern, got[i])
+						}
+					}
+				}
+				t.Logf("\t%s\tTest %d:\tShould match the lengths found byte by byte.", succeed, testID)
+			}
+		}
+	}
+}
@@ -1,220 +1,385 @@
 // All material is licensed under the Apache License Version 2.0, January 2004
 // http://www.apache.org/licenses/LICENSE-2.0
 
-// Package chat implements a basic chat room.
+// Package chat implements a chat server with multiple rooms. Users send
+// lines of text which are sent to everyone in the same room, or they
+// send one of these commands:
+//
+//	/nick name      Change your name.
+//	/join room      Move to the room, which is created if needed.
+//	/leave          Go back to the lobby.
+//	/who            List the users in your room.
+//	/msg name text  Send a private message.
+//
+// Lines from the server start with "* " and messages from users start
+// with their name. Users connect over TCP sending lines of text, or from
+// a browser over a WebSocket sending text messages.
 package chat
 
 import (
-	"bufio"
+	"errors"
 	"fmt"
-	"io"
 	"log"
 	"net"
+	"sort"
+	"strings"
 	"sync"
+	"unicode"
 )
 
-// temporary is declared to test for the existence of the method coming
-// from the net package.
-type temporary interface {
-	Temporary() bool
-}
-
-// message is the data received and sent to users in the chatroom.
-type message struct {
-	data string
-	conn net.Conn
-}
-
-// client represents a single connection in the room.
-type client struct {
-	name   string
-	room   *Room
-	reader *bufio.Reader
-	writer *bufio.Writer
-	wg     sync.WaitGroup
-	conn   net.Conn
-}
-
-// read waits for message and sends it to the chatroom for processing.
-func (c *client) read() {
-	for {
+// Lobby is the room users are in when they connect.
+const Lobby = "lobby"
 
-		// Wait for a message to arrive.
-		line, err := c.reader.ReadString('\n')
+// DefaultQueueSize is the number of lines that can wait to be written
+// to a client before the client is dropped.
+const DefaultQueueSize = 64
 
-		if err == nil {
-			c.room.outgoing <- message{
-				data: line,
-				conn: c.conn,
-			}
-			continue
-		}
+// minQueueSize is the smallest queue that holds the lines sent to a
+// client when it joins.
+const minQueueSize = 2
 
-		if e, is := err.(temporary); is && !e.Temporary() {
-			log.Println("Temporary: Client leaving chat")
-			c.wg.Done()
-			return
-		}
+// Option configures the behavior of a server.
+type Option func(*Server)
 
-		if err == io.EOF {
-			log.Println("EOF: Client leaving chat")
-			c.wg.Done()
-			return
+// WithQueueSize sets the number of lines that can wait to be written to
+// a client before the client is dropped. Sizes smaller than 2 are raised
+// to 2, so a new client isn't dropped while it joins.
+func WithQueueSize(n int) Option {
+	return func(s *Server) {
+		if n < minQueueSize {
+			n = minQueueSize
 		}
-
-		log.Println("read-routine", err)
+		s.queueSize = n
 	}
 }
 
-// write is a goroutine to handle processing outgoing
-// messages to this client.
-func (c *client) write(m message) {
-	msg := fmt.Sprintf("%s %s", c.name, m.data)
-	log.Printf(msg)
-
-	c.writer.WriteString(msg)
-	c.writer.Flush()
-}
-
-// drop closes the client connection and read goroutine.
-func (c *client) drop() {
-
-	// Close the connection.
-	c.conn.Close()
-	c.wg.Wait()
+// Room contains the clients that receive each others messages.
+type Room struct {
+	name    string
+	clients map[*client]struct{}
 }
 
-// newClient create a new client for an incoming connection.
-func newClient(room *Room, conn net.Conn, name string) *client {
-	c := client{
-		name:   name,
-		room:   room,
-		reader: bufio.NewReader(conn),
-		writer: bufio.NewWriter(conn),
-		conn:   conn,
-	}
+// Server accepts network connections and manages the rooms. A single
+// processing goroutine owns the rooms and clients, so they are used
+// without locks.
+type Server struct {
+	listener  net.Listener
+	queueSize int
 
-	c.wg.Add(1)
-	go c.read()
+	rooms   map[string]*Room
+	clients map[string]*client
+	nextID  int
 
-	return &c
-}
-
-// Room contains a set of networked client connections.
-type Room struct {
-	listener net.Listener
-	clients  []*client
-	joining  chan net.Conn
-	outgoing chan message
+	joining  chan transport
+	leaving  chan *client
+	incoming chan message
 	shutdown chan struct{}
 	wg       sync.WaitGroup
+	clientWG sync.WaitGroup
+	once     sync.Once
 }
 
-// sendGroupMessage sends a message to all clients in the room.
-func (r *Room) sendGroupMessage(m message) {
-	for _, c := range r.clients {
-		if c.conn != m.conn {
-			c.write(m)
-		}
+// NewServer creates a chat server that accepts connections from the
+// listener.
+func NewServer(listener net.Listener, opts ...Option) *Server {
+
+	// Create a Server value.
+	s := Server{
+		listener:  listener,
+		queueSize: DefaultQueueSize,
+		rooms:     make(map[string]*Room),
+		clients:   make(map[string]*client),
+		joining:   make(chan transport),
+		leaving:   make(chan *client),
+		incoming:  make(chan message),
+		shutdown:  make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(&s)
 	}
-}
 
-// join takes a new connection and adds it to the room.
-func (r *Room) join(conn net.Conn) {
-	name := fmt.Sprintf("Conn: %d", len(r.clients))
-	log.Println("New client joining chat:", name)
+	// Start the server.
+	s.start()
 
-	c := newClient(r, conn, name)
-	r.clients = append(r.clients, c)
+	// Return a pointer back to the caller.
+	return &s
 }
 
-// start turns the chatroom on.
-func (r *Room) start() {
-	r.wg.Add(2)
+// start turns the server on.
+func (s *Server) start() {
+	s.wg.Add(2)
 
-	// Chatroom processing goroutne.
+	// Server processing goroutine.
 	go func() {
+		defer s.wg.Done()
+
 		for {
 			select {
-			case message := <-r.outgoing:
+			case m := <-s.incoming:
+
+				// Run the command or send the message to the room.
+				s.handle(m.client, m.data)
+
+			case conn := <-s.joining:
 
-				// Sent message to the group.
-				r.sendGroupMessage(message)
-			case conn := <-r.joining:
+				// Join this connection to the lobby.
+				s.join(conn)
 
-				// Join this connection to the room.
-				r.join(conn)
-			case <-r.shutdown:
+			case c := <-s.leaving:
 
-				// Chatroom shutting down.
-				r.wg.Done()
+				// Remove the client that disconnected.
+				s.drop(c, "left")
+
+			case <-s.shutdown:
+
+				// Server shutting down, drop all existing connections.
+				for _, c := range s.clients {
+					c.drop()
+				}
 				return
 			}
 		}
 	}()
 
-	// Chatroom connection accept goroutine.
+	// Server connection accept goroutine.
 	go func() {
-		var err error
-		if r.listener, err = net.Listen("tcp", ":6000"); err != nil {
-			log.Fatalln(err)
-		}
+		defer s.wg.Done()
 
-		log.Println("Chat room started: 6000")
+		log.Println("Chat server started:", s.listener.Addr())
 
 		for {
-			conn, err := r.listener.Accept()
+			conn, err := s.listener.Accept()
 			if err != nil {
-
-				// Check if the error is temporary or not.
-				if e, is := err.(temporary); is {
-					if !e.Temporary() {
-						log.Println("Temporary: Chat room shutting down")
-						r.wg.Done()
-						return
-					}
+				if errors.Is(err, net.ErrClosed) {
+					log.Println("Chat server shutting down")
+					return
 				}
 
 				log.Println("accept-routine", err)
 				continue
 			}
 
-			// Add this new connection to the room.
-			r.joining <- conn
+			// Add this new connection to the server.
+			s.serve(newLineConn(conn))
 		}
 	}()
 }
 
-// Close shutdown the chatroom and closes all connections.
-func (r *Room) Close() error {
+// serve hands the connection to the processing goroutine.
+func (s *Server) serve(conn transport) {
+	select {
+	case s.joining <- conn:
+	case <-s.shutdown:
+		conn.Close()
+	}
+}
+
+// Close shuts down the server and closes all connections. Calling
+// Close more than once does nothing.
+func (s *Server) Close() error {
+	var err error
+	s.once.Do(func() {
+
+		// Don't accept anymore client connections.
+		err = s.listener.Close()
 
-	// Don't accept anymore client connections.
-	r.listener.Close()
+		// Signal the processing goroutine to stop and wait for the
+		// clients to terminate.
+		close(s.shutdown)
+		s.wg.Wait()
+		s.clientWG.Wait()
+	})
 
-	// Signal the chatroom processing goroutine to stop.
-	close(r.shutdown)
-	r.wg.Wait()
+	return err
+}
 
-	// Drop all existing connections.
-	for _, c := range r.clients {
-		c.drop()
+// join takes a new connection and adds it to the lobby.
+func (s *Server) join(conn transport) {
+	s.nextID++
+	name := fmt.Sprintf("guest%d", s.nextID)
+	for s.clients[name] != nil {
+		s.nextID++
+		name = fmt.Sprintf("guest%d", s.nextID)
 	}
-	return nil
+	log.Println("New client joining chat:", name)
+
+	c := newClient(s, conn, name, s.queueSize)
+	s.clients[name] = c
+
+	// Track the client goroutines for Close.
+	s.clientWG.Add(1)
+	go func() {
+		c.wg.Wait()
+		s.clientWG.Done()
+	}()
+
+	s.notify(c, "* welcome %s, type /nick to change your name", name)
+	s.enter(c, Lobby)
 }
 
-// New creates a new chatroom.
-func New() *Room {
+// drop removes the client from the server and closes the connection.
+func (s *Server) drop(c *client, reason string) {
+	if c.closed {
+		return
+	}
 
-	// Create a Room value.
-	chatRoom := Room{
-		joining:  make(chan net.Conn),
-		outgoing: make(chan message),
-		shutdown: make(chan struct{}),
+	s.exit(c, reason)
+	delete(s.clients, c.name)
+	c.drop()
+}
+
+// enter adds the client to the room, creating the room if needed. A
+// client that was dropped is not added.
+func (s *Server) enter(c *client, name string) {
+	if c.closed {
+		return
+	}
+
+	r, ok := s.rooms[name]
+	if !ok {
+		r = &Room{name: name, clients: make(map[*client]struct{})}
+		s.rooms[name] = r
 	}
 
-	// Start the chatroom.
-	chatRoom.start()
+	r.clients[c] = struct{}{}
+	c.room = r
 
-	// Return a pointer back to the caller.
-	return &chatRoom
+	s.sendGroupMessage(r, c, fmt.Sprintf("* %s joined #%s", c.name, r.name))
+	s.notify(c, "* you are in #%s", r.name)
+}
+
+// exit removes the client from its room, removing the room when it is
+// empty unless it is the lobby.
+func (s *Server) exit(c *client, reason string) {
+	r := c.room
+	if r == nil {
+		return
+	}
+
+	delete(r.clients, c)
+	c.room = nil
+
+	if len(r.clients) == 0 && r.name != Lobby {
+		delete(s.rooms, r.name)
+		return
+	}
+
+	s.sendGroupMessage(r, c, fmt.Sprintf("* %s %s #%s", c.name, reason, r.name))
+}
+
+// handle runs the command in the line or sends the line to the room.
+func (s *Server) handle(c *client, line string) {
+	if c.closed || line == "" {
+		return
+	}
+
+	if !strings.HasPrefix(line, "/") {
+		s.sendGroupMessage(c.room, c, fmt.Sprintf("%s: %s", c.name, line))
+		return
+	}
+
+	cmd, args, _ := strings.Cut(line, " ")
+	args = strings.TrimSpace(args)
+
+	switch cmd {
+	case "/nick":
+		s.nick(c, args)
+
+	case "/join":
+		name := strings.TrimPrefix(args, "#")
+		switch {
+		case !validName(name):
+			s.notify(c, "* usage: /join room")
+		case name == c.room.name:
+			s.notify(c, "* you are already in #%s", name)
+		default:
+			s.exit(c, "left")
+			s.enter(c, name)
+		}
+
+	case "/leave":
+		if c.room.name == Lobby {
+			s.notify(c, "* you are already in #%s", Lobby)
+			return
+		}
+		s.exit(c, "left")
+		s.enter(c, Lobby)
+
+	case "/who":
+		names := make([]string, 0, len(c.room.clients))
+		for rc := range c.room.clients {
+			names = append(names, rc.name)
+		}
+		sort.Strings(names)
+		s.notify(c, "* users in #%s: %s", c.room.name, strings.Join(names, ", "))
+
+	case "/msg":
+		name, text, _ := strings.Cut(args, " ")
+		to, ok := s.clients[name]
+		switch {
+		case name == "" || text == "":
+			s.notify(c, "* usage: /msg name text")
+		case !ok:
+			s.notify(c, "* no user named %s", name)
+		default:
+			s.notify(to, "[%s] %s", c.name, text)
+		}
+
+	default:
+		s.notify(c, "* unknown command %s", cmd)
+	}
+}
+
+// nick changes the name of the client.
+func (s *Server) nick(c *client, name string) {
+	switch {
+	case !validName(name):
+		s.notify(c, "* usage: /nick name")
+		return
+	case s.clients[name] != nil:
+		s.notify(c, "* the name %s is taken", name)
+		return
+	}
+
+	old := c.name
+	delete(s.clients, old)
+	c.name = name
+	s.clients[name] = c
+
+	s.sendGroupMessage(c.room, c, fmt.Sprintf("* %s is now known as %s", old, name))
+	s.notify(c, "* you are now known as %s", name)
+}
+
+// validName reports whether the name can be used for a user or room.
+// Spaces and control characters are not allowed.
+func validName(name string) bool {
+	invalid := func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsControl(r) || strings.ContainsRune("#/:*[]", r)
+	}
+
+	return name != "" && strings.IndexFunc(name, invalid) < 0
+}
+
+// notify sends a line to a single client.
+func (s *Server) notify(c *client, format string, args ...any) {
+	if !c.send(fmt.Sprintf(format, args...)) {
+		log.Printf("Dropping slow client: %s", c.name)
+		s.drop(c, "was dropped from")
+	}
+}
+
+// sendGroupMessage sends a line to all clients in the room except the
+// one that sent it. A client that can't keep up is dropped.
+func (s *Server) sendGroupMessage(r *Room, from *client, line string) {
+	var slow []*client
+	for c := range r.clients {
+		if c != from && !c.send(line) {
+			slow = append(slow, c)
+		}
+	}
+
+	for _, c := range slow {
+		log.Printf("Dropping slow client: %s", c.name)
+		s.drop(c, "was dropped from")
+	}
 }
@@ -0,0 +1,319 @@
+package chat_test
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/gotraining/topics/go/concurrency/patterns/chat"
+)
+
+const succeed = "\u2713"
+const failed = "\u2717"
+
+// pipeListener is a listener that hands out connections made by net.Pipe.
+type pipeListener struct {
+	conns chan net.Conn
+	done  chan struct{}
+}
+
+// newPipeListener returns a listener ready to accept connections.
+func newPipeListener() *pipeListener {
+	return &pipeListener{
+		conns: make(chan net.Conn),
+		done:  make(chan struct{}),
+	}
+}
+
+// Accept implements the net.Listener interface.
+func (pl *pipeListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-pl.conns:
+		return conn, nil
+	case <-pl.done:
+		return nil, net.ErrClosed
+	}
+}
+
+// Close implements the net.Listener interface.
+func (pl *pipeListener) Close() error {
+	close(pl.done)
+	return nil
+}
+
+// Addr implements the net.Listener interface.
+func (pl *pipeListener) Addr() net.Addr {
+	return pipeAddr{}
+}
+
+// pipeAddr is the address of a pipeListener.
+type pipeAddr struct{}
+
+func (pipeAddr) Network() string { return "pipe" }
+func (pipeAddr) String() string  { return "pipe" }
+
+// testClient is a user connected to the server.
+type testClient struct {
+	conn  net.Conn
+	lines chan string
+}
+
+// dial connects a new user to the server.
+func (pl *pipeListener) dial() *testClient {
+	server, conn := net.Pipe()
+	pl.conns <- server
+
+	tc := testClient{
+		conn:  conn,
+		lines: make(chan string, 100),
+	}
+
+	go func() {
+		defer close(tc.lines)
+		r := bufio.NewReader(conn)
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			tc.lines <- strings.TrimSuffix(line, "\n")
+		}
+	}()
+
+	return &tc
+}
+
+// send sends a line to the server.
+func (tc *testClient) send(line string) error {
+	tc.conn.SetWriteDeadline(time.Now().Add(time.Second))
+	_, err := tc.conn.Write([]byte(line + "\n"))
+	return err
+}
+
+// next returns the next line from the server.
+func (tc *testClient) next() (string, error) {
+	select {
+	case line, ok := <-tc.lines:
+		if !ok {
+			return "", errors.New("connection closed")
+		}
+		return line, nil
+	case <-time.After(time.Second):
+		return "", errors.New("timeout")
+	}
+}
+
+// want is a line a user should receive.
+type want struct {
+	to   int
+	line string
+}
+
+// receive checks each user receives their lines in order.
+func receive(t *testing.T, clients []*testClient, wants []want) {
+	t.Helper()
+
+	for _, w := range wants {
+		line, err := clients[w.to].next()
+		if err != nil || line != w.line {
+			t.Fatalf("\t%s\tShould receive %q as user %d : got %q %v", failed, w.line, w.to, line, err)
+		}
+	}
+}
+
+func TestServer(t *testing.T) {
+	pl := newPipeListener()
+	s := chat.NewServer(pl)
+	defer s.Close()
+
+	t.Log("Given the need to chat in rooms.")
+	{
+		var clients []*testClient
+
+		t.Logf("\tTest 0:\tWhen users connect.")
+		{
+			for i, name := range []string{"guest1", "guest2", "guest3"} {
+				clients = append(clients, pl.dial())
+				wants := []want{
+					{i, "* welcome " + name + ", type /nick to change your name"},
+					{i, "* you are in #lobby"},
+				}
+				for j := 0; j < i; j++ {
+					wants = append(wants, want{j, "* " + name + " joined #lobby"})
+				}
+				receive(t, clients, wants)
+			}
+			t.Logf("\t%s\tTest 0:\tShould welcome each user and tell the lobby.", succeed)
+		}
+
+		steps := []struct {
+			from  int
+			send  string
+			wants []want
+		}{
+			{0, "/nick bill", []want{{0, "* you are now known as bill"}, {1, "* guest1 is now known as bill"}, {2, "* guest1 is now known as bill"}}},
+			{1, "/nick bill", []want{{1, "* the name bill is taken"}}},
+			{1, "/nick a b", []want{{1, "* usage: /nick name"}}},
+			{1, "/nick a\x1bb", []want{{1, "* usage: /nick name"}}},
+			{1, "/nick a\rb", []want{{1, "* usage: /nick name"}}},
+			{1, "hello", []want{{0, "guest2: hello"}, {2, "guest2: hello"}}},
+			{0, "/who", []want{{0, "* users in #lobby: bill, guest2, guest3"}}},
+			{2, "/join go", []want{{0, "* guest3 left #lobby"}, {1, "* guest3 left #lobby"}, {2, "* you are in #go"}}},
+			{0, "/join #go", []want{{1, "* bill left #lobby"}, {2, "* bill joined #go"}, {0, "* you are in #go"}}},
+			{0, "/join go", []want{{0, "* you are already in #go"}}},
+			{0, "in go", []want{{2, "bill: in go"}}},
+			{2, "/who", []want{{2, "* users in #go: bill, guest3"}}},
+			{1, "/msg guest3 psst, hi", []want{{2, "[guest2] psst, hi"}}},
+			{1, "/msg nobody hi", []want{{1, "* no user named nobody"}}},
+			{1, "/msg bill", []want{{1, "* usage: /msg name text"}}},
+			{1, "/leave", []want{{1, "* you are already in #lobby"}}},
+			{2, "/leave", []want{{0, "* guest3 left #go"}, {1, "* guest3 joined #lobby"}, {2, "* you are in #lobby"}}},
+			{0, "/frob", []want{{0, "* unknown command /frob"}}},
+			{2, "/who", []want{{2, "* users in #lobby: guest2, guest3"}}},
+		}
+
+		for i, step := range steps {
+			testID := i + 1
+			t.Logf("\tTest %d:\tWhen user %d sends %q.", testID, step.from, step.send)
+			{
+				if err := clients[step.from].send(step.send); err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to send : %v", failed, testID, err)
+				}
+				receive(t, clients, step.wants)
+				t.Logf("\t%s\tTest %d:\tShould receive the expected lines.", succeed, testID)
+			}
+		}
+
+		testID := len(steps) + 1
+		t.Logf("\tTest %d:\tWhen a user disconnects.", testID)
+		{
+			clients[1].send("/join go")
+			receive(t, clients, []want{{2, "* guest2 left #lobby"}, {0, "* guest2 joined #go"}, {1, "* you are in #go"}})
+
+			clients[0].conn.Close()
+			receive(t, clients, []want{{1, "* bill left #go"}})
+			t.Logf("\t%s\tTest %d:\tShould tell the room.", succeed, testID)
+
+			clients[1].send("/msg bill hi")
+			receive(t, clients, []want{{1, "* no user named bill"}})
+			t.Logf("\t%s\tTest %d:\tShould remove the user.", succeed, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen the server is closed.", testID)
+		{
+			s.Close()
+			for i := 1; i < len(clients); i++ {
+				if line, err := clients[i].next(); err == nil {
+					t.Fatalf("\t%s\tTest %d:\tShould close the connections : got %q", failed, testID, line)
+				}
+			}
+			t.Logf("\t%s\tTest %d:\tShould close the connections.", succeed, testID)
+		}
+	}
+}
+
+func TestSlowClient(t *testing.T) {
+	pl := newPipeListener()
+	s := chat.NewServer(pl, chat.WithQueueSize(4))
+	defer s.Close()
+
+	t.Log("Given the need to keep a slow user from holding up the room.")
+	{
+		fast := pl.dial()
+		receive(t, []*testClient{fast}, []want{
+			{0, "* welcome guest1, type /nick to change your name"},
+			{0, "* you are in #lobby"},
+		})
+
+		// The slow user never reads, so lines pile up in its queue.
+		server, conn := net.Pipe()
+		pl.conns <- server
+		receive(t, []*testClient{fast}, []want{{0, "* guest2 joined #lobby"}})
+
+		other := pl.dial()
+		clients := []*testClient{fast, other}
+		receive(t, clients, []want{
+			{1, "* welcome guest3, type /nick to change your name"},
+			{1, "* you are in #lobby"},
+			{0, "* guest3 joined #lobby"},
+		})
+
+		t.Logf("\tTest 0:\tWhen the queue of the slow user fills up.")
+		{
+			// The other user keeps up, receiving every message and the
+			// notice the slow user was dropped. The notice comes before
+			// or after the message that didn't fit, depending on which
+			// of them got the message first.
+			var notices int
+			for i := 0; i < 10; i++ {
+				if err := fast.send("message"); err != nil {
+					t.Fatalf("\t%s\tTest 0:\tShould keep accepting messages : %v", failed, err)
+				}
+
+				line, err := other.next()
+				if line == "* guest2 was dropped from #lobby" {
+					notices++
+					line, err = other.next()
+				}
+				if err != nil || line != "guest1: message" {
+					t.Fatalf("\t%s\tTest 0:\tShould receive every message : got %q %v", failed, line, err)
+				}
+			}
+			if notices == 0 {
+				receive(t, clients, []want{{1, "* guest2 was dropped from #lobby"}})
+			}
+			receive(t, clients, []want{{0, "* guest2 was dropped from #lobby"}})
+			t.Logf("\t%s\tTest 0:\tShould drop the slow user and keep the room going.", succeed)
+
+			conn.SetReadDeadline(time.Now().Add(time.Second))
+			r := bufio.NewReader(conn)
+			for {
+				if _, err := r.ReadString('\n'); err != nil {
+					if errors.Is(err, os.ErrDeadlineExceeded) {
+						t.Fatalf("\t%s\tTest 0:\tShould close the connection of the slow user : %v", failed, err)
+					}
+					break
+				}
+			}
+			t.Logf("\t%s\tTest 0:\tShould close the connection of the slow user.", succeed)
+		}
+	}
+}
+
+func TestQueueSize(t *testing.T) {
+	t.Log("Given the need to use a queue that is too small.")
+	{
+		for testID, size := range []int{-1, 0, 1} {
+			t.Logf("\tTest %d:\tWhen the queue size is %d.", testID, size)
+			{
+				pl := newPipeListener()
+				s := chat.NewServer(pl, chat.WithQueueSize(size))
+
+				clients := []*testClient{pl.dial()}
+				receive(t, clients, []want{
+					{0, "* welcome guest1, type /nick to change your name"},
+					{0, "* you are in #lobby"},
+				})
+
+				clients = append(clients, pl.dial())
+				receive(t, clients, []want{
+					{1, "* welcome guest2, type /nick to change your name"},
+					{1, "* you are in #lobby"},
+					{0, "* guest2 joined #lobby"},
+				})
+				t.Logf("\t%s\tTest %d:\tShould let users join.", succeed, testID)
+
+				clients[1].send("/who")
+				receive(t, clients, []want{{1, "* users in #lobby: guest1, guest2"}})
+				t.Logf("\t%s\tTest %d:\tShould keep the users in the lobby.", succeed, testID)
+
+				s.Close()
+			}
+		}
+	}
+}
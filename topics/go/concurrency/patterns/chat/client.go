@@ -0,0 +1,166 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package chat
+
+import (
+	"bufio"
+	"log"
+	"net"
+	"strings"
+	"sync"
+)
+
+// transport reads and writes the lines of text exchanged with a user.
+type transport interface {
+	ReadLine() (string, error)
+	WriteLine(line string) error
+	Close() error
+}
+
+// lineConn is a transport for a network connection that sends lines of
+// text ending in a newline.
+type lineConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	writer *bufio.Writer
+}
+
+// newLineConn returns a transport for the connection.
+func newLineConn(conn net.Conn) *lineConn {
+	return &lineConn{
+		conn:   conn,
+		reader: bufio.NewReader(conn),
+		writer: bufio.NewWriter(conn),
+	}
+}
+
+// ReadLine waits for a line to arrive and returns it without the
+// line ending.
+func (lc *lineConn) ReadLine() (string, error) {
+	line, err := lc.reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// WriteLine sends the line followed by a newline.
+func (lc *lineConn) WriteLine(line string) error {
+	lc.writer.WriteString(line)
+	lc.writer.WriteByte('\n')
+	return lc.writer.Flush()
+}
+
+// Close closes the connection.
+func (lc *lineConn) Close() error {
+	return lc.conn.Close()
+}
+
+// message is the data received from a user in the chatroom.
+type message struct {
+	data   string
+	client *client
+}
+
+// client represents a single connection in the server. The name, room
+// and closed fields are only used by the server processing goroutine.
+type client struct {
+	name   string
+	room   *Room
+	closed bool
+
+	server   *Server
+	conn     transport
+	outgoing chan string // Lines waiting to be written.
+	wg       sync.WaitGroup
+}
+
+// newClient create a new client for an incoming connection.
+func newClient(s *Server, conn transport, name string, queueSize int) *client {
+	c := client{
+		name:     name,
+		server:   s,
+		conn:     conn,
+		outgoing: make(chan string, queueSize),
+	}
+
+	c.wg.Add(2)
+	go c.read()
+	go c.write()
+
+	return &c
+}
+
+// read waits for message and sends it to the server for processing.
+func (c *client) read() {
+	defer c.wg.Done()
+
+	for {
+
+		// Wait for a message to arrive.
+		line, err := c.conn.ReadLine()
+		if err != nil {
+			log.Printf("read-routine: %s leaving chat: %v", c.name, err)
+			select {
+			case c.server.leaving <- c:
+			case <-c.server.shutdown:
+			}
+			return
+		}
+
+		select {
+		case c.server.incoming <- message{data: line, client: c}:
+		case <-c.server.shutdown:
+			return
+		}
+	}
+}
+
+// write is a goroutine to handle processing outgoing
+// messages to this client.
+func (c *client) write() {
+	defer c.wg.Done()
+
+	for line := range c.outgoing {
+		if err := c.conn.WriteLine(line); err != nil {
+
+			// Closing the connection makes the read goroutine
+			// report the client is leaving.
+			c.conn.Close()
+
+			// Keep receiving so the server never blocks.
+			for range c.outgoing {
+			}
+			return
+		}
+	}
+}
+
+// send queues the line to be written to the client. It reports false
+// when the queue is full, so a slow client can't hold up the server.
+func (c *client) send(line string) bool {
+	if c.closed {
+		return true
+	}
+
+	select {
+	case c.outgoing <- line:
+		return true
+	default:
+		return false
+	}
+}
+
+// drop closes the client connection, which makes the read and write
+// goroutines terminate. Lines still in the queue are thrown away.
+func (c *client) drop() {
+	if c.closed {
+		return
+	}
+	c.closed = true
+
+	close(c.outgoing)
+	c.conn.Close()
+}
@@ -8,6 +8,8 @@ package main
 
 import (
 	"log"
+	"net"
+	"net/http"
 	"os"
 	"os/signal"
 
@@ -15,7 +17,18 @@ import (
 )
 
 func main() {
-	cr := chat.New()
+	listener, err := net.Listen("tcp", ":6000")
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	cr := chat.NewServer(listener)
+
+	// Let browsers join the same rooms over a WebSocket.
+	go func() {
+		http.Handle("/chat", cr)
+		log.Println(http.ListenAndServe(":8080", nil))
+	}()
 
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt)
@@ -0,0 +1,421 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package chat
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// websocketGUID is appended to the key sent by the browser to compute
+// the accept value of the handshake.
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// maxMessageSize is the largest message accepted from a browser.
+const maxMessageSize = 64 << 10
+
+// Set of frame opcodes from RFC 6455 section 5.2.
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+)
+
+// Set of close status codes from RFC 6455 section 7.4.1.
+const (
+	closeNormal      = 1000
+	closeProtocol    = 1002
+	closeUnsupported = 1003
+	closeInvalidData = 1007
+	closeTooBig      = 1009
+)
+
+// closeWriteTimeout is how long sending a close frame can take.
+const closeWriteTimeout = time.Second
+
+// Set of errors returned when a browser breaks the framing rules or
+// sends a message larger than maxMessageSize.
+var (
+	errProtocol = errors.New("websocket protocol error")
+	errTooBig   = errors.New("websocket message too big")
+)
+
+// ServeHTTP upgrades the request to a WebSocket connection and adds the
+// user to the lobby, sharing the rooms with the users connected over
+// TCP. Each text message is a line of the chat.
+//
+//	http.Handle("/chat", server)
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := r.Header.Get("Sec-Websocket-Key")
+
+	switch {
+	case r.Method != http.MethodGet:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+
+	case !headerContains(r.Header, "Connection", "upgrade") || !headerContains(r.Header, "Upgrade", "websocket"):
+		http.Error(w, "websocket upgrade required", http.StatusBadRequest)
+		return
+
+	case r.Header.Get("Sec-Websocket-Version") != "13":
+		w.Header().Set("Sec-Websocket-Version", "13")
+		http.Error(w, "unsupported websocket version", http.StatusUpgradeRequired)
+		return
+	}
+
+	if k, err := base64.StdEncoding.DecodeString(key); err != nil || len(k) != 16 {
+		http.Error(w, "invalid websocket key", http.StatusBadRequest)
+		return
+	}
+
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "websocket not supported", http.StatusInternalServerError)
+		return
+	}
+
+	// Take over the connection from the http server.
+	conn, rw, err := hj.Hijack()
+	if err != nil {
+		return
+	}
+
+	// Clear any deadlines set by the http server, since the
+	// connection stays open for as long as the user is chatting.
+	conn.SetDeadline(time.Time{})
+
+	rw.WriteString("HTTP/1.1 101 Switching Protocols\r\n")
+	rw.WriteString("Upgrade: websocket\r\n")
+	rw.WriteString("Connection: Upgrade\r\n")
+	rw.WriteString("Sec-WebSocket-Accept: " + acceptKey(key) + "\r\n\r\n")
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return
+	}
+
+	s.serve(newWSConn(conn, rw.Reader))
+}
+
+// headerContains reports whether the comma separated values of the
+// header contain the token, ignoring case.
+func headerContains(h http.Header, name string, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// acceptKey computes the value of the Sec-WebSocket-Accept header.
+func acceptKey(key string) string {
+	h := sha1.Sum([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(h[:])
+}
+
+// wsConn is a transport for a WebSocket connection that sends each line
+// of text as a text message.
+type wsConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	lines  []string // Lines of a message not returned yet.
+
+	// Secure writes, since the read goroutine answers pings and close
+	// frames while the write goroutine sends messages.
+	mu     sync.Mutex
+	writer *bufio.Writer
+
+	once sync.Once
+}
+
+// newWSConn returns a transport for the upgraded connection. The reader
+// may hold data the browser sent right after the handshake.
+func newWSConn(conn net.Conn, reader *bufio.Reader) *wsConn {
+	return &wsConn{
+		conn:   conn,
+		reader: reader,
+		writer: bufio.NewWriter(conn),
+	}
+}
+
+// ReadLine waits for a text message to arrive and returns it without a
+// line ending. A message holding more than one line is returned one line
+// at a time, so a line break can't be used to forge a line from the
+// server. Control frames are handled along the way.
+func (ws *wsConn) ReadLine() (string, error) {
+	if len(ws.lines) > 0 {
+		line := ws.lines[0]
+		ws.lines = ws.lines[1:]
+		return line, nil
+	}
+
+	var msg []byte
+	fragmented := false
+
+	for {
+		fin, op, payload, err := ws.readFrame()
+		if err != nil {
+			switch {
+			case errors.Is(err, errProtocol):
+				ws.closeWith(closeProtocol)
+			case errors.Is(err, errTooBig):
+				ws.closeWith(closeTooBig)
+			}
+			return "", err
+		}
+
+		switch op {
+		case opPing:
+			if err := ws.writeFrame(opPong, payload); err != nil {
+				return "", err
+			}
+			continue
+
+		case opPong:
+			continue
+
+		case opClose:
+
+			// Echo the status code back and finish the connection.
+			code, err := closeCode(payload)
+			if err != nil {
+				ws.closeWith(closeProtocol)
+				return "", err
+			}
+			ws.closeWith(code)
+			return "", io.EOF
+
+		case opBinary:
+			ws.closeWith(closeUnsupported)
+			return "", errors.New("websocket binary messages are not supported")
+
+		case opText:
+			if fragmented {
+				ws.closeWith(closeProtocol)
+				return "", errProtocol
+			}
+			msg = append(msg[:0], payload...)
+
+		case opContinuation:
+			if !fragmented {
+				ws.closeWith(closeProtocol)
+				return "", errProtocol
+			}
+			msg = append(msg, payload...)
+		}
+
+		if len(msg) > maxMessageSize {
+			ws.closeWith(closeTooBig)
+			return "", errTooBig
+		}
+
+		if !fin {
+			fragmented = true
+			continue
+		}
+
+		if !utf8.Valid(msg) {
+			ws.closeWith(closeInvalidData)
+			return "", errors.New("websocket message is not valid utf-8")
+		}
+
+		// Any carriage return or newline ends a line. Empty lines are
+		// ignored by the server anyway.
+		lines := strings.FieldsFunc(string(msg), func(r rune) bool {
+			return r == '\r' || r == '\n'
+		})
+		if len(lines) == 0 {
+			return "", nil
+		}
+
+		ws.lines = lines[1:]
+		return lines[0], nil
+	}
+}
+
+// closeCode returns the status code to send back for the payload of a
+// close frame. Codes that can't be sent in a close frame are answered
+// with a normal closure.
+func closeCode(payload []byte) (int, error) {
+	switch len(payload) {
+	case 0:
+		return closeNormal, nil
+	case 1:
+		return 0, errProtocol
+	}
+
+	switch code := int(binary.BigEndian.Uint16(payload)); {
+	case code < 1000, code >= 5000:
+		return closeNormal, nil
+	case code == 1004, code == 1005, code == 1006, code == 1015:
+		return closeNormal, nil
+	default:
+		return code, nil
+	}
+}
+
+// readFrame reads a single frame and removes the mask from the payload.
+//
+//	 0                   1                   2                   3
+//	 0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1 2 3 4 5 6 7 8 9 0 1
+//	+-+-+-+-+-------+-+-------------+-------------------------------+
+//	|F|R|R|R| opcode|M| Payload len |    Extended payload length    |
+//	|I|S|S|S|  (4)  |A|     (7)     |             (16/64)           |
+//	|N|V|V|V|       |S|             |   (if payload len==126/127)   |
+//	| |1|2|3|       |K|             |                               |
+//	+-+-+-+-+-------+-+-------------+ - - - - - - - - - - - - - - - +
+//	|     Extended payload length continued, if payload len == 127  |
+//	+ - - - - - - - - - - - - - - - +-------------------------------+
+//	|                               |Masking-key, if MASK set to 1  |
+//	+-------------------------------+-------------------------------+
+//	| Masking-key (continued)       |          Payload Data         |
+//	+-------------------------------- - - - - - - - - - - - - - - -+
+func (ws *wsConn) readFrame() (fin bool, op byte, payload []byte, err error) {
+	var header [2]byte
+	if _, err := io.ReadFull(ws.reader, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+
+	fin = header[0]&0x80 != 0
+	op = header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+	size := uint64(header[1] & 0x7F)
+
+	// No extensions are negotiated, so the reserved bits must be clear,
+	// and a browser must mask every frame.
+	if header[0]&0x70 != 0 || !masked {
+		return false, 0, nil, errProtocol
+	}
+
+	switch op {
+	case opContinuation, opText, opBinary:
+	case opClose, opPing, opPong:
+
+		// Control frames can't be fragmented or carry more than 125
+		// bytes.
+		if !fin || size > 125 {
+			return false, 0, nil, errProtocol
+		}
+	default:
+		return false, 0, nil, errProtocol
+	}
+
+	switch size {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(ws.reader, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		size = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(ws.reader, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		size = binary.BigEndian.Uint64(ext[:])
+	}
+
+	if size > maxMessageSize {
+		return false, 0, nil, errTooBig
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(ws.reader, mask[:]); err != nil {
+		return false, 0, nil, err
+	}
+
+	payload = make([]byte, size)
+	if _, err := io.ReadFull(ws.reader, payload); err != nil {
+		return false, 0, nil, err
+	}
+
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+
+	return fin, op, payload, nil
+}
+
+// WriteLine sends the line as a text message.
+func (ws *wsConn) WriteLine(line string) error {
+	return ws.writeFrame(opText, []byte(line))
+}
+
+// writeFrame sends the payload in a single frame. Frames sent by the
+// server are not masked.
+func (ws *wsConn) writeFrame(op byte, payload []byte) error {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	return ws.writeFrameLocked(op, payload)
+}
+
+// writeFrameLocked sends the frame while the caller holds the mutex.
+func (ws *wsConn) writeFrameLocked(op byte, payload []byte) error {
+	ws.writer.WriteByte(0x80 | op)
+
+	switch n := len(payload); {
+	case n <= 125:
+		ws.writer.WriteByte(byte(n))
+	case n <= 0xFFFF:
+		var ext [3]byte
+		ext[0] = 126
+		binary.BigEndian.PutUint16(ext[1:], uint16(n))
+		ws.writer.Write(ext[:])
+	default:
+		var ext [9]byte
+		ext[0] = 127
+		binary.BigEndian.PutUint64(ext[1:], uint64(n))
+		ws.writer.Write(ext[:])
+	}
+
+	ws.writer.Write(payload)
+	return ws.writer.Flush()
+}
+
+// closeWith sends a close frame with the status code and closes the
+// connection.
+func (ws *wsConn) closeWith(code int) {
+	ws.once.Do(func() {
+
+		// Skip the close frame when a write is in progress, so a browser
+		// that stopped reading can't hold up closing the connection.
+		if ws.mu.TryLock() {
+			var payload [2]byte
+			binary.BigEndian.PutUint16(payload[:], uint16(code))
+
+			ws.conn.SetWriteDeadline(time.Now().Add(closeWriteTimeout))
+			ws.writeFrameLocked(opClose, payload[:])
+			ws.mu.Unlock()
+		}
+
+		ws.conn.Close()
+	})
+}
+
+// Close sends a close frame and closes the connection. The server
+// processing goroutine calls Close, so the close frame is sent from
+// another goroutine and a browser that stopped reading can't hold up the
+// server. The read goroutine of the client returns once the connection
+// is closed.
+func (ws *wsConn) Close() error {
+	go ws.closeWith(closeNormal)
+	return nil
+}
@@ -0,0 +1,402 @@
+package chat_test
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/gotraining/topics/go/concurrency/patterns/chat"
+)
+
+// Set of frame opcodes used by the tests.
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+)
+
+// wsClient is a user connected to the server with a WebSocket.
+type wsClient struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// dialWS connects to the server and performs the handshake, using the
+// key from the example in RFC 6455.
+func dialWS(hs *httptest.Server) (*wsClient, error) {
+	conn, err := net.Dial("tcp", hs.Listener.Addr().String())
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(conn, "GET /chat HTTP/1.1\r\n"+
+		"Host: %s\r\n"+
+		"Upgrade: websocket\r\n"+
+		"Connection: keep-alive, Upgrade\r\n"+
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n"+
+		"Sec-WebSocket-Version: 13\r\n\r\n", hs.Listener.Addr())
+
+	ws := wsClient{
+		conn:   conn,
+		reader: bufio.NewReader(conn),
+	}
+
+	resp, err := http.ReadResponse(ws.reader, nil)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		conn.Close()
+		return nil, fmt.Errorf("status %d", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Sec-WebSocket-Accept"); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		conn.Close()
+		return nil, fmt.Errorf("accept %q", got)
+	}
+
+	return &ws, nil
+}
+
+// writeFrame sends a frame, masking it like a browser would unless
+// masked is false.
+func (ws *wsClient) writeFrame(fin bool, op byte, payload []byte, masked bool) error {
+	var frame []byte
+
+	b := op
+	if fin {
+		b |= 0x80
+	}
+	frame = append(frame, b)
+
+	var mask byte
+	if masked {
+		mask = 0x80
+	}
+
+	switch n := len(payload); {
+	case n <= 125:
+		frame = append(frame, mask|byte(n))
+	default:
+		frame = append(frame, mask|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	}
+
+	if !masked {
+		frame = append(frame, payload...)
+	} else {
+		key := [4]byte{0x37, 0xfa, 0x21, 0x3d}
+		frame = append(frame, key[:]...)
+		for i, b := range payload {
+			frame = append(frame, b^key[i%4])
+		}
+	}
+
+	ws.conn.SetWriteDeadline(time.Now().Add(time.Second))
+	_, err := ws.conn.Write(frame)
+	return err
+}
+
+// readFrame returns the opcode and payload of the next frame.
+func (ws *wsClient) readFrame() (byte, []byte, error) {
+	ws.conn.SetReadDeadline(time.Now().Add(time.Second))
+
+	var header [2]byte
+	if _, err := io.ReadFull(ws.reader, header[:]); err != nil {
+		return 0, nil, err
+	}
+	if header[0]&0x80 == 0 || header[1]&0x80 != 0 {
+		return 0, nil, errors.New("server frames must be final and not masked")
+	}
+
+	size := int(header[1] & 0x7F)
+	if size == 126 {
+		var ext [2]byte
+		if _, err := io.ReadFull(ws.reader, ext[:]); err != nil {
+			return 0, nil, err
+		}
+		size = int(binary.BigEndian.Uint16(ext[:]))
+	}
+
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(ws.reader, payload); err != nil {
+		return 0, nil, err
+	}
+
+	return header[0] & 0x0F, payload, nil
+}
+
+// next returns the next text message.
+func (ws *wsClient) next() (string, error) {
+	op, payload, err := ws.readFrame()
+	if err != nil {
+		return "", err
+	}
+	if op != opText {
+		return "", fmt.Errorf("opcode %d", op)
+	}
+
+	return string(payload), nil
+}
+
+// closeCode returns the status code of the next frame, which should be
+// a close frame.
+func (ws *wsClient) closeCode() (int, error) {
+	op, payload, err := ws.readFrame()
+	if err != nil {
+		return 0, err
+	}
+	if op != opClose || len(payload) < 2 {
+		return 0, fmt.Errorf("opcode %d payload %q", op, payload)
+	}
+
+	return int(binary.BigEndian.Uint16(payload)), nil
+}
+
+func TestWebSocketHandshake(t *testing.T) {
+	pl := newPipeListener()
+	s := chat.NewServer(pl)
+	defer s.Close()
+
+	hs := httptest.NewServer(s)
+	defer hs.Close()
+
+	tt := []struct {
+		name    string
+		method  string
+		headers map[string]string
+		status  int
+	}{
+		{"plain request", "GET", nil, http.StatusBadRequest},
+		{"post", "POST", map[string]string{"Connection": "Upgrade", "Upgrade": "websocket"}, http.StatusMethodNotAllowed},
+		{"old version", "GET", map[string]string{"Connection": "Upgrade", "Upgrade": "websocket", "Sec-WebSocket-Version": "8"}, http.StatusUpgradeRequired},
+		{"bad key", "GET", map[string]string{"Connection": "Upgrade", "Upgrade": "websocket", "Sec-WebSocket-Version": "13", "Sec-WebSocket-Key": "short"}, http.StatusBadRequest},
+	}
+
+	t.Log("Given the need to reject requests that are not WebSocket handshakes.")
+	{
+		for testID, test := range tt {
+			t.Logf("\tTest %d:\tWhen sending a %s.", testID, test.name)
+			{
+				req, _ := http.NewRequest(test.method, hs.URL+"/chat", nil)
+				for k, v := range test.headers {
+					req.Header.Set(k, v)
+				}
+
+				resp, err := http.DefaultClient.Do(req)
+				if err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to send the request : %v", failed, testID, err)
+				}
+				resp.Body.Close()
+
+				if resp.StatusCode != test.status {
+					t.Fatalf("\t%s\tTest %d:\tShould receive a %d status : %d", failed, testID, test.status, resp.StatusCode)
+				}
+				t.Logf("\t%s\tTest %d:\tShould receive a %d status.", succeed, testID, test.status)
+			}
+		}
+	}
+}
+
+func TestWebSocket(t *testing.T) {
+	pl := newPipeListener()
+	s := chat.NewServer(pl)
+	defer s.Close()
+
+	hs := httptest.NewServer(s)
+	defer hs.Close()
+
+	t.Log("Given the need to chat from a browser.")
+	{
+		tcp := pl.dial()
+		tcps := []*testClient{tcp}
+		receive(t, tcps, []want{
+			{0, "* welcome guest1, type /nick to change your name"},
+			{0, "* you are in #lobby"},
+		})
+
+		// expect checks the WebSocket user receives the lines in order.
+		expect := func(testID int, ws *wsClient, lines ...string) {
+			t.Helper()
+			for _, line := range lines {
+				got, err := ws.next()
+				if err != nil || got != line {
+					t.Fatalf("\t%s\tTest %d:\tShould receive %q : got %q %v", failed, testID, line, got, err)
+				}
+			}
+		}
+
+		t.Logf("\tTest 0:\tWhen a browser connects.")
+		{
+			ws, err := dialWS(hs)
+			if err != nil {
+				t.Fatalf("\t%s\tTest 0:\tShould complete the handshake : %v", failed, err)
+			}
+			defer ws.conn.Close()
+			t.Logf("\t%s\tTest 0:\tShould complete the handshake.", succeed)
+
+			expect(0, ws, "* welcome guest2, type /nick to change your name", "* you are in #lobby")
+			receive(t, tcps, []want{{0, "* guest2 joined #lobby"}})
+			t.Logf("\t%s\tTest 0:\tShould join the lobby with the TCP users.", succeed)
+
+			t.Logf("\tTest 1:\tWhen messages are exchanged.")
+			{
+				ws.writeFrame(true, opText, []byte("hello"), true)
+				receive(t, tcps, []want{{0, "guest2: hello"}})
+				t.Logf("\t%s\tTest 1:\tShould send a message to the TCP user.", succeed)
+
+				tcp.send("hi there")
+				expect(1, ws, "guest1: hi there")
+				t.Logf("\t%s\tTest 1:\tShould receive a message from the TCP user.", succeed)
+
+				long := strings.Repeat("go ", 100)
+				tcp.send(long)
+				expect(1, ws, "guest1: "+long)
+				ws.writeFrame(true, opText, []byte(long), true)
+				receive(t, tcps, []want{{0, "guest2: " + long}})
+				t.Logf("\t%s\tTest 1:\tShould exchange messages longer than 125 bytes.", succeed)
+			}
+
+			t.Logf("\tTest 2:\tWhen a message is fragmented around a ping.")
+			{
+				ws.writeFrame(false, opText, []byte("/join "), true)
+				ws.writeFrame(true, opPing, []byte("are you there"), true)
+				ws.writeFrame(true, opContinuation, []byte("go\r\n"), true)
+
+				op, payload, err := ws.readFrame()
+				if err != nil || op != opPong || string(payload) != "are you there" {
+					t.Fatalf("\t%s\tTest 2:\tShould answer the ping : %d %q %v", failed, op, payload, err)
+				}
+				t.Logf("\t%s\tTest 2:\tShould answer the ping.", succeed)
+
+				expect(2, ws, "* you are in #go")
+				receive(t, tcps, []want{{0, "* guest2 left #lobby"}})
+				t.Logf("\t%s\tTest 2:\tShould run the command.", succeed)
+
+				ws.writeFrame(true, opText, []byte("/leave"), true)
+				expect(2, ws, "* you are in #lobby")
+				receive(t, tcps, []want{{0, "* guest2 joined #lobby"}})
+			}
+
+			t.Logf("\tTest 3:\tWhen a message holds more than one line.")
+			{
+				ws.writeFrame(true, opText, []byte("hi\n* fake\r* also fake\r\n"), true)
+				receive(t, tcps, []want{{0, "guest2: hi"}, {0, "guest2: * fake"}, {0, "guest2: * also fake"}})
+				t.Logf("\t%s\tTest 3:\tShould send every line as its own message.", succeed)
+			}
+
+			t.Logf("\tTest 4:\tWhen the browser closes the connection.")
+			{
+				ws.writeFrame(true, opClose, []byte{0x03, 0xE8}, true)
+
+				if code, err := ws.closeCode(); err != nil || code != 1000 {
+					t.Fatalf("\t%s\tTest 4:\tShould echo the close frame : %d %v", failed, code, err)
+				}
+				t.Logf("\t%s\tTest 4:\tShould echo the close frame.", succeed)
+
+				receive(t, tcps, []want{{0, "* guest2 left #lobby"}})
+				t.Logf("\t%s\tTest 4:\tShould remove the user.", succeed)
+			}
+		}
+
+		t.Logf("\tTest 5:\tWhen the browser breaks the protocol.")
+		{
+			ws, err := dialWS(hs)
+			if err != nil {
+				t.Fatalf("\t%s\tTest 5:\tShould complete the handshake : %v", failed, err)
+			}
+			defer ws.conn.Close()
+
+			expect(5, ws, "* welcome guest3, type /nick to change your name", "* you are in #lobby")
+			receive(t, tcps, []want{{0, "* guest3 joined #lobby"}})
+
+			ws.writeFrame(true, opText, []byte("not masked"), false)
+			if code, err := ws.closeCode(); err != nil || code != 1002 {
+				t.Fatalf("\t%s\tTest 5:\tShould close with a protocol error : %d %v", failed, code, err)
+			}
+			t.Logf("\t%s\tTest 5:\tShould close with a protocol error.", succeed)
+
+			receive(t, tcps, []want{{0, "* guest3 left #lobby"}})
+			t.Logf("\t%s\tTest 5:\tShould remove the user.", succeed)
+		}
+
+		t.Logf("\tTest 6:\tWhen the server is closed.")
+		{
+			ws, err := dialWS(hs)
+			if err != nil {
+				t.Fatalf("\t%s\tTest 6:\tShould complete the handshake : %v", failed, err)
+			}
+			defer ws.conn.Close()
+
+			expect(6, ws, "* welcome guest4, type /nick to change your name", "* you are in #lobby")
+
+			s.Close()
+			if code, err := ws.closeCode(); err != nil || code != 1000 {
+				t.Fatalf("\t%s\tTest 6:\tShould send a close frame : %d %v", failed, code, err)
+			}
+			t.Logf("\t%s\tTest 6:\tShould send a close frame.", succeed)
+		}
+	}
+}
+
+func TestWebSocketClose(t *testing.T) {
+	pl := newPipeListener()
+	s := chat.NewServer(pl)
+	defer s.Close()
+
+	hs := httptest.NewServer(s)
+	defer hs.Close()
+
+	tt := []struct {
+		name    string
+		payload []byte
+		code    int
+	}{
+		{"no status code", nil, 1000},
+		{"a one byte payload", []byte{0x03}, 1002},
+		{"an application status code", []byte{0x0B, 0xB8}, 3000},
+		{"a status code below 1000", []byte{0x03, 0xE7}, 1000},
+		{"the no status received code", []byte{0x03, 0xED}, 1000},
+		{"the abnormal closure code", []byte{0x03, 0xEE}, 1000},
+		{"the tls handshake code", []byte{0x03, 0xF7}, 1000},
+	}
+
+	t.Log("Given the need to answer the close frame of a browser.")
+	{
+		for testID, test := range tt {
+			t.Logf("\tTest %d:\tWhen the browser sends %s.", testID, test.name)
+			{
+				ws, err := dialWS(hs)
+				if err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould complete the handshake : %v", failed, testID, err)
+				}
+				defer ws.conn.Close()
+
+				// Skip the welcome lines.
+				for i := 0; i < 2; i++ {
+					if _, err := ws.next(); err != nil {
+						t.Fatalf("\t%s\tTest %d:\tShould be welcomed : %v", failed, testID, err)
+					}
+				}
+
+				ws.writeFrame(true, opClose, test.payload, true)
+				if code, err := ws.closeCode(); err != nil || code != test.code {
+					t.Fatalf("\t%s\tTest %d:\tShould answer with %d : %d %v", failed, testID, test.code, code, err)
+				}
+				t.Logf("\t%s\tTest %d:\tShould answer with %d.", succeed, testID, test.code)
+			}
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+//go:build go1.21
+
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Handler implements the slog.Handler interface on top of a logger, so
+// a slog.Logger writes through the same buffer.
+type Handler struct {
+	l      *Logger
+	prefix string // Group names joined with dots, ending in a dot.
+}
+
+// NewHandler returns a handler that writes through the logger.
+func NewHandler(l *Logger) *Handler {
+	return &Handler{l: l}
+}
+
+// Enabled implements the slog.Handler interface.
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.l.Enabled(Level(level))
+}
+
+// Handle implements the slog.Handler interface.
+func (h *Handler) Handle(_ context.Context, r slog.Record) error {
+	fields := make([]Field, 0, len(h.l.fields)+r.NumAttrs())
+	fields = append(fields, h.l.fields...)
+
+	r.Attrs(func(a slog.Attr) bool {
+		fields = appendAttr(fields, h.prefix, a)
+		return true
+	})
+
+	h.l.enqueue(entry{
+		time:   r.Time,
+		level:  Level(r.Level),
+		msg:    r.Message,
+		fields: fields,
+	})
+
+	return nil
+}
+
+// WithAttrs implements the slog.Handler interface.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	fields := make([]Field, 0, len(h.l.fields)+len(attrs))
+	fields = append(fields, h.l.fields...)
+	for _, a := range attrs {
+		fields = appendAttr(fields, h.prefix, a)
+	}
+
+	return &Handler{
+		l:      &Logger{core: h.l.core, fields: fields},
+		prefix: h.prefix,
+	}
+}
+
+// WithGroup implements the slog.Handler interface.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	return &Handler{
+		l:      h.l,
+		prefix: h.prefix + name + ".",
+	}
+}
+
+// appendAttr converts the attribute to fields. The attributes inside a
+// group are flattened using the group name as a prefix for the keys.
+func appendAttr(fields []Field, prefix string, a slog.Attr) []Field {
+	a.Value = a.Value.Resolve()
+
+	if a.Value.Kind() == slog.KindGroup {
+		attrs := a.Value.Group()
+		if len(attrs) == 0 {
+			return fields
+		}
+
+		// A group without a key is inlined.
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range attrs {
+			fields = appendAttr(fields, prefix, ga)
+		}
+		return fields
+	}
+
+	// Ignore empty attributes like slog does.
+	if a.Equal(slog.Attr{}) {
+		return fields
+	}
+
+	return append(fields, Field{Key: prefix + a.Key, Value: a.Value.Any()})
+}
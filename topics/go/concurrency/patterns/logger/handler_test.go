@@ -0,0 +1,41 @@
+//go:build go1.21
+
+package logger_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/ardanlabs/gotraining/topics/go/concurrency/patterns/logger"
+)
+
+func TestHandler(t *testing.T) {
+	t.Log("Given the need to log through the log/slog package.")
+	{
+		d := newDevice()
+		close(d.open)
+
+		l := logger.New(d, 10, logger.WithLevel(logger.LevelWarn)).With("app", "sales")
+		defer l.Shutdown()
+
+		sl := slog.New(logger.NewHandler(l))
+
+		sl.Info("not logged")
+		sl.Warn("slow", "ms", 120, slog.Group("req", "method", "GET", "path", "/users"))
+		sl.With("id", 7).WithGroup("db").Error("failed", "table", "users", slog.Group("", "retry", true), slog.Attr{})
+
+		if err := l.Flush(context.Background()); err != nil {
+			t.Fatalf("\t%s\tShould be able to flush the logger : %v", failed, err)
+		}
+
+		exp := []string{
+			`WARN slow app=sales ms=120 req.method=GET req.path=/users`,
+			`ERROR failed app=sales id=7 db.table=users db.retry=true`,
+		}
+		if got := d.lines(); !equal(got, exp) {
+			t.Fatalf("\t%s\tShould write the records.\n%q\n%q", failed, got, exp)
+		}
+		t.Logf("\t%s\tShould write the records.", succeed)
+	}
+}
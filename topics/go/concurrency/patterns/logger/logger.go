@@ -3,75 +3,464 @@
 
 // Package logger shows a pattern of using a buffer to handle log write
 // continuity by dealing with write latencies by throwing away log data.
+// Log entries have a level and key/value fields, and the number of
+// entries thrown away is counted so the loss is visible.
 package logger
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"io"
+	"strconv"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
+// Level is the importance of a log entry. The values match the levels
+// used by the log/slog package.
+type Level int
+
+// Set of levels supported by the logger.
+const (
+	LevelDebug Level = -4
+	LevelInfo  Level = 0
+	LevelWarn  Level = 4
+	LevelError Level = 8
+)
+
+// String returns the name of the level. Levels between the named ones
+// are shown as an offset, like INFO+2.
+func (l Level) String() string {
+	name := func(base string, offset Level) string {
+		if offset == 0 {
+			return base
+		}
+		return fmt.Sprintf("%s%+d", base, offset)
+	}
+
+	switch {
+	case l < LevelInfo:
+		return name("DEBUG", l-LevelDebug)
+	case l < LevelWarn:
+		return name("INFO", l-LevelInfo)
+	case l < LevelError:
+		return name("WARN", l-LevelWarn)
+	default:
+		return name("ERROR", l-LevelError)
+	}
+}
+
+// Policy decides what happens to an entry when the buffer is full.
+type Policy int
+
+// Set of overflow policies supported by the logger.
+const (
+	DropNewest Policy = iota // Throw away the entry being logged.
+	DropOldest               // Throw away the oldest entry in the buffer, or the new one without a buffer.
+	Block                    // Wait for room, up to the block timeout.
+)
+
+// Option configures the behavior of a logger.
+type Option func(*options)
+
+// options holds the settings applied by the Option functions.
+type options struct {
+	level   Level
+	policy  Policy
+	timeout time.Duration
+}
+
+// WithLevel sets the minimum level of the entries that are logged.
+// The default is LevelInfo.
+func WithLevel(level Level) Option {
+	return func(o *options) {
+		o.level = level
+	}
+}
+
+// WithOverflow sets the policy used when the buffer is full. The
+// default is DropNewest.
+func WithOverflow(policy Policy) Option {
+	return func(o *options) {
+		o.policy = policy
+	}
+}
+
+// WithBlockTimeout sets how long the Block policy waits for room in the
+// buffer before the entry is thrown away. The default of 0 waits for as
+// long as it takes, or until Shutdown is called.
+func WithBlockTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.timeout = d
+	}
+}
+
+// Field is a key/value pair added to a log entry.
+type Field struct {
+	Key   string
+	Value any
+}
+
+// entry is a log entry waiting to be written. An entry with a flush
+// channel is a marker placed by Flush and is not written.
+type entry struct {
+	time   time.Time
+	level  Level
+	msg    string
+	fields []Field
+	flush  chan struct{}
+}
+
 // Logger provides support to throw log lines away if log
 // writes start to timeout due to latency.
 type Logger struct {
-	write chan string    // Channel to send/recv data to be logged.
-	wg    sync.WaitGroup // Helps control the shutdown.
+	*core
+	fields []Field // Fields added to every entry by this logger.
+}
+
+// core is the state shared by a logger and the loggers created from it
+// with With.
+type core struct {
+	opts    options
+	write   chan entry    // Channel to send/recv data to be logged.
+	stopped chan struct{} // Closed when the write goroutine terminates.
+	dropped uint64        // Number of entries thrown away.
+	errors  uint64        // Number of entries the writer failed to write.
+
+	// Acts as a mutex for sending on the write channel, which can be
+	// given up on when a timeout expires or a context is canceled.
+	send   chan struct{}
+	closed bool
+
+	// Closed when Shutdown is called, so a send waiting for room gives
+	// up its turn.
+	done chan struct{}
+	once sync.Once
 }
 
 // New creates a logger value and initializes it for use. The user can
 // pass the size of the buffer to use for continuity.
-func New(w io.Writer, capacity int) *Logger {
+func New(w io.Writer, capacity int, opts ...Option) *Logger {
 
-	// Create a value of type logger and init the channel
-	// and timer value.
-	l := Logger{
-		write: make(chan string, capacity), // Buffered channel if size > 0.
+	// Create a value of type logger and init the channels.
+	c := core{
+		write:   make(chan entry, capacity), // Buffered channel if size > 0.
+		stopped: make(chan struct{}),
+		send:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(&c.opts)
 	}
 
-	// Add one to the waitgroup to track the write goroutine.
-	l.wg.Add(1)
+	// Without a buffer there is no oldest entry to throw away.
+	if capacity == 0 && c.opts.policy == DropOldest {
+		c.opts.policy = DropNewest
+	}
 
 	// Create the write goroutine that performs the actual
 	// writes to disk.
 	go func() {
 
-		// Range over the channel and write each data received to disk.
+		// Mark that we are done and terminated.
+		defer close(c.stopped)
+
+		// Range over the channel and write each entry received to disk.
 		// Once the channel is close and flushed the loop will terminate.
-		for d := range l.write {
+		var buf bytes.Buffer
+		for e := range c.write {
 
-			// Simulate write to disk.
-			fmt.Fprintln(w, d)
-		}
+			// Everything before a flush marker has been written.
+			if e.flush != nil {
+				close(e.flush)
+				continue
+			}
 
-		// Mark that we are done and terminated.
-		l.wg.Done()
+			buf.Reset()
+			format(&buf, e)
+			if _, err := w.Write(buf.Bytes()); err != nil {
+				atomic.AddUint64(&c.errors, 1)
+			}
+		}
 	}()
 
-	return &l
+	return &Logger{core: &c}
+}
+
+// With returns a logger that adds the key/value pairs to every entry.
+// The new logger shares the buffer of the original one.
+func (l *Logger) With(args ...any) *Logger {
+	fields := make([]Field, 0, len(l.fields)+len(args)/2)
+	fields = append(fields, l.fields...)
+
+	return &Logger{
+		core:   l.core,
+		fields: appendFields(fields, args),
+	}
+}
+
+// Enabled reports whether entries at the level are logged.
+func (l *Logger) Enabled(level Level) bool {
+	return level >= l.opts.level
+}
+
+// Debug logs the message at LevelDebug.
+func (l *Logger) Debug(msg string, args ...any) {
+	l.Log(LevelDebug, msg, args...)
+}
+
+// Info logs the message at LevelInfo.
+func (l *Logger) Info(msg string, args ...any) {
+	l.Log(LevelInfo, msg, args...)
+}
+
+// Warn logs the message at LevelWarn.
+func (l *Logger) Warn(msg string, args ...any) {
+	l.Log(LevelWarn, msg, args...)
+}
+
+// Error logs the message at LevelError.
+func (l *Logger) Error(msg string, args ...any) {
+	l.Log(LevelError, msg, args...)
+}
+
+// Log logs the message at the level. The args are alternating keys and
+// values, or Field values.
+func (l *Logger) Log(level Level, msg string, args ...any) {
+	if !l.Enabled(level) {
+		return
+	}
+
+	fields := make([]Field, 0, len(l.fields)+len(args)/2)
+	fields = append(fields, l.fields...)
+
+	l.enqueue(entry{
+		time:   time.Now(),
+		level:  level,
+		msg:    msg,
+		fields: appendFields(fields, args),
+	})
+}
+
+// Dropped returns the number of entries that have been thrown away.
+func (l *Logger) Dropped() uint64 {
+	return atomic.LoadUint64(&l.dropped)
+}
+
+// WriteErrors returns the number of entries the writer failed to write.
+func (l *Logger) WriteErrors() uint64 {
+	return atomic.LoadUint64(&l.errors)
+}
+
+// Flush waits until every entry logged before the call has been written
+// or the context is canceled.
+func (l *Logger) Flush(ctx context.Context) error {
+
+	// Take our turn to send.
+	select {
+	case l.send <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	// Once the logger is closed, everything is written when the write
+	// goroutine terminates.
+	if l.closed {
+		<-l.send
+		return wait(ctx, l.stopped)
+	}
+
+	// Place a marker behind the entries in the buffer. The marker is
+	// never dropped, so wait for room.
+	done := make(chan struct{})
+	select {
+	case l.write <- entry{flush: done}:
+		<-l.send
+	case <-l.done:
+		<-l.send
+		return wait(ctx, l.stopped)
+	case <-ctx.Done():
+		<-l.send
+		return ctx.Err()
+	}
+
+	return wait(ctx, done)
 }
 
 // Shutdown closes the logger and wait for the writer goroutine
-// to terminate.
+// to terminate. Entries logged after Shutdown are dropped, along with
+// the entries still waiting for room under the Block policy.
 func (l *Logger) Shutdown() {
 
+	// Stop the sends waiting for room, then wait for the sends in
+	// progress to finish.
+	l.once.Do(func() { close(l.done) })
+	l.send <- struct{}{}
+	if l.closed {
+		<-l.send
+		<-l.stopped
+		return
+	}
+	l.closed = true
+
 	// Close the channel which will cause the write goroutine
 	// to finish what is has in its buffer and terminate.
 	close(l.write)
+	<-l.send
 
 	// Wait for the write goroutine to terminate.
-	l.wg.Wait()
+	<-l.stopped
 }
 
-// Write is used to write the data to the log.
-func (l *Logger) Write(data string) {
+// enqueue sends the entry to the write goroutine, applying the overflow
+// policy when the buffer is full.
+func (c *core) enqueue(e entry) {
+	var timeout <-chan time.Time
+	if c.opts.policy == Block && c.opts.timeout > 0 {
+		timer := time.NewTimer(c.opts.timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
 
-	// Perform the channel operations.
+	// Take our turn to send.
 	select {
-	case l.write <- data:
-		// The writing goroutine got it.
+	case c.send <- struct{}{}:
+	case <-timeout:
+		atomic.AddUint64(&c.dropped, 1)
+		return
+	}
+	defer func() { <-c.send }()
 
-	default:
-		// Drop the write.
-		fmt.Println("Dropping the write")
+	if c.closed {
+		atomic.AddUint64(&c.dropped, 1)
+		return
+	}
+
+	for {
+
+		// Perform the channel operations.
+		select {
+		case c.write <- e:
+			// The writing goroutine got it.
+			return
+
+		default:
+		}
+
+		switch c.opts.policy {
+		case DropNewest:
+			atomic.AddUint64(&c.dropped, 1)
+			return
+
+		case DropOldest:
+
+			// Make room by taking the oldest entry, unless the write
+			// goroutine got to it first, and try again.
+			select {
+			case old := <-c.write:
+				if old.flush == nil {
+					atomic.AddUint64(&c.dropped, 1)
+					continue
+				}
+
+				// A flush marker can't be dropped. Moving it to the
+				// back keeps every entry it waits for in front of
+				// it, but takes the room, so drop this entry.
+				c.write <- old
+				atomic.AddUint64(&c.dropped, 1)
+				return
+
+			default:
+			}
+
+		case Block:
+			select {
+			case c.write <- e:
+			case <-timeout:
+				atomic.AddUint64(&c.dropped, 1)
+			case <-c.done:
+				atomic.AddUint64(&c.dropped, 1)
+			}
+			return
+		}
+	}
+}
+
+// wait waits for the channel to be closed or the context to be canceled.
+func wait(ctx context.Context, ch <-chan struct{}) error {
+	select {
+	case <-ch:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
 }
+
+// appendFields converts the args to fields. Args are alternating keys
+// and values, or Field values. A value without a string key is given
+// the key !BADKEY.
+func appendFields(fields []Field, args []any) []Field {
+	for len(args) > 0 {
+		switch key := args[0].(type) {
+		case Field:
+			fields = append(fields, key)
+			args = args[1:]
+
+		case string:
+			if len(args) == 1 {
+				fields = append(fields, Field{Key: "!BADKEY", Value: key})
+				return fields
+			}
+			fields = append(fields, Field{Key: key, Value: args[1]})
+			args = args[2:]
+
+		default:
+			fields = append(fields, Field{Key: "!BADKEY", Value: key})
+			args = args[1:]
+		}
+	}
+
+	return fields
+}
+
+// format writes the entry as a single line of text.
+//
+//	2009-11-10T23:00:00.000Z INFO user logged in id=10 name="Bill Kennedy"
+func format(buf *bytes.Buffer, e entry) {
+
+	// An entry without a time leaves it out.
+	if !e.time.IsZero() {
+		buf.WriteString(e.time.Format("2006-01-02T15:04:05.000Z07:00"))
+		buf.WriteByte(' ')
+	}
+	buf.WriteString(e.level.String())
+	buf.WriteByte(' ')
+	buf.WriteString(e.msg)
+
+	for _, f := range e.fields {
+		buf.WriteByte(' ')
+		buf.WriteString(f.Key)
+		buf.WriteByte('=')
+		buf.WriteString(quote(fmt.Sprint(f.Value)))
+	}
+
+	buf.WriteByte('\n')
+}
+
+// quote quotes the value if it is empty or contains spaces, quotes, an
+// equal sign or characters that can't be printed.
+func quote(s string) string {
+	if s == "" {
+		return `""`
+	}
+
+	for _, r := range s {
+		if r <= ' ' || r == '"' || r == '=' || !strconv.IsPrint(r) {
+			return strconv.Quote(s)
+		}
+	}
+
+	return s
+}
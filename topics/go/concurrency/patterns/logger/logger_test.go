@@ -0,0 +1,371 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/gotraining/topics/go/concurrency/patterns/logger"
+)
+
+const succeed = "\u2713"
+const failed = "\u2717"
+
+// device is a writer that blocks until it is opened, so the buffer of
+// the logger fills up.
+type device struct {
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	once    sync.Once
+	started chan struct{}
+	open    chan struct{}
+}
+
+// newDevice returns a device that is closed.
+func newDevice() *device {
+	return &device{
+		started: make(chan struct{}),
+		open:    make(chan struct{}),
+	}
+}
+
+// Write implements the io.Writer interface.
+func (d *device) Write(p []byte) (int, error) {
+	d.once.Do(func() { close(d.started) })
+	<-d.open
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.buf.Write(p)
+}
+
+// lines returns the lines written without the time.
+func (d *device) lines() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSuffix(d.buf.String(), "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+		_, rest, _ := strings.Cut(line, " ")
+		lines = append(lines, rest)
+	}
+	return lines
+}
+
+// equal reports whether the lines are the same.
+func equal(a, b []string) bool {
+	return strings.Join(a, "\n") == strings.Join(b, "\n")
+}
+
+func TestFormat(t *testing.T) {
+	t.Log("Given the need to log entries with levels and fields.")
+	{
+		d := newDevice()
+		close(d.open)
+
+		l := logger.New(d, 10)
+		defer l.Shutdown()
+
+		ul := l.With("user", "bill")
+		l.Debug("not logged")
+		l.Info("hello", "id", 10, "name", "Bill Kennedy")
+		ul.Warn("disk", logger.Field{Key: "free", Value: 0.5}, "empty", "")
+		ul.Error("failed", "err", errors.New("a=b"), 42)
+		l.Log(logger.LevelWarn+2, "odd", "key")
+
+		if err := l.Flush(context.Background()); err != nil {
+			t.Fatalf("\t%s\tShould be able to flush the logger : %v", failed, err)
+		}
+
+		exp := []string{
+			`INFO hello id=10 name="Bill Kennedy"`,
+			`WARN disk user=bill free=0.5 empty=""`,
+			`ERROR failed user=bill err="a=b" !BADKEY=42`,
+			`WARN+2 odd !BADKEY=key`,
+		}
+		if got := d.lines(); !equal(got, exp) {
+			t.Fatalf("\t%s\tShould write the entries.\n%q\n%q", failed, got, exp)
+		}
+		t.Logf("\t%s\tShould write the entries.", succeed)
+	}
+}
+
+func TestOverflow(t *testing.T) {
+	tt := []struct {
+		name    string
+		opts    []logger.Option
+		exp     []string
+		dropped uint64
+	}{
+		{"drop newest", nil, []string{"0", "1", "2"}, 1},
+		{"drop oldest", []logger.Option{logger.WithOverflow(logger.DropOldest)}, []string{"0", "2", "3"}, 1},
+		{"block", []logger.Option{logger.WithOverflow(logger.Block)}, []string{"0", "1", "2", "3"}, 0},
+		{"block timeout", []logger.Option{logger.WithOverflow(logger.Block), logger.WithBlockTimeout(20 * time.Millisecond)}, []string{"0", "1", "2"}, 1},
+	}
+
+	t.Log("Given the need to handle a full buffer.")
+	{
+		for testID, test := range tt {
+			tf := func(t *testing.T) {
+				t.Logf("\tTest %d:\tWhen using %s.", testID, test.name)
+				{
+					d := newDevice()
+					l := logger.New(d, 2, test.opts...)
+
+					// The write goroutine takes the first entry and blocks
+					// writing it, then the buffer is filled.
+					l.Info("0")
+					<-d.started
+					l.Info("1")
+					l.Info("2")
+
+					logged := make(chan struct{})
+					go func() {
+						l.Info("3")
+						close(logged)
+					}()
+
+					select {
+					case <-logged:
+					case <-time.After(50 * time.Millisecond):
+					}
+					close(d.open)
+					<-logged
+
+					if err := l.Flush(context.Background()); err != nil {
+						t.Fatalf("\t%s\tTest %d:\tShould be able to flush the logger : %v", failed, testID, err)
+					}
+
+					var exp []string
+					for _, msg := range test.exp {
+						exp = append(exp, "INFO "+msg)
+					}
+					if got := d.lines(); !equal(got, exp) {
+						t.Fatalf("\t%s\tTest %d:\tShould write the expected entries : %q", failed, testID, got)
+					}
+					t.Logf("\t%s\tTest %d:\tShould write the expected entries.", succeed, testID)
+
+					if got := l.Dropped(); got != test.dropped {
+						t.Fatalf("\t%s\tTest %d:\tShould count %d dropped entries : %d", failed, testID, test.dropped, got)
+					}
+					t.Logf("\t%s\tTest %d:\tShould count %d dropped entries.", succeed, testID, test.dropped)
+
+					l.Shutdown()
+				}
+			}
+			t.Run(test.name, tf)
+		}
+	}
+}
+
+func TestFlush(t *testing.T) {
+	t.Log("Given the need to wait for entries to be written.")
+	{
+		d := newDevice()
+		l := logger.New(d, 10, logger.WithOverflow(logger.DropOldest))
+
+		for i := 0; i < 5; i++ {
+			l.Info("entry")
+		}
+
+		t.Logf("\tTest 0:\tWhen the writer is blocked.")
+		{
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+
+			if err := l.Flush(ctx); !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("\t%s\tTest 0:\tShould give up when the context is done : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 0:\tShould give up when the context is done.", succeed)
+		}
+
+		t.Logf("\tTest 1:\tWhen flush markers are in a full buffer.")
+		{
+			for i := 0; i < 10; i++ {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+				l.Flush(ctx)
+				cancel()
+			}
+			l.Info("entry")
+
+			close(d.open)
+			if err := l.Flush(context.Background()); err != nil {
+				t.Fatalf("\t%s\tTest 1:\tShould be able to flush the logger : %v", failed, err)
+			}
+			if got := len(d.lines()) + int(l.Dropped()); got != 6 {
+				t.Fatalf("\t%s\tTest 1:\tShould write or drop every entry : %d", failed, got)
+			}
+			t.Logf("\t%s\tTest 1:\tShould write or drop every entry.", succeed)
+		}
+
+		t.Logf("\tTest 2:\tWhen the logger is shut down.")
+		{
+			l.Shutdown()
+			l.Shutdown()
+
+			dropped := l.Dropped()
+			l.Info("late")
+			if l.Dropped() != dropped+1 {
+				t.Fatalf("\t%s\tTest 2:\tShould drop entries logged after the shutdown.", failed)
+			}
+			t.Logf("\t%s\tTest 2:\tShould drop entries logged after the shutdown.", succeed)
+
+			if err := l.Flush(context.Background()); err != nil {
+				t.Fatalf("\t%s\tTest 2:\tShould be able to flush the logger : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 2:\tShould be able to flush the logger.", succeed)
+		}
+	}
+}
+
+func TestUnbuffered(t *testing.T) {
+	t.Log("Given the need to log without a buffer.")
+	{
+		t.Logf("\tTest 0:\tWhen dropping the oldest entry.")
+		{
+			d := newDevice()
+			l := logger.New(d, 0, logger.WithOverflow(logger.DropOldest))
+
+			logged := make(chan struct{})
+			go func() {
+				for i := 0; i < 5; i++ {
+					l.Info("entry")
+				}
+				close(logged)
+			}()
+
+			select {
+			case <-logged:
+			case <-time.After(time.Second):
+				t.Fatalf("\t%s\tTest 0:\tShould drop the new entries.", failed)
+			}
+			t.Logf("\t%s\tTest 0:\tShould drop the new entries.", succeed)
+
+			close(d.open)
+			if err := l.Flush(context.Background()); err != nil {
+				t.Fatalf("\t%s\tTest 0:\tShould be able to flush the logger : %v", failed, err)
+			}
+			if got := len(d.lines()) + int(l.Dropped()); got != 5 {
+				t.Fatalf("\t%s\tTest 0:\tShould write or drop every entry : %d", failed, got)
+			}
+			t.Logf("\t%s\tTest 0:\tShould write or drop every entry.", succeed)
+
+			l.Shutdown()
+		}
+	}
+}
+
+func TestShutdownBlocked(t *testing.T) {
+	t.Log("Given the need to shut down a logger with entries waiting for room.")
+	{
+		t.Logf("\tTest 0:\tWhen the Block policy waits without a timeout.")
+		{
+			d := newDevice()
+			l := logger.New(d, 1, logger.WithOverflow(logger.Block))
+
+			// The first entry blocks the writer and the second one takes
+			// the buffer, so the third one waits for room.
+			l.Info("entry")
+			<-d.started
+			l.Info("entry")
+
+			logged := make(chan struct{})
+			go func() {
+				l.Info("waiting")
+				close(logged)
+			}()
+
+			// Give the entry time to take its turn to send.
+			time.Sleep(20 * time.Millisecond)
+
+			shutdown := make(chan struct{})
+			go func() {
+				l.Shutdown()
+				close(shutdown)
+			}()
+
+			select {
+			case <-logged:
+			case <-time.After(time.Second):
+				t.Fatalf("\t%s\tTest 0:\tShould stop waiting for room on Shutdown.", failed)
+			}
+			t.Logf("\t%s\tTest 0:\tShould stop waiting for room on Shutdown.", succeed)
+
+			close(d.open)
+			select {
+			case <-shutdown:
+			case <-time.After(time.Second):
+				t.Fatalf("\t%s\tTest 0:\tShould shut down once the writer is done.", failed)
+			}
+			t.Logf("\t%s\tTest 0:\tShould shut down once the writer is done.", succeed)
+
+			if got := len(d.lines()); got != 2 || l.Dropped() != 1 {
+				t.Fatalf("\t%s\tTest 0:\tShould write 2 entries and drop 1 : %d %d", failed, got, l.Dropped())
+			}
+			t.Logf("\t%s\tTest 0:\tShould write 2 entries and drop 1.", succeed)
+		}
+	}
+}
+
+// brokenWriter is a writer that fails every write.
+type brokenWriter struct{}
+
+// Write implements the io.Writer interface.
+func (brokenWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("device is broken")
+}
+
+func TestWriteErrors(t *testing.T) {
+	t.Log("Given the need to know when entries can't be written.")
+	{
+		l := logger.New(brokenWriter{}, 10, logger.WithOverflow(logger.Block))
+		for i := 0; i < 3; i++ {
+			l.Info("entry")
+		}
+		l.Shutdown()
+
+		if got := l.WriteErrors(); got != 3 {
+			t.Fatalf("\t%s\tShould count the failed writes : %d", failed, got)
+		}
+		t.Logf("\t%s\tShould count the failed writes.", succeed)
+	}
+}
+
+func TestLevel(t *testing.T) {
+	tt := []struct {
+		level logger.Level
+		exp   string
+	}{
+		{logger.LevelDebug, "DEBUG"},
+		{logger.LevelDebug - 1, "DEBUG-1"},
+		{logger.LevelInfo + 1, "INFO+1"},
+		{logger.LevelWarn, "WARN"},
+		{logger.LevelError + 4, "ERROR+4"},
+	}
+
+	t.Log("Given the need to name the levels.")
+	{
+		for _, test := range tt {
+			if got := test.level.String(); got != test.exp {
+				t.Fatalf("\t%s\tShould name level %d %q : %q", failed, test.level, test.exp, got)
+			}
+			t.Logf("\t%s\tShould name level %d %q.", succeed, test.level, test.exp)
+		}
+
+		d := newDevice()
+		close(d.open)
+		l := logger.New(d, 1, logger.WithLevel(logger.LevelDebug))
+		if !l.Enabled(logger.LevelDebug) || l.Enabled(logger.LevelDebug-1) {
+			t.Fatalf("\t%s\tShould log entries at or above the level.", failed)
+		}
+		t.Logf("\t%s\tShould log entries at or above the level.", succeed)
+		l.Shutdown()
+	}
+}
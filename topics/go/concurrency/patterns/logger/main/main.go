@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"sync/atomic"
 	"time"
 
 	"github.com/ardanlabs/gotraining/topics/go/concurrency/patterns/logger"
@@ -15,12 +16,12 @@ import (
 
 // device allows us to mock a device we write logs to.
 type device struct {
-	problem bool
+	problem atomic.Bool
 }
 
 // Write implements the io.Writer interface.
 func (d *device) Write(p []byte) (n int, err error) {
-	for d.problem {
+	for d.problem.Load() {
 
 		// Simulate disk problems.
 		time.Sleep(time.Second)
@@ -38,13 +39,14 @@ func main() {
 	// Create a logger value with a buffer of capacity
 	// for each goroutine that will be logging.
 	var d device
-	l := logger.New(&d, grs)
+	l := logger.New(&d, grs, logger.WithOverflow(logger.DropOldest))
 
 	// Generate goroutines, each writing to disk.
 	for i := 0; i < grs; i++ {
 		go func(id int) {
+			gl := l.With("id", id)
 			for {
-				l.Write(fmt.Sprintf("%d: log data", id))
+				gl.Info("log data")
 				time.Sleep(10 * time.Millisecond)
 			}
 		}(i)
@@ -60,8 +62,9 @@ func main() {
 	for {
 		<-sigChan
 
-		// I appreciate we have a data race here with the Write
-		// method. Let's keep things simple to show the mechanics.
-		d.problem = !d.problem
+		// Toggle the problem atomically since the Write method
+		// reads it from the write goroutine.
+		d.problem.Store(!d.problem.Load())
+		fmt.Println("Dropped:", l.Dropped())
 	}
 }
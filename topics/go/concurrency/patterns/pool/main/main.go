@@ -6,6 +6,7 @@
 package main
 
 import (
+	"context"
 	"io"
 	"log"
 	"math/rand"
@@ -19,6 +20,7 @@ import (
 const (
 	maxGoroutines = 25 // the number of routines to use.
 	numPooled     = 2  // number of resources in the pool
+	maxOpen       = 5  // number of resources open at the same time
 )
 
 // dbConnection simulates a resource to share.
@@ -49,8 +51,12 @@ func createConnection() (io.Closer, error) {
 // performQueries tests the resource pool of connections.
 func performQueries(query int, p *pool.Pool) {
 
-	// Acquire a connection from the pool.
-	conn, err := p.Acquire()
+	// Acquire a connection from the pool, waiting no more than
+	// a second for one to be released.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	conn, err := p.Acquire(ctx)
 	if err != nil {
 		log.Println(err)
 		return
@@ -69,7 +75,7 @@ func main() {
 	wg.Add(maxGoroutines)
 
 	// Create the pool to manage our connections.
-	p, err := pool.New(numPooled, createConnection)
+	p, err := pool.New(numPooled, createConnection, pool.WithMaxOpen(maxOpen))
 	if err != nil {
 		log.Println(err)
 		return
@@ -91,6 +97,6 @@ func main() {
 	wg.Wait()
 
 	// Close the pool.
-	log.Println("Shutdown Program.")
+	log.Printf("Shutdown Program: %+v\n", p.Stats())
 	p.Close()
 }
@@ -7,90 +7,344 @@
 package pool
 
 import (
+	"context"
 	"errors"
 	"io"
-	"log"
 	"sync"
+	"time"
 )
 
+// ErrPoolClosed is returned when an Acquire returns on a
+// closed pool.
+var ErrPoolClosed = errors.New("Pool has been closed")
+
+// Option configures the behavior of a pool.
+type Option func(*options)
+
+// options holds the settings applied by the Option functions.
+type options struct {
+	maxOpen     int
+	idleTimeout time.Duration
+	maxLifetime time.Duration
+	validate    func(r io.Closer) error
+}
+
+// WithMaxOpen limits the number of resources that are open at the same
+// time, counting the idle ones and the ones in use. Once the limit is
+// reached, Acquire waits for a resource to be released. The default of
+// 0 means there is no limit.
+func WithMaxOpen(n int) Option {
+	return func(o *options) {
+		o.maxOpen = n
+	}
+}
+
+// WithIdleTimeout closes resources that have been idle in the pool for
+// longer than the duration.
+func WithIdleTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.idleTimeout = d
+	}
+}
+
+// WithMaxLifetime closes resources once they have been open for longer
+// than the duration. A resource in use is closed when it is released.
+func WithMaxLifetime(d time.Duration) Option {
+	return func(o *options) {
+		o.maxLifetime = d
+	}
+}
+
+// WithValidate sets a function that checks a resource before it is handed
+// out again. A resource that fails the check is closed and another one is
+// acquired in its place.
+func WithValidate(fn func(r io.Closer) error) Option {
+	return func(o *options) {
+		o.validate = fn
+	}
+}
+
+// Stats describes the state of a pool.
+type Stats struct {
+	MaxOpen int // Maximum number of open resources.
+
+	Open  int // Resources open, in use and idle.
+	InUse int // Resources currently in use.
+	Idle  int // Resources currently idle.
+
+	Waiters      int           // Goroutines currently waiting for a resource.
+	WaitCount    int64         // Total number of times a goroutine had to wait.
+	WaitDuration time.Duration // Total time spent waiting for a resource.
+
+	MaxIdleClosed     int64 // Resources closed because the pool was full.
+	IdleTimeClosed    int64 // Resources closed by the idle timeout.
+	LifetimeClosed    int64 // Resources closed by the maximum lifetime.
+	InvalidatedClosed int64 // Resources closed by Invalidate or validation.
+}
+
+// resource is a resource managed by the pool.
+type resource struct {
+	r        io.Closer
+	created  time.Time
+	returned time.Time
+}
+
 // Pool manages a set of resources that can be shared safely by
 // multiple goroutines. The resource being managed must implement
-// the io.Closer interface.
+// the io.Closer interface and be comparable, like a pointer, so
+// the pool can keep track of it.
 type Pool struct {
-	mu        sync.Mutex
-	resources chan io.Closer
-	factory   func() (io.Closer, error)
-	closed    bool
-}
+	mu      sync.Mutex
+	opts    options
+	factory func() (io.Closer, error)
+	maxIdle int
+	closed  bool
 
-// ErrPoolClosed is returned when an Acquire returns on a
-// closed pool.
-var ErrPoolClosed = errors.New("Pool has been closed")
+	// Every open resource, idle resources in the order they were
+	// released and the goroutines waiting for a resource.
+	open    map[io.Closer]*resource
+	idle    []*resource
+	waiters []chan *resource
+
+	// Reserved counts resources being created by the factory.
+	reserved int
+
+	// Closing holds the resources discarded while the lock is held, so
+	// they are closed once it is released.
+	closing []io.Closer
+
+	stats   Stats
+	cleaner chan struct{}
+}
 
 // New creates a pool that manages resources. A pool requires a
 // function that can allocate a new resource and the size of
-// the pool.
-func New(size uint, f func() (io.Closer, error)) (*Pool, error) {
+// the pool, which is the number of idle resources kept open.
+func New(size uint, f func() (io.Closer, error), opts ...Option) (*Pool, error) {
 	if size == 0 {
 		return nil, errors.New("Size value too small")
 	}
 
-	return &Pool{
-		factory:   f,
-		resources: make(chan io.Closer, size),
-	}, nil
+	p := Pool{
+		factory: f,
+		maxIdle: int(size),
+		open:    make(map[io.Closer]*resource),
+	}
+	for _, opt := range opts {
+		opt(&p.opts)
+	}
+
+	if p.opts.maxOpen < 0 {
+		return nil, errors.New("Max open value too small")
+	}
+
+	// Start a goroutine to close the resources that expire while idle.
+	if interval := p.cleanInterval(); interval > 0 {
+		p.cleaner = make(chan struct{})
+		go p.clean(interval)
+	}
+
+	return &p, nil
+}
+
+// Acquire retrieves a resource from the pool. If the maximum number of
+// resources are open, Acquire waits until one is released or the context
+// is canceled.
+func (p *Pool) Acquire(ctx context.Context) (io.Closer, error) {
+	for {
+		res, err := p.acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		// A nil resource means there is room to open a new one.
+		if res == nil {
+			return p.create()
+		}
+
+		// Check a reused resource is still good before handing it out.
+		if p.opts.validate != nil {
+			if err := p.opts.validate(res.r); err != nil {
+				p.mu.Lock()
+				p.stats.InvalidatedClosed++
+				p.discardLocked(res)
+				p.unlock()
+				continue
+			}
+		}
+
+		return res.r, nil
+	}
 }
 
-// Acquire retrieves a resource	from the pool.
-func (p *Pool) Acquire() (io.Closer, error) {
+// acquire returns an idle resource, or nil when a new resource can be
+// opened, waiting for either when the pool is at its limit.
+func (p *Pool) acquire(ctx context.Context) (*resource, error) {
+	p.mu.Lock()
+
+	if p.closed {
+		p.mu.Unlock()
+		return nil, ErrPoolClosed
+	}
+
+	// Check for a free resource, using the one released last.
+	for len(p.idle) > 0 {
+		res := p.idle[len(p.idle)-1]
+		p.idle = p.idle[:len(p.idle)-1]
+
+		if p.expiredLocked(res, time.Now()) {
+			continue
+		}
+
+		p.unlock()
+		return res, nil
+	}
+
+	// Provide a new resource if the limit allows it.
+	if p.opts.maxOpen == 0 || p.numOpenLocked() < p.opts.maxOpen {
+		p.reserved++
+		p.unlock()
+		return nil, nil
+	}
+
+	// Wait in line for a resource to be released.
+	ch := make(chan *resource, 1)
+	p.waiters = append(p.waiters, ch)
+	p.stats.WaitCount++
+	start := time.Now()
+	p.unlock()
+
 	select {
+	case res, ok := <-ch:
+		p.mu.Lock()
+		p.stats.WaitDuration += time.Since(start)
+		p.mu.Unlock()
 
-	// Check for a free resource.
-	case r, wd := <-p.resources:
-		log.Println("Acquire:", "Shared Resource")
-		if !wd {
+		if !ok {
 			return nil, ErrPoolClosed
 		}
-		return r, nil
+		return res, nil
 
-	// Provide a new resource since there are none available.
-	default:
-		log.Println("Acquire:", "New Resource")
-		return p.factory()
+	case <-ctx.Done():
+		p.mu.Lock()
+		p.stats.WaitDuration += time.Since(start)
+		for i, w := range p.waiters {
+			if w == ch {
+				p.waiters = append(p.waiters[:i], p.waiters[i+1:]...)
+				break
+			}
+		}
+		p.mu.Unlock()
+
+		// A resource may have been handed over before the waiter was
+		// removed, so pass it on.
+		select {
+		case res, ok := <-ch:
+			if ok {
+				p.putOrPass(res)
+			}
+		default:
+		}
+
+		return nil, ctx.Err()
+	}
+}
+
+// create opens a new resource using the reservation made by acquire.
+func (p *Pool) create() (io.Closer, error) {
+	r, err := p.factory()
+
+	p.mu.Lock()
+	defer p.unlock()
+
+	p.reserved--
+
+	if err != nil {
+
+		// The reservation is not used, so a waiter can try instead.
+		p.passLocked(nil)
+		return nil, err
 	}
+
+	if p.closed {
+		p.closing = append(p.closing, r)
+		return nil, ErrPoolClosed
+	}
+
+	now := time.Now()
+	p.open[r] = &resource{r: r, created: now, returned: now}
+	return r, nil
 }
 
-// Release places a new resource onto the pool.
+// Release places a new resource onto the pool. A resource the pool no
+// longer tracks, because it was invalidated, already closed by the pool
+// or not opened by it, is left alone so it is never closed twice.
 func (p *Pool) Release(r io.Closer) {
 
 	// Secure this operation with the Close operation.
 	p.mu.Lock()
-	defer p.mu.Unlock()
+	defer p.unlock()
+
+	res, ok := p.open[r]
+
+	switch {
+
+	// The resource was invalidated or is not open in this pool.
+	case !ok:
 
 	// If the pool is closed, discard the resource.
-	if p.closed {
-		r.Close()
-		return
-	}
+	case p.closed:
+		p.discardLocked(res)
 
-	select {
-	// Attempt to place the new resource on the queue.
-	case p.resources <- r:
-		log.Println("Release:", "In Queue")
+	// If the resource is too old, discard it and make room.
+	case p.opts.maxLifetime > 0 && time.Since(res.created) > p.opts.maxLifetime:
+		p.stats.LifetimeClosed++
+		p.discardLocked(res)
 
-	// If the queue is already at cap we close the resource.
 	default:
-		log.Println("Release:", "Closing")
-		r.Close()
+		p.putLocked(res)
 	}
 }
 
+// Invalidate closes a resource that is broken instead of releasing it
+// back to the pool. Calling Release on the resource afterwards does
+// nothing, so a deferred Release is safe.
+func (p *Pool) Invalidate(r io.Closer) {
+	p.mu.Lock()
+	defer p.unlock()
+
+	res, ok := p.open[r]
+	if !ok {
+		p.closing = append(p.closing, r)
+		return
+	}
+
+	p.stats.InvalidatedClosed++
+	p.discardLocked(res)
+}
+
+// Stats returns the current state of the pool.
+func (p *Pool) Stats() Stats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	s := p.stats
+	s.MaxOpen = p.opts.maxOpen
+	s.Open = len(p.open)
+	s.Idle = len(p.idle)
+	s.InUse = s.Open - s.Idle
+	s.Waiters = len(p.waiters)
+
+	return s
+}
+
 // Close will shutdown the pool and close all existing resources.
+// Resources in use are closed when they are released.
 func (p *Pool) Close() error {
 
 	// Secure this operation with the Release operation.
 	p.mu.Lock()
-	defer p.mu.Unlock()
+	defer p.unlock()
 
 	// If the pool is already close, don't do anything.
 	if p.closed {
@@ -100,14 +354,158 @@ func (p *Pool) Close() error {
 	// Set the pool as closed.
 	p.closed = true
 
-	// Close the channel before we drain the channel of its
-	// resources. If we don't do this, we will have a deadlock.
-	close(p.resources)
+	if p.cleaner != nil {
+		close(p.cleaner)
+	}
+
+	// Wake up every goroutine waiting for a resource.
+	for _, ch := range p.waiters {
+		close(ch)
+	}
+	p.waiters = nil
 
-	// Close the resources
-	for r := range p.resources {
-		r.Close()
+	// Close the idle resources.
+	for _, res := range p.idle {
+		delete(p.open, res.r)
+		p.closing = append(p.closing, res.r)
 	}
+	p.idle = nil
 
 	return nil
 }
+
+// putOrPass returns a resource handed to a waiter that gave up.
+func (p *Pool) putOrPass(res *resource) {
+	p.mu.Lock()
+	defer p.unlock()
+
+	// Give up the reservation to open a resource and pass it on.
+	if res == nil {
+		p.reserved--
+		if !p.closed {
+			p.passLocked(nil)
+		}
+		return
+	}
+
+	if p.closed {
+		p.discardLocked(res)
+		return
+	}
+
+	p.putLocked(res)
+}
+
+// putLocked hands the resource to the first waiter, or keeps it idle if
+// there is room.
+func (p *Pool) putLocked(res *resource) {
+	if p.passLocked(res) {
+		return
+	}
+
+	// If the pool already holds enough idle resources, close it.
+	if len(p.idle) >= p.maxIdle {
+		p.stats.MaxIdleClosed++
+		p.discardLocked(res)
+		return
+	}
+
+	res.returned = time.Now()
+	p.idle = append(p.idle, res)
+}
+
+// passLocked hands the resource to the first waiter and reports whether
+// there was one. A nil resource tells the waiter to open a new one.
+func (p *Pool) passLocked(res *resource) bool {
+	if len(p.waiters) == 0 {
+		return false
+	}
+
+	ch := p.waiters[0]
+	p.waiters = p.waiters[1:]
+
+	if res == nil {
+		p.reserved++
+	}
+	ch <- res
+
+	return true
+}
+
+// discardLocked removes the resource, to be closed by unlock, and lets a
+// waiter open a new one in its place.
+func (p *Pool) discardLocked(res *resource) {
+	delete(p.open, res.r)
+	p.closing = append(p.closing, res.r)
+
+	if !p.closed {
+		p.passLocked(nil)
+	}
+}
+
+// numOpenLocked returns the number of resources open or being opened.
+func (p *Pool) numOpenLocked() int {
+	return len(p.open) + p.reserved
+}
+
+// expiredLocked removes the idle resource, to be closed by unlock, if it
+// has been idle or open for too long and reports whether it did.
+func (p *Pool) expiredLocked(res *resource, now time.Time) bool {
+	switch {
+	case p.opts.idleTimeout > 0 && now.Sub(res.returned) > p.opts.idleTimeout:
+		p.stats.IdleTimeClosed++
+	case p.opts.maxLifetime > 0 && now.Sub(res.created) > p.opts.maxLifetime:
+		p.stats.LifetimeClosed++
+	default:
+		return false
+	}
+
+	delete(p.open, res.r)
+	p.closing = append(p.closing, res.r)
+	return true
+}
+
+// unlock releases the lock and then closes the discarded resources, so a
+// slow Close never holds up the other goroutines using the pool.
+func (p *Pool) unlock() {
+	closing := p.closing
+	p.closing = nil
+	p.mu.Unlock()
+
+	for _, r := range closing {
+		r.Close()
+	}
+}
+
+// cleanInterval returns how often the idle resources are checked.
+func (p *Pool) cleanInterval() time.Duration {
+	interval := p.opts.idleTimeout
+	if p.opts.maxLifetime > 0 && (interval == 0 || p.opts.maxLifetime < interval) {
+		interval = p.opts.maxLifetime
+	}
+
+	return interval / 2
+}
+
+// clean closes the expired idle resources until the pool is closed.
+func (p *Pool) clean(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.cleaner:
+			return
+		case now := <-ticker.C:
+			p.mu.Lock()
+			idle := p.idle[:0]
+			for _, res := range p.idle {
+				if !p.expiredLocked(res, now) {
+					idle = append(idle, res)
+				}
+			}
+			p.idle = idle
+			p.unlock()
+		}
+	}
+}
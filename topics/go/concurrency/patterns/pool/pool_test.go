@@ -0,0 +1,399 @@
+package pool_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/gotraining/topics/go/concurrency/patterns/pool"
+)
+
+const succeed = "\u2713"
+const failed = "\u2717"
+
+// conn is a resource that tracks how many are open.
+type conn struct {
+	id     int32
+	open   *int32
+	closed int32
+	broken bool
+}
+
+// Close implements the io.Closer interface.
+func (c *conn) Close() error {
+	if atomic.AddInt32(&c.closed, 1) == 1 {
+		atomic.AddInt32(c.open, -1)
+	}
+	return nil
+}
+
+// factory returns a factory function and the counter of open resources.
+func factory() (func() (io.Closer, error), *int32) {
+	var ids, open int32
+	return func() (io.Closer, error) {
+		atomic.AddInt32(&open, 1)
+		return &conn{id: atomic.AddInt32(&ids, 1), open: &open}, nil
+	}, &open
+}
+
+func TestMaxOpen(t *testing.T) {
+	t.Log("Given the need to limit the number of open resources.")
+	{
+		f, _ := factory()
+		p, err := pool.New(2, f, pool.WithMaxOpen(2))
+		if err != nil {
+			t.Fatalf("\t%s\tShould be able to create a pool : %v", failed, err)
+		}
+		defer p.Close()
+
+		ctx := context.Background()
+		r1, _ := p.Acquire(ctx)
+		r2, _ := p.Acquire(ctx)
+
+		t.Logf("\tTest 0:\tWhen the pool is at its limit.")
+		{
+			tctx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+			defer cancel()
+
+			if _, err := p.Acquire(tctx); !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("\t%s\tTest 0:\tShould wait until the context is done : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 0:\tShould wait until the context is done.", succeed)
+
+			s := p.Stats()
+			if s.Open != 2 || s.InUse != 2 || s.WaitCount != 1 || s.WaitDuration < 20*time.Millisecond || s.Waiters != 0 {
+				t.Fatalf("\t%s\tTest 0:\tShould report the wait in the stats : %+v", failed, s)
+			}
+			t.Logf("\t%s\tTest 0:\tShould report the wait in the stats.", succeed)
+		}
+
+		t.Logf("\tTest 1:\tWhen a resource is released to a waiter.")
+		{
+			got := make(chan io.Closer)
+			go func() {
+				r, _ := p.Acquire(ctx)
+				got <- r
+			}()
+
+			for p.Stats().Waiters != 1 {
+				time.Sleep(time.Millisecond)
+			}
+			p.Release(r1)
+
+			if r := <-got; r != r1 {
+				t.Fatalf("\t%s\tTest 1:\tShould hand the released resource to the waiter.", failed)
+			}
+			t.Logf("\t%s\tTest 1:\tShould hand the released resource to the waiter.", succeed)
+
+			p.Release(r1)
+			p.Release(r2)
+			if s := p.Stats(); s.Open != 2 || s.Idle != 2 {
+				t.Fatalf("\t%s\tTest 1:\tShould keep the released resources idle : %+v", failed, s)
+			}
+			t.Logf("\t%s\tTest 1:\tShould keep the released resources idle.", succeed)
+		}
+	}
+}
+
+func TestInvalidate(t *testing.T) {
+	t.Log("Given the need to discard broken resources.")
+	{
+		f, open := factory()
+		p, _ := pool.New(1, f,
+			pool.WithMaxOpen(1),
+			pool.WithValidate(func(r io.Closer) error {
+				if r.(*conn).broken {
+					return errors.New("broken")
+				}
+				return nil
+			}),
+		)
+		defer p.Close()
+
+		ctx := context.Background()
+
+		t.Logf("\tTest 0:\tWhen a resource in use is invalidated.")
+		{
+			r1, _ := p.Acquire(ctx)
+
+			got := make(chan io.Closer)
+			go func() {
+				r, _ := p.Acquire(ctx)
+				got <- r
+			}()
+
+			for p.Stats().Waiters != 1 {
+				time.Sleep(time.Millisecond)
+			}
+			p.Invalidate(r1)
+
+			r2 := <-got
+			if r2 == r1 || atomic.LoadInt32(&r1.(*conn).closed) != 1 {
+				t.Fatalf("\t%s\tTest 0:\tShould close the resource and let the waiter open a new one.", failed)
+			}
+			t.Logf("\t%s\tTest 0:\tShould close the resource and let the waiter open a new one.", succeed)
+
+			p.Release(r2)
+		}
+
+		t.Logf("\tTest 1:\tWhen an idle resource fails validation.")
+		{
+			r2, _ := p.Acquire(ctx)
+			r2.(*conn).broken = true
+			p.Release(r2)
+
+			r3, _ := p.Acquire(ctx)
+			if r3 == r2 || atomic.LoadInt32(&r2.(*conn).closed) != 1 {
+				t.Fatalf("\t%s\tTest 1:\tShould close the broken resource and open a new one.", failed)
+			}
+			t.Logf("\t%s\tTest 1:\tShould close the broken resource and open a new one.", succeed)
+
+			p.Release(r3)
+			if s := p.Stats(); s.InvalidatedClosed != 2 || s.Open != 1 || atomic.LoadInt32(open) != 1 {
+				t.Fatalf("\t%s\tTest 1:\tShould count the invalidated resources : %+v", failed, s)
+			}
+			t.Logf("\t%s\tTest 1:\tShould count the invalidated resources.", succeed)
+		}
+
+		t.Logf("\tTest 2:\tWhen an invalidated resource is released.")
+		{
+			r3, _ := p.Acquire(ctx)
+			p.Invalidate(r3)
+			p.Release(r3)
+
+			if n := atomic.LoadInt32(&r3.(*conn).closed); n != 1 {
+				t.Fatalf("\t%s\tTest 2:\tShould close the resource once : %d", failed, n)
+			}
+			t.Logf("\t%s\tTest 2:\tShould close the resource once.", succeed)
+
+			if s := p.Stats(); s.Open != 0 || s.Idle != 0 || s.InvalidatedClosed != 3 {
+				t.Fatalf("\t%s\tTest 2:\tShould not put the resource back in the pool : %+v", failed, s)
+			}
+			t.Logf("\t%s\tTest 2:\tShould not put the resource back in the pool.", succeed)
+		}
+	}
+}
+
+func TestExpire(t *testing.T) {
+	t.Log("Given the need to close resources that are too old.")
+	{
+		ctx := context.Background()
+
+		t.Logf("\tTest 0:\tWhen a resource is idle for too long.")
+		{
+			f, open := factory()
+			p, _ := pool.New(2, f, pool.WithIdleTimeout(20*time.Millisecond))
+			defer p.Close()
+
+			r, _ := p.Acquire(ctx)
+			p.Release(r)
+
+			time.Sleep(80 * time.Millisecond)
+
+			if s := p.Stats(); s.Idle != 0 || s.IdleTimeClosed != 1 || atomic.LoadInt32(open) != 0 {
+				t.Fatalf("\t%s\tTest 0:\tShould close the idle resource : %+v", failed, s)
+			}
+			t.Logf("\t%s\tTest 0:\tShould close the idle resource.", succeed)
+		}
+
+		t.Logf("\tTest 1:\tWhen a resource is open for too long.")
+		{
+			f, open := factory()
+			p, _ := pool.New(2, f, pool.WithMaxLifetime(20*time.Millisecond))
+			defer p.Close()
+
+			r, _ := p.Acquire(ctx)
+			time.Sleep(40 * time.Millisecond)
+			p.Release(r)
+
+			if s := p.Stats(); s.Open != 0 || s.LifetimeClosed != 1 || atomic.LoadInt32(open) != 0 {
+				t.Fatalf("\t%s\tTest 1:\tShould close the resource when it is released : %+v", failed, s)
+			}
+			t.Logf("\t%s\tTest 1:\tShould close the resource when it is released.", succeed)
+		}
+	}
+}
+
+func TestClose(t *testing.T) {
+	t.Log("Given the need to close a pool.")
+	{
+		f, open := factory()
+		p, _ := pool.New(1, f, pool.WithMaxOpen(1))
+
+		ctx := context.Background()
+		r, _ := p.Acquire(ctx)
+
+		errs := make(chan error)
+		go func() {
+			_, err := p.Acquire(ctx)
+			errs <- err
+		}()
+		for p.Stats().Waiters != 1 {
+			time.Sleep(time.Millisecond)
+		}
+
+		t.Logf("\tTest 0:\tWhen closing a pool with waiters.")
+		{
+			if err := p.Close(); err != nil {
+				t.Fatalf("\t%s\tTest 0:\tShould be able to close the pool : %v", failed, err)
+			}
+			if err := <-errs; !errors.Is(err, pool.ErrPoolClosed) {
+				t.Fatalf("\t%s\tTest 0:\tShould wake the waiter with an error : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 0:\tShould wake the waiter with an error.", succeed)
+
+			if _, err := p.Acquire(ctx); !errors.Is(err, pool.ErrPoolClosed) {
+				t.Fatalf("\t%s\tTest 0:\tShould not acquire from a closed pool : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 0:\tShould not acquire from a closed pool.", succeed)
+
+			p.Release(r)
+			if atomic.LoadInt32(open) != 0 {
+				t.Fatalf("\t%s\tTest 0:\tShould close resources released after the pool is closed.", failed)
+			}
+			t.Logf("\t%s\tTest 0:\tShould close resources released after the pool is closed.", succeed)
+		}
+	}
+}
+
+func TestConcurrent(t *testing.T) {
+	const maxOpen = 3
+
+	t.Log("Given the need to share resources between many goroutines.")
+	{
+		var mu sync.Mutex
+		var live, peak int
+		f := func() (io.Closer, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			live++
+			if live > peak {
+				peak = live
+			}
+			return &tracked{mu: &mu, live: &live}, nil
+		}
+
+		p, _ := pool.New(2, f, pool.WithMaxOpen(maxOpen))
+
+		var wg sync.WaitGroup
+		var timeouts int32
+		for g := 0; g < 50; g++ {
+			wg.Add(1)
+			go func(g int) {
+				defer wg.Done()
+				for i := 0; i < 20; i++ {
+					ctx, cancel := context.WithTimeout(context.Background(), time.Duration(g%5)*time.Millisecond)
+					r, err := p.Acquire(ctx)
+					cancel()
+					if err != nil {
+						atomic.AddInt32(&timeouts, 1)
+						continue
+					}
+
+					switch i % 7 {
+					case 0:
+						p.Invalidate(r)
+					default:
+						p.Release(r)
+					}
+				}
+			}(g)
+		}
+		wg.Wait()
+		p.Close()
+
+		if peak > maxOpen {
+			t.Fatalf("\t%s\tShould never have more than %d resources open : %d", failed, maxOpen, peak)
+		}
+		t.Logf("\t%s\tShould never have more than %d resources open.", succeed, maxOpen)
+
+		if live != 0 {
+			t.Fatalf("\t%s\tShould close every resource : %d open", failed, live)
+		}
+		t.Logf("\t%s\tShould close every resource, %d acquires timed out.", succeed, timeouts)
+	}
+}
+
+// tracked is a resource that keeps count of the live resources.
+type tracked struct {
+	mu   *sync.Mutex
+	live *int
+}
+
+// Close implements the io.Closer interface.
+func (tr *tracked) Close() error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	*tr.live--
+	return nil
+}
+
+// statsCloser is a resource that reads the stats of its pool when it is
+// closed, which only works when the pool doesn't hold its lock.
+type statsCloser struct {
+	p      **pool.Pool
+	closed chan struct{}
+}
+
+// Close implements the io.Closer interface.
+func (sc *statsCloser) Close() error {
+	(*sc.p).Stats()
+	close(sc.closed)
+	return nil
+}
+
+func TestCloseUnlocked(t *testing.T) {
+	t.Log("Given the need to close resources that use the pool.")
+	{
+		var p *pool.Pool
+		f := func() (io.Closer, error) {
+			return &statsCloser{p: &p, closed: make(chan struct{})}, nil
+		}
+
+		// wait fails when the resource is not closed, as the pool would
+		// wait on itself if it closed the resource holding its lock.
+		wait := func(testID int, what string, r io.Closer) {
+			t.Helper()
+			select {
+			case <-r.(*statsCloser).closed:
+			case <-time.After(time.Second):
+				t.Fatalf("\t%s\tTest %d:\tShould close the resource %s without holding the lock.", failed, testID, what)
+			}
+			t.Logf("\t%s\tTest %d:\tShould close the resource %s without holding the lock.", succeed, testID, what)
+		}
+
+		ctx := context.Background()
+		p, _ = pool.New(1, f, pool.WithIdleTimeout(20*time.Millisecond))
+
+		t.Logf("\tTest 0:\tWhen resources are discarded while the pool is open.")
+		{
+			r1, _ := p.Acquire(ctx)
+			go p.Invalidate(r1)
+			wait(0, "on Invalidate", r1)
+
+			r2, _ := p.Acquire(ctx)
+			r3, _ := p.Acquire(ctx)
+			p.Release(r2)
+			go p.Release(r3)
+			wait(0, "when the pool is full", r3)
+
+			wait(0, "when it has been idle too long", r2)
+		}
+
+		t.Logf("\tTest 1:\tWhen the pool is closed.")
+		{
+			r4, _ := p.Acquire(ctx)
+			r5, _ := p.Acquire(ctx)
+			p.Release(r4)
+			go p.Close()
+			wait(1, "on Close", r4)
+
+			go p.Release(r5)
+			wait(1, "released after Close", r5)
+		}
+	}
+}
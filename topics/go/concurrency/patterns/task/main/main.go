@@ -6,6 +6,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"sync"
 	"time"
@@ -68,4 +69,33 @@ func main() {
 	// Shutdown the task pool and wait for all existing work
 	// to be completed.
 	t.Shutdown()
+
+	// Use a runner when the work produces a value.
+	runNames(routines)
+}
+
+// runNames uses a runner to measure the length of each name and
+// receives the results in the order the names were submitted.
+func runNames(routines int) {
+	r := task.NewRunner[int](routines)
+	defer r.Shutdown()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	jobs := make([]task.Job[int], len(names))
+	for i, name := range names {
+		name := name
+		jobs[i] = func(ctx context.Context) (int, error) {
+			return len(name), nil
+		}
+	}
+
+	for res := range r.Ordered(ctx, jobs...) {
+		if res.Err != nil {
+			log.Println(names[res.Index], res.Err)
+			continue
+		}
+		log.Println(names[res.Index], res.Value)
+	}
 }
@@ -0,0 +1,280 @@
+// All material is licensed under the Apache License Version 2.0, January 2004
+// http://www.apache.org/licenses/LICENSE-2.0
+
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// ErrShutdown is returned when work is submitted to a task or runner
+// that has been shut down.
+var ErrShutdown = errors.New("Pool has been shut down")
+
+// Job is a unit of work that produces a value. The context is the
+// one provided when the job was submitted.
+type Job[R any] func(ctx context.Context) (R, error)
+
+// Result is the outcome of a job. Index is the position of the job
+// in the list of jobs that was submitted.
+type Result[R any] struct {
+	Index int
+	Value R
+	Err   error
+}
+
+// PanicError is the error reported for a job that panicked.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+// Error implements the error interface.
+func (pe *PanicError) Error() string {
+	return fmt.Sprintf("job panicked: %v", pe.Value)
+}
+
+// submission is a job on its way to a worker along with the
+// channel the worker sends the result on.
+type submission[R any] struct {
+	ctx    context.Context
+	job    Job[R]
+	result chan Result[R]
+}
+
+// Runner provides a pool of goroutines that execute jobs and return
+// their results. Unlike Task, submitting work respects a context and
+// fails once the runner is shut down instead of blocking forever.
+type Runner[R any] struct {
+	work chan submission[R]
+	quit chan struct{}
+	done chan struct{}
+	wg   sync.WaitGroup
+
+	// Secure changes to the size of the pool.
+	mu       sync.Mutex
+	size     int
+	shutdown bool
+}
+
+// NewRunner creates a runner with the specified number of goroutines.
+// A size less than 1 creates a runner with a single goroutine.
+func NewRunner[R any](size int) *Runner[R] {
+	if size < 1 {
+		size = 1
+	}
+
+	r := Runner[R]{
+
+		// Using unbuffered channels because we want the guarantee
+		// of knowing the work being submitted is actually being
+		// worked on after the call to Submit returns.
+		work: make(chan submission[R]),
+		quit: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	r.grow(size)
+
+	return &r
+}
+
+// Size returns the number of goroutines in the pool.
+func (r *Runner[R]) Size() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.size
+}
+
+// Resize changes the number of goroutines in the pool, which must have
+// at least one goroutine. When the pool shrinks, Resize returns right
+// away and goroutines stop as they finish the job they are working on.
+// The new size is reported by Size right away.
+func (r *Runner[R]) Resize(size int) error {
+	if size < 1 {
+		return errors.New("Size value too small")
+	}
+
+	r.mu.Lock()
+
+	if r.shutdown {
+		r.mu.Unlock()
+		return ErrShutdown
+	}
+
+	if size >= r.size {
+		r.grow(size - r.size)
+		r.mu.Unlock()
+		return nil
+	}
+
+	stop := r.size - size
+	r.size = size
+	r.mu.Unlock()
+
+	// Only goroutines waiting for work can receive the signal, so send
+	// it from another goroutine. A job calling Resize would otherwise
+	// wait on itself when every other goroutine is busy.
+	go func() {
+		for i := 0; i < stop; i++ {
+			select {
+			case r.quit <- struct{}{}:
+			case <-r.done:
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// grow adds goroutines to the pool.
+func (r *Runner[R]) grow(n int) {
+	r.size += n
+
+	r.wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func() {
+			defer r.wg.Done()
+			for {
+				select {
+				case s := <-r.work:
+					s.result <- run(s.ctx, s.job)
+				case <-r.quit:
+					return
+				case <-r.done:
+					return
+				}
+			}
+		}()
+	}
+}
+
+// run executes the job, turning a panic into an error so the goroutine
+// can go on working.
+func run[R any](ctx context.Context, job Job[R]) (res Result[R]) {
+	defer func() {
+		if v := recover(); v != nil {
+			res.Err = &PanicError{Value: v, Stack: debug.Stack()}
+		}
+	}()
+
+	res.Value, res.Err = job(ctx)
+	return res
+}
+
+// Submit hands the job to a goroutine in the pool and returns a channel
+// that receives the result. Submit blocks until a goroutine accepts the
+// job, the context is canceled or the runner is shut down.
+func (r *Runner[R]) Submit(ctx context.Context, job Job[R]) (<-chan Result[R], error) {
+	s := submission[R]{
+		ctx:    ctx,
+		job:    job,
+		result: make(chan Result[R], 1),
+	}
+
+	// Check for a shutdown first, since the select below picks at
+	// random when more than one case is ready.
+	select {
+	case <-r.done:
+		return nil, ErrShutdown
+	default:
+	}
+
+	select {
+	case r.work <- s:
+		return s.result, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-r.done:
+		return nil, ErrShutdown
+	}
+}
+
+// Ordered submits the jobs and returns a channel that receives their
+// results in the order the jobs were provided. Jobs that can't be
+// submitted report the error from Submit. The channel is closed after
+// the last result.
+func (r *Runner[R]) Ordered(ctx context.Context, jobs ...Job[R]) <-chan Result[R] {
+	pending := make(chan (<-chan Result[R]), len(jobs))
+	go r.submitAll(ctx, jobs, pending)
+
+	out := make(chan Result[R], len(jobs))
+	go func() {
+		defer close(out)
+
+		idx := 0
+		for ch := range pending {
+			res := <-ch
+			res.Index = idx
+			out <- res
+			idx++
+		}
+	}()
+
+	return out
+}
+
+// Completed submits the jobs and returns a channel that receives their
+// results as soon as each job is done. Use the Index field to match a
+// result with its job. The channel is closed after the last result.
+func (r *Runner[R]) Completed(ctx context.Context, jobs ...Job[R]) <-chan Result[R] {
+	pending := make(chan (<-chan Result[R]), len(jobs))
+	go r.submitAll(ctx, jobs, pending)
+
+	out := make(chan Result[R], len(jobs))
+	go func() {
+		var wg sync.WaitGroup
+
+		idx := 0
+		for ch := range pending {
+			wg.Add(1)
+			go func(idx int, ch <-chan Result[R]) {
+				defer wg.Done()
+				res := <-ch
+				res.Index = idx
+				out <- res
+			}(idx, ch)
+			idx++
+		}
+
+		wg.Wait()
+		close(out)
+	}()
+
+	return out
+}
+
+// submitAll submits each job in order and sends the channel for its
+// result on pending, which is closed when all the jobs are submitted.
+func (r *Runner[R]) submitAll(ctx context.Context, jobs []Job[R], pending chan<- (<-chan Result[R])) {
+	defer close(pending)
+
+	for _, job := range jobs {
+		ch, err := r.Submit(ctx, job)
+		if err != nil {
+			failed := make(chan Result[R], 1)
+			failed <- Result[R]{Err: err}
+			ch = failed
+		}
+		pending <- ch
+	}
+}
+
+// Shutdown stops the runner from accepting more work and waits for
+// the goroutines to finish the jobs they are working on.
+func (r *Runner[R]) Shutdown() {
+	r.mu.Lock()
+	if !r.shutdown {
+		r.shutdown = true
+		close(r.done)
+	}
+	r.mu.Unlock()
+
+	r.wg.Wait()
+}
@@ -0,0 +1,365 @@
+package task_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/gotraining/topics/go/concurrency/patterns/task"
+)
+
+const succeed = "\u2713"
+const failed = "\u2717"
+
+// square returns a job that squares the value after sleeping for the
+// specified duration.
+func square(v int, d time.Duration) task.Job[int] {
+	return func(ctx context.Context) (int, error) {
+		time.Sleep(d)
+		return v * v, nil
+	}
+}
+
+func TestOrdered(t *testing.T) {
+	t.Log("Given the need to receive results in the order jobs are submitted.")
+	{
+		r := task.NewRunner[int](4)
+		defer r.Shutdown()
+
+		errJob := errors.New("bad job")
+		jobs := []task.Job[int]{
+			square(1, 30*time.Millisecond),
+			square(2, 20*time.Millisecond),
+			func(ctx context.Context) (int, error) { return 0, errJob },
+			square(4, 0),
+		}
+
+		t.Logf("\tTest 0:\tWhen the later jobs finish first.")
+		{
+			var got []task.Result[int]
+			for res := range r.Ordered(context.Background(), jobs...) {
+				got = append(got, res)
+			}
+
+			if len(got) != len(jobs) {
+				t.Fatalf("\t%s\tTest 0:\tShould receive a result for every job : %d", failed, len(got))
+			}
+			t.Logf("\t%s\tTest 0:\tShould receive a result for every job.", succeed)
+
+			for i, res := range got {
+				if res.Index != i {
+					t.Fatalf("\t%s\tTest 0:\tShould receive the results in order : %+v", failed, got)
+				}
+			}
+			t.Logf("\t%s\tTest 0:\tShould receive the results in order.", succeed)
+
+			if got[0].Value != 1 || got[1].Value != 4 || !errors.Is(got[2].Err, errJob) || got[3].Value != 16 {
+				t.Fatalf("\t%s\tTest 0:\tShould receive the values and errors : %+v", failed, got)
+			}
+			t.Logf("\t%s\tTest 0:\tShould receive the values and errors.", succeed)
+		}
+	}
+}
+
+func TestCompleted(t *testing.T) {
+	t.Log("Given the need to receive results as soon as jobs are done.")
+	{
+		r := task.NewRunner[int](3)
+		defer r.Shutdown()
+
+		jobs := []task.Job[int]{
+			square(1, 60*time.Millisecond),
+			square(2, 30*time.Millisecond),
+			square(3, 0),
+		}
+
+		t.Logf("\tTest 0:\tWhen the later jobs finish first.")
+		{
+			var idx []int
+			for res := range r.Completed(context.Background(), jobs...) {
+				if res.Value != (res.Index+1)*(res.Index+1) {
+					t.Fatalf("\t%s\tTest 0:\tShould match each result with its job : %+v", failed, res)
+				}
+				idx = append(idx, res.Index)
+			}
+			t.Logf("\t%s\tTest 0:\tShould match each result with its job.", succeed)
+
+			if len(idx) != 3 || idx[0] != 2 || idx[1] != 1 || idx[2] != 0 {
+				t.Fatalf("\t%s\tTest 0:\tShould receive the results as they complete : %v", failed, idx)
+			}
+			t.Logf("\t%s\tTest 0:\tShould receive the results as they complete.", succeed)
+		}
+	}
+}
+
+func TestPanic(t *testing.T) {
+	t.Log("Given the need to recover from a job that panics.")
+	{
+		r := task.NewRunner[int](1)
+		defer r.Shutdown()
+
+		ctx := context.Background()
+
+		t.Logf("\tTest 0:\tWhen a job panics.")
+		{
+			ch, err := r.Submit(ctx, func(ctx context.Context) (int, error) {
+				panic("boom")
+			})
+			if err != nil {
+				t.Fatalf("\t%s\tTest 0:\tShould be able to submit the job : %v", failed, err)
+			}
+
+			res := <-ch
+			var pe *task.PanicError
+			if !errors.As(res.Err, &pe) || pe.Value != "boom" || len(pe.Stack) == 0 {
+				t.Fatalf("\t%s\tTest 0:\tShould report the panic as an error : %v", failed, res.Err)
+			}
+			t.Logf("\t%s\tTest 0:\tShould report the panic as an error.", succeed)
+
+			ch, err = r.Submit(ctx, square(3, 0))
+			if err != nil || (<-ch).Value != 9 {
+				t.Fatalf("\t%s\tTest 0:\tShould keep the goroutine working : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 0:\tShould keep the goroutine working.", succeed)
+		}
+	}
+}
+
+func TestCancel(t *testing.T) {
+	t.Log("Given the need to stop submitting work.")
+	{
+		r := task.NewRunner[int](1)
+
+		block := make(chan struct{})
+		ch, _ := r.Submit(context.Background(), func(ctx context.Context) (int, error) {
+			<-block
+			return 1, nil
+		})
+
+		t.Logf("\tTest 0:\tWhen every goroutine is busy.")
+		{
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+
+			if _, err := r.Submit(ctx, square(2, 0)); !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("\t%s\tTest 0:\tShould give up when the context is done : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 0:\tShould give up when the context is done.", succeed)
+
+			var errs int
+			for res := range r.Ordered(ctx, square(1, 0), square(2, 0)) {
+				if errors.Is(res.Err, context.DeadlineExceeded) {
+					errs++
+				}
+			}
+			if errs != 2 {
+				t.Fatalf("\t%s\tTest 0:\tShould report the jobs that were not submitted : %d", failed, errs)
+			}
+			t.Logf("\t%s\tTest 0:\tShould report the jobs that were not submitted.", succeed)
+		}
+
+		t.Logf("\tTest 1:\tWhen the runner is shut down.")
+		{
+			done := make(chan struct{})
+			go func() {
+				r.Shutdown()
+				close(done)
+			}()
+
+			// Wait for the shutdown to start before submitting.
+			for {
+				if err := r.Resize(1); errors.Is(err, task.ErrShutdown) {
+					break
+				}
+				time.Sleep(time.Millisecond)
+			}
+
+			if _, err := r.Submit(context.Background(), square(2, 0)); !errors.Is(err, task.ErrShutdown) {
+				t.Fatalf("\t%s\tTest 1:\tShould not block on a runner that is shut down : %v", failed, err)
+			}
+			t.Logf("\t%s\tTest 1:\tShould not block on a runner that is shut down.", succeed)
+
+			close(block)
+			<-done
+			if res := <-ch; res.Value != 1 {
+				t.Fatalf("\t%s\tTest 1:\tShould finish the running job : %+v", failed, res)
+			}
+			t.Logf("\t%s\tTest 1:\tShould finish the running job.", succeed)
+		}
+	}
+}
+
+func TestResize(t *testing.T) {
+	t.Log("Given the need to change the number of goroutines.")
+	{
+		r := task.NewRunner[int](1)
+		defer r.Shutdown()
+
+		var running, peak int32
+		job := func(ctx context.Context) (int, error) {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			return 0, nil
+		}
+
+		jobs := make([]task.Job[int], 8)
+		for i := range jobs {
+			jobs[i] = job
+		}
+
+		t.Logf("\tTest 0:\tWhen the pool grows.")
+		{
+			if err := r.Resize(4); err != nil || r.Size() != 4 {
+				t.Fatalf("\t%s\tTest 0:\tShould be able to grow the pool : %v", failed, err)
+			}
+			for range r.Completed(context.Background(), jobs...) {
+			}
+			if p := atomic.LoadInt32(&peak); p != 4 {
+				t.Fatalf("\t%s\tTest 0:\tShould run 4 jobs at a time : %d", failed, p)
+			}
+			t.Logf("\t%s\tTest 0:\tShould run 4 jobs at a time.", succeed)
+		}
+
+		t.Logf("\tTest 1:\tWhen the pool shrinks.")
+		{
+			atomic.StoreInt32(&peak, 0)
+			if err := r.Resize(2); err != nil || r.Size() != 2 {
+				t.Fatalf("\t%s\tTest 1:\tShould be able to shrink the pool : %v", failed, err)
+			}
+			for range r.Completed(context.Background(), jobs...) {
+			}
+			if p := atomic.LoadInt32(&peak); p != 2 {
+				t.Fatalf("\t%s\tTest 1:\tShould run 2 jobs at a time : %d", failed, p)
+			}
+			t.Logf("\t%s\tTest 1:\tShould run 2 jobs at a time.", succeed)
+		}
+
+		t.Logf("\tTest 2:\tWhen the pool shrinks while every goroutine is busy.")
+		{
+			started := make(chan struct{}, 2)
+			release := make(chan struct{})
+			busy := func(ctx context.Context) (int, error) {
+				started <- struct{}{}
+				<-release
+				return r.Size(), nil
+			}
+
+			results := r.Completed(context.Background(), busy, busy)
+			<-started
+			<-started
+
+			resized := make(chan error, 1)
+			go func() {
+				for r.Size() == 2 {
+					time.Sleep(time.Millisecond)
+				}
+				resized <- nil
+			}()
+			go func() {
+				if err := r.Resize(1); err != nil {
+					resized <- err
+				}
+			}()
+
+			select {
+			case err := <-resized:
+				if err != nil {
+					t.Fatalf("\t%s\tTest 2:\tShould be able to shrink the pool : %v", failed, err)
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("\t%s\tTest 2:\tShould report the new size right away.", failed)
+			}
+			t.Logf("\t%s\tTest 2:\tShould report the new size right away.", succeed)
+
+			close(release)
+			for i := 0; i < 2; i++ {
+				select {
+				case res := <-results:
+					if res.Err != nil || res.Value != 1 {
+						t.Fatalf("\t%s\tTest 2:\tShould let the jobs call Size : %d %v", failed, res.Value, res.Err)
+					}
+				case <-time.After(time.Second):
+					t.Fatalf("\t%s\tTest 2:\tShould let the jobs call Size.", failed)
+				}
+			}
+			t.Logf("\t%s\tTest 2:\tShould let the jobs call Size.", succeed)
+		}
+
+		t.Logf("\tTest 3:\tWhen a job shrinks the pool while the other goroutine is busy.")
+		{
+			if err := r.Resize(2); err != nil {
+				t.Fatalf("\t%s\tTest 3:\tShould be able to grow the pool : %v", failed, err)
+			}
+
+			started := make(chan struct{})
+			release := make(chan struct{})
+			busy := func(ctx context.Context) (int, error) {
+				close(started)
+				<-release
+				return 0, nil
+			}
+			shrink := func(ctx context.Context) (int, error) {
+				<-started
+				return 1, r.Resize(1)
+			}
+
+			results := r.Completed(context.Background(), busy, shrink)
+			select {
+			case res := <-results:
+				if res.Err != nil || res.Value != 1 {
+					t.Fatalf("\t%s\tTest 3:\tShould be able to shrink the pool : %d %v", failed, res.Value, res.Err)
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("\t%s\tTest 3:\tShould not wait for the busy goroutine.", failed)
+			}
+			t.Logf("\t%s\tTest 3:\tShould not wait for the busy goroutine.", succeed)
+
+			close(release)
+			<-results
+		}
+
+		t.Logf("\tTest 4:\tWhen the pool is resized to no goroutines.")
+		{
+			for _, size := range []int{0, -1} {
+				if err := r.Resize(size); err == nil || r.Size() != 1 {
+					t.Fatalf("\t%s\tTest 4:\tShould reject a size of %d : %v", failed, size, err)
+				}
+			}
+			t.Logf("\t%s\tTest 4:\tShould reject a size less than one.", succeed)
+		}
+	}
+}
+
+func TestNewRunnerSize(t *testing.T) {
+	t.Log("Given the need to create a runner with an invalid size.")
+	{
+		for testID, size := range []int{0, -1} {
+			t.Logf("\tTest %d:\tWhen the size is %d.", testID, size)
+			{
+				r := task.NewRunner[int](size)
+				if r.Size() != 1 {
+					t.Fatalf("\t%s\tTest %d:\tShould create a single goroutine : %d", failed, testID, r.Size())
+				}
+
+				ch, err := r.Submit(context.Background(), square(3, 0))
+				if err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to submit a job : %v", failed, testID, err)
+				}
+				if res := <-ch; res.Value != 9 {
+					t.Fatalf("\t%s\tTest %d:\tShould run the job : %d", failed, testID, res.Value)
+				}
+				r.Shutdown()
+				t.Logf("\t%s\tTest %d:\tShould create a single goroutine.", succeed, testID)
+			}
+		}
+	}
+}
@@ -16,6 +16,8 @@ type Worker interface {
 // tasks that are submitted.
 type Task struct {
 	work chan Worker
+	done chan struct{}
+	once sync.Once
 	wg   sync.WaitGroup
 }
 
@@ -27,6 +29,7 @@ func New(maxGoroutines int) *Task {
 		// guarantee of knowing the work being submitted is
 		// actually being worked on after the call to Run returns.
 		work: make(chan Worker),
+		done: make(chan struct{}),
 	}
 
 	// The goroutines are the pool. So we could add code
@@ -35,23 +38,46 @@ func New(maxGoroutines int) *Task {
 	t.wg.Add(maxGoroutines)
 	for i := 0; i < maxGoroutines; i++ {
 		go func() {
-			for w := range t.work {
-				w.Work()
+			defer t.wg.Done()
+			for {
+				select {
+				case w := <-t.work:
+					w.Work()
+				case <-t.done:
+					return
+				}
 			}
-			t.wg.Done()
 		}()
 	}
 
 	return &t
 }
 
-// Shutdown waits for all the goroutines to shutdown.
+// Shutdown waits for all the goroutines to shutdown. Calling Shutdown
+// more than once does nothing.
 func (t *Task) Shutdown() {
-	close(t.work)
+	t.once.Do(func() {
+		close(t.done)
+	})
 	t.wg.Wait()
 }
 
-// Do submits work to the pool.
-func (t *Task) Do(w Worker) {
-	t.work <- w
+// Do submits work to the pool. Do blocks until a goroutine accepts the
+// work, or returns ErrShutdown once the pool is shut down.
+func (t *Task) Do(w Worker) error {
+
+	// Check for a shutdown first, since the select below picks at
+	// random when more than one case is ready.
+	select {
+	case <-t.done:
+		return ErrShutdown
+	default:
+	}
+
+	select {
+	case t.work <- w:
+		return nil
+	case <-t.done:
+		return ErrShutdown
+	}
 }
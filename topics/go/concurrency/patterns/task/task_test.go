@@ -0,0 +1,60 @@
+package task_test
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/gotraining/topics/go/concurrency/patterns/task"
+)
+
+// counter is a Worker that counts the number of times it was run.
+type counter struct {
+	n *int32
+}
+
+func (c counter) Work() {
+	atomic.AddInt32(c.n, 1)
+}
+
+func TestTaskShutdown(t *testing.T) {
+	t.Log("Given the need to submit work to a pool that has been shut down.")
+	{
+		t.Logf("\tTest 0:\tWhen work is submitted before and after Shutdown.")
+		{
+			var n int32
+			tk := task.New(2)
+
+			for i := 0; i < 10; i++ {
+				if err := tk.Do(counter{&n}); err != nil {
+					t.Fatalf("\t%s\tTest 0:\tShould be able to submit work : %v", failed, err)
+				}
+			}
+			tk.Shutdown()
+
+			if got := atomic.LoadInt32(&n); got != 10 {
+				t.Fatalf("\t%s\tTest 0:\tShould run all the work : %d", failed, got)
+			}
+			t.Logf("\t%s\tTest 0:\tShould run all the work.", succeed)
+
+			errs := make(chan error, 1)
+			go func() {
+				errs <- tk.Do(counter{&n})
+			}()
+
+			select {
+			case err := <-errs:
+				if !errors.Is(err, task.ErrShutdown) {
+					t.Fatalf("\t%s\tTest 0:\tShould get ErrShutdown : %v", failed, err)
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("\t%s\tTest 0:\tShould not block after Shutdown.", failed)
+			}
+			t.Logf("\t%s\tTest 0:\tShould get ErrShutdown without blocking.", succeed)
+
+			tk.Shutdown()
+			t.Logf("\t%s\tTest 0:\tShould be able to call Shutdown again.", succeed)
+		}
+	}
+}